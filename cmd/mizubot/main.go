@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log"
 	"os"
@@ -14,12 +15,19 @@ import (
 	"mizubot-go/internal/config"
 	"mizubot-go/internal/db"
 	"mizubot-go/internal/guildinstructions"
+	"mizubot-go/internal/httpserver"
+	"mizubot-go/internal/leader"
+	"mizubot-go/internal/lifecycle"
 	"mizubot-go/internal/llm"
 	llmtools "mizubot-go/internal/llm/tools"
 	"mizubot-go/internal/llmstats"
+	"mizubot-go/internal/memories"
+	"mizubot-go/internal/metrics"
+	"mizubot-go/internal/modelroutes"
 	"mizubot-go/internal/pagemonitor"
 	"mizubot-go/internal/reminders"
 	"mizubot-go/internal/scheduler"
+	"mizubot-go/internal/threadsummaries"
 	"mizubot-go/internal/usersettings"
 )
 
@@ -61,6 +69,7 @@ func main() {
 	}
 
 	var publisher animefeed.Publisher
+	var calendarPublisher reminders.CalendarPublisher
 	if cfg.S3AccessKey != "" && cfg.S3SecretKey != "" && cfg.S3Bucket != "" && cfg.S3Region != "" {
 		s3Publisher, err := animefeed.NewS3Publisher(ctx, animefeed.S3PublisherConfig{
 			AccessKey:         cfg.S3AccessKey,
@@ -74,6 +83,7 @@ func main() {
 			log.Fatalf("s3 publisher init error: %v", err)
 		}
 		publisher = s3Publisher
+		calendarPublisher = s3Publisher
 	}
 
 	animeService := animefeed.NewService(database, publisher, cfg.AnimeFeedURL)
@@ -82,12 +92,54 @@ func main() {
 	monitorService := pagemonitor.NewService(monitorStore)
 	llmStatsStore := llmstats.NewStore(database)
 
+	memoryStore := memories.NewStore(database)
 	allTools := append(llmtools.NewReminderTools(reminderService, userSettingsService), llmtools.NewUserSettingsTools(userSettingsService)...)
-	llmService := llm.NewServiceWithGuildInstructionProvider(llm.NewOllamaClient(llm.OllamaConfig{
-		BaseURL: cfg.OllamaBaseURL,
-		Model:   cfg.OllamaModel,
-		Timeout: cfg.OllamaTimeout,
-	}), guildInstructionStore, allTools...)
+	allTools = append(allTools, llmtools.NewMemoryTools(memoryStore)...)
+	promptBudget := llm.PromptBudget{
+		ContextWindow:       cfg.LLMContextWindow,
+		ModelContextWindows: cfg.LLMContextWindows,
+		CompletionReserve:   cfg.LLMCompletionReserve,
+	}
+	primaryModel := cfg.OllamaModel
+	newCompleter := func(model string) llm.Completer {
+		return llm.NewOllamaClient(llm.OllamaConfig{
+			BaseURL:       cfg.OllamaBaseURL,
+			Model:         model,
+			ContextWindow: promptBudget.Window(model),
+			Timeout:       cfg.OllamaTimeout,
+		})
+	}
+	if cfg.LLMProvider == "openai" {
+		primaryModel = cfg.OpenAIModel
+		newCompleter = func(model string) llm.Completer {
+			return llm.NewOpenAIClient(llm.OpenAIConfig{
+				BaseURL: cfg.OpenAIBaseURL,
+				APIKey:  cfg.OpenAIAPIKey,
+				Model:   model,
+				Timeout: cfg.OpenAITimeout,
+			})
+		}
+	}
+	modelRouteStore := modelroutes.NewStore(database)
+	if err := modelroutes.Seed(ctx, modelRouteStore, cfg.LLMModelRoutes); err != nil {
+		log.Fatalf("model route seed error: %v", err)
+	}
+	router := llm.NewRouter(llm.RouterConfig{
+		Models:        append([]string{primaryModel}, cfg.LLMFallbackModels...),
+		NewCompleter:  newCompleter,
+		Routes:        modelRouteStore,
+		FallbackAfter: cfg.LLMFallbackAfter,
+	})
+	llmService := llm.NewServiceWithGuildInstructionProvider(router.Default(), guildInstructionStore, allTools...)
+	llmService.SetRouter(router)
+	llmService.SetMemoryProvider(memoryStore)
+	llmService.SetPromptBudget(promptBudget)
+	if cfg.OllamaEmbedModel != "" {
+		llmService.SetEmbedder(llm.NewOllamaEmbedder(llm.OllamaConfig{
+			BaseURL: cfg.OllamaBaseURL,
+			Model:   cfg.OllamaEmbedModel,
+		}), cfg.LLMToolSimilarity)
+	}
 
 	discordBot, err := bot.New(cfg.DiscordToken, store, animeService, monitorService, llmService, userSettingsService, llmStatsStore)
 	if err != nil {
@@ -95,7 +147,38 @@ func main() {
 	}
 	// Enable dry-run if requested (no actual sends)
 	discordBot.SetDryRun(cfg.DryRun)
+	reminderService.SetChannelAccess(discordBot.ChannelAccess())
 	discordBot.SetDebugHistory(cfg.LLMDebugHistory)
+	reminderFeeds := reminders.NewFeedService(store, calendarPublisher)
+	discordBot.SetReminderFeeds(reminderFeeds)
+	discordBot.SetMemoryStore(memoryStore)
+	discordBot.SetThreadSummaries(threadsummaries.NewStore(database))
+
+	// Started before the gateway connects so /healthz answers while
+	// /readyz still reports not-ready.
+	if cfg.HTTPAddr != "" {
+		httpServer := httpserver.New(cfg.HTTPAddr, metrics.Default,
+			httpserver.Check{Name: "discord", Run: func(context.Context) error {
+				if !discordBot.Connected() {
+					return errors.New("gateway not connected")
+				}
+				return nil
+			}},
+			httpserver.Check{Name: "database", Run: database.PingContext},
+			httpserver.Check{Name: "migrations", Run: func(ctx context.Context) error {
+				return db.MigrationsCurrent(ctx, database, "./db/migrations")
+			}},
+		)
+		if err := httpServer.Start(); err != nil {
+			log.Fatalf("http server error: %v", err)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+		}()
+		log.Printf("HTTP health and metrics listening on %s", cfg.HTTPAddr)
+	}
 
 	if err := discordBot.Open(); err != nil {
 		log.Fatalf("discord open error: %v", err)
@@ -113,14 +196,42 @@ func main() {
 		}
 	}
 
-	sched := scheduler.New(store, discordBot.SendReminder, cfg.TickInterval)
-	sched.Start(ctx)
+	// startWork runs the scheduler and pollers. With leader election it
+	// runs once per term, so components are built fresh each time.
+	startWork := func(ctx context.Context) lifecycle.Stopper {
+		sched := scheduler.New(store, discordBot.SendReminder, cfg.TickInterval)
+		sched.SetDeadLetterSender(discordBot.SendDeadReminderNotice)
+		sched.Start(ctx)
 
-	animePoller := animefeed.NewPoller(animeService, discordBot, cfg.AnimePollInterval)
-	animePoller.Start(ctx)
+		animePoller := animefeed.NewPoller(animeService, discordBot, cfg.AnimePollInterval)
+		animePoller.Start(ctx)
 
-	monitorPoller := pagemonitor.NewPoller(monitorService, discordBot, cfg.TickInterval)
-	monitorPoller.Start(ctx)
+		monitorPoller := pagemonitor.NewPoller(monitorService, discordBot, cfg.TickInterval)
+		monitorPoller.Start(ctx)
+
+		stoppers := []lifecycle.Stopper{sched, animePoller, monitorPoller}
+		if reminderFeeds.Available() {
+			feedPoller := reminders.NewFeedPoller(reminderFeeds, 0)
+			feedPoller.Start(ctx)
+			stoppers = append(stoppers, feedPoller)
+		}
+		return lifecycle.StopFunc(func(ctx context.Context) error {
+			return lifecycle.StopAll(ctx, stoppers...)
+		})
+	}
+
+	var work lifecycle.Stopper
+	if cfg.LeaderElection {
+		elector := leader.NewElector(leader.NewStore(database), "scheduler", leader.DefaultHolder(), leader.DefaultTTL, startWork)
+		// The standby stays connected but leaves mentions, slash commands
+		// and buttons to the leader so they aren't handled twice.
+		discordBot.SetReplyGate(elector.Leading)
+		elector.Start(ctx)
+		work = elector
+	} else {
+		work = startWork(ctx)
+	}
+	stoppers := []lifecycle.Stopper{lifecycle.StopFunc(discordBot.StopReplies), work}
 
 	log.Printf("MizuBot is running. Reminder tick: %s. Anime poll: %s", cfg.TickInterval.String(), cfg.AnimePollInterval.String())
 
@@ -129,6 +240,17 @@ func main() {
 	<-sigCh
 	log.Println("Shutting down...")
 
-	// Give scheduler a moment to finish current cycle
-	time.Sleep(500 * time.Millisecond)
+	// Let the current scheduler tick, poller cycles and LLM replies finish
+	// before the Discord session and database close. Whatever is still
+	// running at the deadline is abandoned.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if err := lifecycle.StopAll(shutdownCtx, stoppers...); err != nil {
+		log.Printf("shutdown did not drain in time: %v", err)
+	}
 }
+
+// shutdownTimeout bounds how long shutdown waits for in-flight work. It is
+// shorter than the 60s LLM reply timeout so a very slow reply can't hold up
+// a deploy.
+const shutdownTimeout = 20 * time.Second
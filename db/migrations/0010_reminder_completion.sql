@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN completed_at INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave completed_at in place on down migration.
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    reminder_id INTEGER NOT NULL,
+    scheduled_for INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminder_deliveries_reminder_scheduled
+ON reminder_deliveries(reminder_id, scheduled_for);
+
+CREATE INDEX IF NOT EXISTS idx_reminder_deliveries_updated
+ON reminder_deliveries(updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reminder_deliveries_updated;
+DROP INDEX IF EXISTS idx_reminder_deliveries_reminder_scheduled;
+DROP TABLE IF EXISTS reminder_deliveries;
+-- +goose StatementEnd
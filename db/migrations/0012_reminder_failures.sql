@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN failure_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reminders ADD COLUMN retry_at INTEGER;
+ALTER TABLE reminders ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE reminders ADD COLUMN dead_at INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave failure columns in place on down migration.
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN catch_up TEXT NOT NULL DEFAULT 'once';
+ALTER TABLE reminders ADD COLUMN missed_runs INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reminder_deliveries ADD COLUMN missed_runs INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave catch-up columns in place on down migration.
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE reminders ADD COLUMN paused_until INTEGER;
+ALTER TABLE page_monitors ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE page_monitors ADD COLUMN paused_until INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave pause columns in place on down migration.
+-- +goose StatementEnd
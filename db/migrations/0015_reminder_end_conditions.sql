@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN ends_at INTEGER;
+ALTER TABLE reminders ADD COLUMN remaining_runs INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave end condition columns in place on down migration.
+-- +goose StatementEnd
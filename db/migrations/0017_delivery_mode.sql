@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN delivery_mode TEXT NOT NULL DEFAULT 'channel';
+ALTER TABLE reminder_deliveries ADD COLUMN delivered_via TEXT NOT NULL DEFAULT '';
+ALTER TABLE reminder_deliveries ADD COLUMN fallback_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN delivery_mode TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave delivery mode columns in place on down migration.
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE reminders ADD COLUMN shared INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS reminder_subscribers (
+    reminder_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (reminder_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reminder_subscribers;
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave reminders.shared in place on down migration.
+-- +goose StatementEnd
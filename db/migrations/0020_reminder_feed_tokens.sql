@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reminder_feed_tokens (
+    user_id TEXT PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    created_at INTEGER NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reminder_feed_tokens;
+-- +goose StatementEnd
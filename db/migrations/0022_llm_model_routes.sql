@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS llm_model_routes (
+    scope_id TEXT NOT NULL PRIMARY KEY,
+    models TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+ALTER TABLE llm_message_logs ADD COLUMN model TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS llm_model_routes;
+-- SQLite cannot drop columns in older versions used by this project.
+-- Leave llm_message_logs.model in place on down migration.
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS llm_thread_summaries (
+    root_id TEXT NOT NULL PRIMARY KEY,
+    channel_id TEXT NOT NULL,
+    through_message_id TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_thread_summaries_through ON llm_thread_summaries(through_message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS llm_thread_summaries;
+-- +goose StatementEnd
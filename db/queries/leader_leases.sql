@@ -0,0 +1,13 @@
+-- name: AcquireLeaderLease :execrows
+-- Takes the lease when it is free, expired or already held by holder, and
+-- extends it; affects no rows while another holder's lease is live.
+INSERT INTO leader_leases(name, holder, expires_at, renewed_at)
+VALUES(?, ?, ?, ?)
+ON CONFLICT(name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at, renewed_at = excluded.renewed_at
+WHERE leader_leases.holder = excluded.holder OR leader_leases.expires_at <= excluded.renewed_at;
+
+-- name: ReleaseLeaderLease :execrows
+DELETE FROM leader_leases WHERE name = ? AND holder = ?;
+
+-- name: GetLeaderLease :one
+SELECT name, holder, expires_at, renewed_at FROM leader_leases WHERE name = ?;
@@ -0,0 +1,22 @@
+-- name: CreateLLMMemory :one
+INSERT INTO llm_memories(user_id, guild_id, content, created_at)
+VALUES(?, ?, ?, ?)
+RETURNING id, user_id, guild_id, content, created_at;
+
+-- name: ListLLMMemoriesByUser :many
+SELECT id, user_id, guild_id, content, created_at
+FROM llm_memories
+WHERE user_id = ?
+ORDER BY id;
+
+-- name: ListLLMMemoriesForGuild :many
+SELECT id, user_id, guild_id, content, created_at
+FROM llm_memories
+WHERE user_id = ? AND (guild_id = '' OR guild_id = ?)
+ORDER BY id;
+
+-- name: DeleteLLMMemory :execrows
+DELETE FROM llm_memories WHERE id = ? AND user_id = ?;
+
+-- name: DeleteLLMMemoriesByUser :execrows
+DELETE FROM llm_memories WHERE user_id = ?;
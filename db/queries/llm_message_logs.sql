@@ -12,13 +12,14 @@ INSERT INTO llm_message_logs(
     latency_ms,
     status,
     error,
+    model,
     created_at
 )
-VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at;
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at, model;
 
 -- name: ListLLMMessageLogsByGuild :many
-SELECT id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at
+SELECT id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at, model
 FROM llm_message_logs
 WHERE guild_id = ?
 ORDER BY created_at DESC
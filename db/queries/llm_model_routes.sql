@@ -0,0 +1,17 @@
+-- name: GetLLMModelRoute :one
+SELECT scope_id, models, created_at, updated_at
+FROM llm_model_routes
+WHERE scope_id = ?;
+
+-- name: UpsertLLMModelRoute :exec
+INSERT INTO llm_model_routes(scope_id, models, created_at, updated_at)
+VALUES(?, ?, ?, ?)
+ON CONFLICT(scope_id) DO UPDATE SET models = excluded.models, updated_at = excluded.updated_at;
+
+-- name: DeleteLLMModelRoute :execrows
+DELETE FROM llm_model_routes WHERE scope_id = ?;
+
+-- name: ListLLMModelRoutes :many
+SELECT scope_id, models, created_at, updated_at
+FROM llm_model_routes
+ORDER BY scope_id;
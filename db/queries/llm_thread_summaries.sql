@@ -0,0 +1,12 @@
+-- name: GetThreadSummaryByThrough :one
+SELECT root_id, channel_id, through_message_id, summary, created_at, updated_at
+FROM llm_thread_summaries
+WHERE through_message_id = ?;
+
+-- name: UpsertThreadSummary :exec
+INSERT INTO llm_thread_summaries(root_id, channel_id, through_message_id, summary, created_at, updated_at)
+VALUES(?, ?, ?, ?, ?, ?)
+ON CONFLICT(root_id) DO UPDATE SET
+    through_message_id = excluded.through_message_id,
+    summary = excluded.summary,
+    updated_at = excluded.updated_at;
@@ -1,18 +1,18 @@
 -- name: CreatePageMonitor :one
 INSERT INTO page_monitors(user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at)
 VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at;
+RETURNING id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until;
 
 -- name: ListPageMonitorsByUser :many
-SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until
 FROM page_monitors
 WHERE user_id = ?
 ORDER BY created_at ASC;
 
 -- name: ListDuePageMonitors :many
-SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until
 FROM page_monitors
-WHERE next_check <= ?
+WHERE next_check <= ? AND paused = 0
 ORDER BY next_check ASC
 LIMIT ?;
 
@@ -21,3 +21,13 @@ UPDATE page_monitors SET last_status = ?, content_hash = ?, last_content = ?, ne
 
 -- name: DeletePageMonitor :execrows
 DELETE FROM page_monitors WHERE id = ? AND user_id = ?;
+
+-- name: PausePageMonitor :execrows
+UPDATE page_monitors SET paused = 1, paused_until = ?, updated_at = ? WHERE id = ? AND user_id = ?;
+
+-- name: ResumePageMonitor :execrows
+UPDATE page_monitors SET paused = 0, paused_until = NULL, next_check = ?, updated_at = ? WHERE id = ? AND user_id = ? AND paused = 1;
+
+-- name: ResumeExpiredPageMonitors :execrows
+UPDATE page_monitors SET paused = 0, paused_until = NULL, updated_at = ?
+WHERE paused = 1 AND paused_until IS NOT NULL AND paused_until <= ?;
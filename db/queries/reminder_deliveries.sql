@@ -0,0 +1,28 @@
+-- name: ClaimReminderDelivery :one
+INSERT INTO reminder_deliveries(reminder_id, scheduled_for, status, attempts, error, created_at, updated_at, missed_runs)
+VALUES(?, ?, 'claimed', 1, '', ?, ?, ?)
+ON CONFLICT(reminder_id, scheduled_for) DO UPDATE
+SET status = 'claimed', attempts = reminder_deliveries.attempts + 1, error = '', missed_runs = excluded.missed_runs, updated_at = excluded.updated_at
+WHERE reminder_deliveries.status = 'failed' OR (reminder_deliveries.status = 'claimed' AND reminder_deliveries.updated_at < sqlc.arg(stale_before))
+RETURNING id, reminder_id, scheduled_for, status, attempts, error, created_at, updated_at, missed_runs, delivered_via, fallback_reason;
+
+-- name: GetReminderDelivery :one
+SELECT id, reminder_id, scheduled_for, status, attempts, error, created_at, updated_at, missed_runs, delivered_via, fallback_reason
+FROM reminder_deliveries
+WHERE reminder_id = ? AND scheduled_for = ?;
+
+-- name: SetReminderDeliveryStatus :exec
+UPDATE reminder_deliveries SET status = ?, error = ?, updated_at = ? WHERE id = ?;
+
+-- name: FinishReminderDelivery :exec
+UPDATE reminder_deliveries SET status = 'sent', error = '', delivered_via = ?, fallback_reason = ?, updated_at = ? WHERE id = ?;
+
+-- name: ListReminderDeliveries :many
+SELECT id, reminder_id, scheduled_for, status, attempts, error, created_at, updated_at, missed_runs, delivered_via, fallback_reason
+FROM reminder_deliveries
+WHERE reminder_id = ?
+ORDER BY scheduled_for DESC
+LIMIT ?;
+
+-- name: DeleteReminderDeliveriesBefore :execrows
+DELETE FROM reminder_deliveries WHERE updated_at < ?;
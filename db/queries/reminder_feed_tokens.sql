@@ -0,0 +1,13 @@
+-- name: GetReminderFeedToken :one
+SELECT token FROM reminder_feed_tokens WHERE user_id = ?;
+
+-- name: UpsertReminderFeedToken :exec
+INSERT INTO reminder_feed_tokens(user_id, token, created_at)
+VALUES(?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET token = excluded.token, created_at = excluded.created_at;
+
+-- name: DeleteReminderFeedToken :execrows
+DELETE FROM reminder_feed_tokens WHERE user_id = ?;
+
+-- name: ListReminderFeedTokens :many
+SELECT user_id, token FROM reminder_feed_tokens ORDER BY user_id;
@@ -0,0 +1,16 @@
+-- name: AddReminderSubscriber :execrows
+INSERT INTO reminder_subscribers(reminder_id, user_id, created_at)
+VALUES(?, ?, ?)
+ON CONFLICT(reminder_id, user_id) DO NOTHING;
+
+-- name: RemoveReminderSubscriber :execrows
+DELETE FROM reminder_subscribers WHERE reminder_id = ? AND user_id = ?;
+
+-- name: ListReminderSubscribers :many
+SELECT user_id
+FROM reminder_subscribers
+WHERE reminder_id = ?
+ORDER BY created_at ASC, user_id ASC;
+
+-- name: DeleteOrphanReminderSubscribers :execrows
+DELETE FROM reminder_subscribers WHERE reminder_id NOT IN (SELECT id FROM reminders);
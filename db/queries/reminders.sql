@@ -1,23 +1,23 @@
 -- name: CreateReminder :one
-INSERT INTO reminders(user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at)
-VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at;
+INSERT INTO reminders(user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, catch_up, next_run, created_at, updated_at, ends_at, remaining_runs, mentions, delivery_mode, shared)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared;
 
 -- name: ListByUser :many
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
-WHERE user_id = ?
+WHERE user_id = ? AND completed_at IS NULL
 ORDER BY next_run ASC;
 
 -- name: ListDue :many
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
-WHERE next_run <= ?
+WHERE next_run <= ? AND (retry_at IS NULL OR retry_at <= ?) AND completed_at IS NULL AND dead_at IS NULL AND paused = 0
 ORDER BY next_run ASC
 LIMIT ?;
 
 -- name: GetOwned :one
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
 WHERE id = ? AND user_id = ?;
 
@@ -28,4 +28,54 @@ DELETE FROM reminders WHERE id = ? AND user_id = ?;
 DELETE FROM reminders WHERE id = ?;
 
 -- name: SetNextRun :exec
-UPDATE reminders SET next_run = ?, updated_at = ? WHERE id = ?;
+UPDATE reminders SET next_run = ?, missed_runs = 0, failure_count = 0, retry_at = NULL, last_error = '', updated_at = ? WHERE id = ?;
+
+-- name: CountReminderRun :exec
+UPDATE reminders
+SET run_count = run_count + 1, remaining_runs = CASE WHEN remaining_runs IS NULL THEN NULL ELSE remaining_runs - 1 END
+WHERE id = ?;
+
+-- name: CompleteReminder :exec
+UPDATE reminders SET completed_at = ?, updated_at = ? WHERE id = ?;
+
+-- name: SnoozeOwned :execrows
+UPDATE reminders SET next_run = ?, completed_at = NULL, failure_count = 0, retry_at = NULL, last_error = '', dead_at = NULL, updated_at = ? WHERE id = ? AND user_id = ?;
+
+-- name: DeleteCompletedBefore :execrows
+DELETE FROM reminders WHERE completed_at IS NOT NULL AND completed_at < ?;
+
+-- name: GetDueNextRun :one
+SELECT next_run FROM reminders WHERE id = ? AND completed_at IS NULL;
+
+-- name: RecordReminderFailure :exec
+UPDATE reminders SET failure_count = ?, retry_at = ?, last_error = ?, dead_at = ?, updated_at = ? WHERE id = ?;
+
+-- name: CatchUpReminder :execrows
+UPDATE reminders SET next_run = ?, missed_runs = ?, updated_at = ?
+WHERE id = ? AND next_run = ? AND completed_at IS NULL;
+
+-- name: UpdateOwned :execrows
+UPDATE reminders
+SET channel_id = ?, message = ?, schedule = ?, at_time = ?, cron_expr = ?, once = ?, timezone = ?, next_run = ?, completed_at = ?,
+    failure_count = 0, retry_at = NULL, last_error = '', dead_at = NULL, missed_runs = 0, updated_at = ?
+WHERE id = ? AND user_id = ?;
+
+-- name: PauseOwned :execrows
+UPDATE reminders SET paused = 1, paused_until = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND completed_at IS NULL;
+
+-- name: ResumeReminder :execrows
+UPDATE reminders SET paused = 0, paused_until = NULL, next_run = ?, missed_runs = 0, updated_at = ?
+WHERE id = ? AND paused = 1;
+
+-- name: ListPausedUntil :many
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
+FROM reminders
+WHERE paused = 1 AND paused_until IS NOT NULL AND paused_until <= ? AND completed_at IS NULL
+ORDER BY paused_until ASC
+LIMIT ?;
+
+-- name: GetSharedReminder :one
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
+FROM reminders
+WHERE id = ? AND shared = 1 AND completed_at IS NULL;
@@ -1,5 +1,5 @@
 -- name: GetUserSettings :one
-SELECT user_id, timezone, created_at, updated_at
+SELECT user_id, timezone, created_at, updated_at, delivery_mode
 FROM user_settings
 WHERE user_id = ?;
 
@@ -9,4 +9,12 @@ VALUES(?, ?, ?, ?)
 ON CONFLICT(user_id) DO UPDATE SET
     timezone = excluded.timezone,
     updated_at = excluded.updated_at
-RETURNING user_id, timezone, created_at, updated_at;
+RETURNING user_id, timezone, created_at, updated_at, delivery_mode;
+
+-- name: UpsertUserDeliveryMode :one
+INSERT INTO user_settings(user_id, timezone, delivery_mode, created_at, updated_at)
+VALUES(?, '', ?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET
+    delivery_mode = excluded.delivery_mode,
+    updated_at = excluded.updated_at
+RETURNING user_id, timezone, created_at, updated_at, delivery_mode;
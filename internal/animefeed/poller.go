@@ -4,12 +4,16 @@ import (
 	"context"
 	"log"
 	"time"
+
+	"mizubot-go/internal/lifecycle"
+	"mizubot-go/internal/metrics"
 )
 
 type Poller struct {
 	service  *Service
 	notifier Notifier
 	every    time.Duration
+	loop     lifecycle.Loop
 }
 
 func NewPoller(service *Service, notifier Notifier, every time.Duration) *Poller {
@@ -20,18 +24,16 @@ func NewPoller(service *Service, notifier Notifier, every time.Duration) *Poller
 }
 
 func (p *Poller) Start(ctx context.Context) {
-	ticker := time.NewTicker(p.every)
-	go func() {
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if _, err := p.service.Sync(ctx, p.notifier); err != nil {
-					log.Printf("anime poller error: %v", err)
-				}
-			}
+	p.loop.Start(ctx, p.every, func(ctx context.Context) {
+		start := time.Now()
+		if _, err := p.service.Sync(ctx, p.notifier); err != nil {
+			log.Printf("anime poller error: %v", err)
 		}
-	}()
+		metrics.PollerTickSeconds.ObserveSince(start, "anime")
+	})
+}
+
+// Stop stops polling and waits for a sync in progress to finish.
+func (p *Poller) Stop(ctx context.Context) error {
+	return p.loop.Stop(ctx)
 }
@@ -53,16 +53,18 @@ func NewS3Publisher(ctx context.Context, cfg S3PublisherConfig) (*S3Publisher, e
 }
 
 func (p *S3Publisher) PublishUserFeed(ctx context.Context, userID string, body string) (string, error) {
-	key := fmt.Sprintf("%s.xml", userID)
-	if p.prefix != "" {
-		key = path.Join(p.prefix, key)
-	}
+	return p.PublishObject(ctx, fmt.Sprintf("%s.xml", userID), "application/rss+xml", []byte(body))
+}
 
+// PublishObject uploads body under name, relative to the configured prefix,
+// and returns its public location.
+func (p *S3Publisher) PublishObject(ctx context.Context, name, contentType string, body []byte) (string, error) {
+	key := p.objectKey(name)
 	_, err := p.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:       &p.bucket,
 		Key:          &key,
-		Body:         bytes.NewReader([]byte(body)),
-		ContentType:  stringPtr("application/rss+xml"),
+		Body:         bytes.NewReader(body),
+		ContentType:  stringPtr(contentType),
 		CacheControl: stringPtr("public, max-age=300, stale-while-revalidate=60"),
 	})
 	if err != nil {
@@ -72,6 +74,20 @@ func (p *S3Publisher) PublishUserFeed(ctx context.Context, userID string, body s
 	return p.userFeedURLForKey(key), nil
 }
 
+// DeleteObject removes an object previously stored with PublishObject.
+func (p *S3Publisher) DeleteObject(ctx context.Context, name string) error {
+	key := p.objectKey(name)
+	_, err := p.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &p.bucket, Key: &key})
+	return err
+}
+
+func (p *S3Publisher) objectKey(name string) string {
+	if p.prefix != "" {
+		return path.Join(p.prefix, name)
+	}
+	return name
+}
+
 func stringPtr(v string) *string { return &v }
 
 func (p *S3Publisher) UserFeedURL(userID string) string {
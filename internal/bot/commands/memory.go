@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"mizubot-go/internal/memories"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const memoryEmbedColor = 0x9B59B6
+
+type MemoryModule struct {
+	store *memories.Store
+}
+
+func NewMemoryModule(store *memories.Store) *MemoryModule {
+	return &MemoryModule{store: store}
+}
+
+func (m *MemoryModule) Definitions() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		{
+			Name:        "memory",
+			Description: "See or wipe what MizuBot remembers about you",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "Show everything MizuBot remembers about you",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "clear",
+					Description: "Forget everything MizuBot remembers about you",
+				},
+			},
+		},
+	}
+}
+
+func (m *MemoryModule) Handle(responder Responder, _ *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if i.ApplicationCommandData().Name != "memory" {
+		return false
+	}
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		responder.Respond(i, "Missing memory subcommand.", true)
+		return true
+	}
+	switch options[0].Name {
+	case "list":
+		m.handleList(responder, i)
+	case "clear":
+		m.handleClear(responder, i)
+	default:
+		responder.Respond(i, "Unknown memory subcommand.", true)
+	}
+	return true
+}
+
+func (m *MemoryModule) handleList(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user.", true)
+		return
+	}
+
+	list, err := m.store.List(context.Background(), userID)
+	if err != nil {
+		log.Printf("list memories error: %v", err)
+		responder.Respond(i, "Failed to load memories.", true)
+		return
+	}
+	embed := &discordgo.MessageEmbed{
+		Title: "Memories",
+		Color: memoryEmbedColor,
+	}
+	if len(list) == 0 {
+		embed.Description = "I don't remember anything about you yet."
+		responder.RespondEmbed(i, embed, true)
+		return
+	}
+	var b strings.Builder
+	for _, memory := range list {
+		scope := ""
+		if memory.GuildID != "" {
+			scope = " (this server only)"
+			if memory.GuildID != i.GuildID {
+				scope = " (another server only)"
+			}
+		}
+		fmt.Fprintf(&b, "`#%d` %s%s\n", memory.ID, memory.Content, scope)
+	}
+	// Discord caps embed descriptions at 4096 characters.
+	embed.Description = truncateText(strings.TrimSpace(b.String()), 4096)
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: "Ask me to forget one, or use /memory clear to wipe them all."}
+	responder.RespondEmbed(i, embed, true)
+}
+
+func (m *MemoryModule) handleClear(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user.", true)
+		return
+	}
+
+	n, err := m.store.Clear(context.Background(), userID)
+	if err != nil {
+		log.Printf("clear memories error: %v", err)
+		responder.Respond(i, "Failed to clear memories.", true)
+		return
+	}
+	responder.RespondEmbed(i, &discordgo.MessageEmbed{
+		Title:       "Memories Cleared",
+		Color:       memoryEmbedColor,
+		Description: fmt.Sprintf("Forgot %d memories.", n),
+	}, true)
+}
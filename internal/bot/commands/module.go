@@ -9,9 +9,26 @@ import "github.com/bwmarrin/discordgo"
 type Responder interface {
 	Respond(i *discordgo.InteractionCreate, content string, ephemeral bool)
 	RespondEmbed(i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, ephemeral bool)
+	// RespondFile responds with a message and a single attached file.
+	RespondFile(i *discordgo.InteractionCreate, content string, file *discordgo.File, ephemeral bool)
+	// UpdateComponentMessage replaces the content of the message a clicked
+	// component belongs to and removes its components.
+	UpdateComponentMessage(i *discordgo.InteractionCreate, content string)
+	// DeferResponse acknowledges the interaction right away, for handlers
+	// that may take longer than Discord's three seconds to answer. The
+	// answer is then sent with EditResponse.
+	DeferResponse(i *discordgo.InteractionCreate, ephemeral bool)
+	EditResponse(i *discordgo.InteractionCreate, content string)
 }
 
 type Module interface {
 	Definitions() []*discordgo.ApplicationCommand
 	Handle(responder Responder, s *discordgo.Session, i *discordgo.InteractionCreate) bool
 }
+
+// ComponentHandler is implemented by modules that attach message components
+// (buttons) to messages and handle clicks on them. It returns false when the
+// component belongs to another module.
+type ComponentHandler interface {
+	HandleComponent(responder Responder, s *discordgo.Session, i *discordgo.InteractionCreate) bool
+}
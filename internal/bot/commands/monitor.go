@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"mizubot-go/internal/pagemonitor"
+	"mizubot-go/internal/reminders"
+	"mizubot-go/internal/usersettings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 type MonitorModule struct {
-	service *pagemonitor.Service
+	service         *pagemonitor.Service
+	settingsService *usersettings.Service
 }
 
-func NewMonitorModule(service *pagemonitor.Service) *MonitorModule {
-	return &MonitorModule{service: service}
+func NewMonitorModule(service *pagemonitor.Service, settingsService ...*usersettings.Service) *MonitorModule {
+	var settings *usersettings.Service
+	if len(settingsService) > 0 {
+		settings = settingsService[0]
+	}
+	return &MonitorModule{service: service, settingsService: settings}
 }
 
 func (m *MonitorModule) Definitions() []*discordgo.ApplicationCommand {
@@ -68,6 +76,38 @@ func (m *MonitorModule) Definitions() []*discordgo.ApplicationCommand {
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "pause",
+					Description: "Stop checking a monitor without removing it",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "id",
+							Description: "Monitor ID (from /monitor list)",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "until",
+							Description: "Resume automatically: 3d, 2026-08-01, or 2026-08-01 09:00. Omit to pause until resumed.",
+							Required:    false,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "resume",
+					Description: "Resume a paused monitor",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "id",
+							Description: "Monitor ID (from /monitor list)",
+							Required:    true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -85,6 +125,10 @@ func (m *MonitorModule) Handle(r Responder, _ *discordgo.Session, i *discordgo.I
 		m.handleList(r, i)
 	case "remove":
 		m.handleRemove(r, i, sub)
+	case "pause":
+		m.handlePause(r, i, sub)
+	case "resume":
+		m.handleResume(r, i, sub)
 	}
 	return true
 }
@@ -139,7 +183,14 @@ func (m *MonitorModule) handleList(r Responder, i *discordgo.InteractionCreate)
 		if mon.Selector != "" {
 			sel = mon.Selector
 		}
-		fmt.Fprintf(&sb, "`%d` **%s** `%s` — selector: `%s` — <%s>\n", mon.ID, mon.Label, mon.LastStatus, sel, mon.URL)
+		status := mon.LastStatus
+		switch {
+		case mon.Paused && mon.PausedUntil.IsZero():
+			status = "paused"
+		case mon.Paused:
+			status = fmt.Sprintf("paused until <t:%d:f>", mon.PausedUntil.Unix())
+		}
+		fmt.Fprintf(&sb, "`%d` **%s** `%s` — selector: `%s` — <%s>\n", mon.ID, mon.Label, status, sel, mon.URL)
 	}
 	r.Respond(i, sb.String(), true)
 }
@@ -160,6 +211,54 @@ func (m *MonitorModule) handleRemove(r Responder, i *discordgo.InteractionCreate
 	r.Respond(i, "Monitor #"+strconv.FormatInt(id, 10)+" removed.", true)
 }
 
+func (m *MonitorModule) handlePause(r Responder, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	opts := optionMap(sub.Options)
+	id := opts["id"].IntValue()
+	userID := i.Member.User.ID
+	ctx := context.Background()
+
+	var until time.Time
+	if o, ok := opts["until"]; ok && strings.TrimSpace(o.StringValue()) != "" {
+		parsed, err := reminders.ParseUntil(time.Now().UTC(), o.StringValue(), userTimezone(ctx, m.settingsService, userID))
+		if err != nil {
+			r.Respond(i, "Error: "+err.Error(), true)
+			return
+		}
+		until = parsed
+	}
+
+	ok, err := m.service.PauseMonitor(ctx, id, userID, until)
+	if err != nil {
+		r.Respond(i, "Error: "+err.Error(), true)
+		return
+	}
+	if !ok {
+		r.Respond(i, "Monitor not found or doesn't belong to you.", true)
+		return
+	}
+	if until.IsZero() {
+		r.Respond(i, fmt.Sprintf("Monitor #%d paused. Use `/monitor resume id:%d` to start checking again.", id, id), true)
+		return
+	}
+	r.Respond(i, fmt.Sprintf("Monitor #%d paused until <t:%d:f>.", id, until.Unix()), true)
+}
+
+func (m *MonitorModule) handleResume(r Responder, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	opts := optionMap(sub.Options)
+	id := opts["id"].IntValue()
+
+	ok, err := m.service.ResumeMonitor(context.Background(), id, i.Member.User.ID)
+	if err != nil {
+		r.Respond(i, "Failed to resume monitor.", true)
+		return
+	}
+	if !ok {
+		r.Respond(i, "Monitor not found, doesn't belong to you, or isn't paused.", true)
+		return
+	}
+	r.Respond(i, "Monitor #"+strconv.FormatInt(id, 10)+" resumed.", true)
+}
+
 func optionMap(opts []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
 	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
 	for _, o := range opts {
@@ -2,8 +2,12 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,11 +20,40 @@ import (
 const (
 	reminderEmbedColor   = 0x5865F2
 	reminderListPageSize = 5
+
+	// maxCalendarImportBytes caps the size of an imported .ics attachment.
+	maxCalendarImportBytes  = 1 << 20
+	maxDiscordContentLength = 2000
+)
+
+var scheduleChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "once", Value: string(reminders.ScheduleOnce)},
+	{Name: "hourly", Value: string(reminders.ScheduleHourly)},
+	{Name: "daily", Value: string(reminders.ScheduleDaily)},
+	{Name: "weekly", Value: string(reminders.ScheduleWeekly)},
+	{Name: "monthly", Value: string(reminders.ScheduleMonthly)},
+	{Name: "every N minutes/hours", Value: string(reminders.ScheduleInterval)},
+	{Name: "cron", Value: string(reminders.ScheduleCron)},
+}
+
+var calendarDownloadClient = &http.Client{Timeout: 10 * time.Second}
+
+// Custom IDs for the buttons attached to delivered reminders have the form
+// "remind:<action>:<reminder id>".
+const (
+	reminderComponentPrefix   = "remind:"
+	reminderActionSnooze10m   = "snooze10m"
+	reminderActionSnooze1h    = "snooze1h"
+	reminderActionTomorrow    = "tomorrow"
+	reminderActionAcknowledge = "done"
+	reminderActionJoin        = "join"
+	reminderActionLeave       = "leave"
 )
 
 type RemindModule struct {
 	service         *reminders.Service
 	settingsService *usersettings.Service
+	feeds           *reminders.FeedService
 }
 
 func NewRemindModule(service *reminders.Service, settingsService ...*usersettings.Service) *RemindModule {
@@ -31,6 +64,12 @@ func NewRemindModule(service *reminders.Service, settingsService ...*usersetting
 	return &RemindModule{service: service, settingsService: settings}
 }
 
+// SetFeedService enables /remind feed. Without it the command reports that
+// feeds aren't configured.
+func (m *RemindModule) SetFeedService(feeds *reminders.FeedService) {
+	m.feeds = feeds
+}
+
 func (m *RemindModule) Definitions() []*discordgo.ApplicationCommand {
 	return []*discordgo.ApplicationCommand{
 		{
@@ -43,13 +82,23 @@ func (m *RemindModule) Definitions() []*discordgo.ApplicationCommand {
 					Description: "Add a reminder",
 					Options: []*discordgo.ApplicationCommandOption{
 						{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "What should I send?", Required: true},
-						{Type: discordgo.ApplicationCommandOptionString, Name: "schedule", Description: "When: once, hourly, or daily", Required: true, Choices: []*discordgo.ApplicationCommandOptionChoice{
-							{Name: "once", Value: "once"},
-							{Name: "hourly", Value: "hourly"},
-							{Name: "daily", Value: "daily"},
-						}},
-						{Type: discordgo.ApplicationCommandOptionString, Name: "at", Description: "Once: 10m, 2h, or YYYY-MM-DD HH:MM. Daily: HH:MM. Hourly: :MM. Optional.", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "schedule", Description: "How often to repeat. Omit to describe the time in words with at.", Required: false, Choices: scheduleChoices},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "at", Description: "e.g. tomorrow 9am, in 2 hours, every weekday at 8:30. Daily/weekly/monthly: HH:MM. Hourly: :MM.", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "days", Description: "Weekly: days to run, e.g. mon,wed,fri or weekdays", Required: false},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "day", Description: "Monthly: day of the month (1-31)", Required: false, MinValue: &minDayOfMonth, MaxValue: 31},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "every", Description: "Every N minutes/hours: e.g. 15m or 2h", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "cron", Description: "Cron: five-field expression, e.g. 0 9 * * 1-5", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "ends_at", Description: "Stop repeating after: 30d, 2026-12-31 (inclusive), or 2026-12-31 18:00", Required: false},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "runs", Description: "Stop repeating after this many reminders", Required: false, MinValue: &minReminderRuns},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "mention", Description: "Who to ping: @users, @roles, or @here. Defaults to you.", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "delivery", Description: "Where to send it; defaults to your /settings delivery choice", Required: false, Choices: deliveryModeChoices},
+						{Type: discordgo.ApplicationCommandOptionBoolean, Name: "shared", Description: "Let others in this server subscribe to be mentioned too", Required: false},
 						{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "Channel to send this reminder in; defaults to current channel", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "catch_up", Description: "Runs missed while the bot was offline: send once (default), each, or skip", Required: false, Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "once", Value: string(reminders.CatchUpOnce)},
+							{Name: "each", Value: string(reminders.CatchUpEach)},
+							{Name: "skip", Value: string(reminders.CatchUpSkip)},
+						}},
 					},
 				},
 				{
@@ -68,12 +117,101 @@ func (m *RemindModule) Definitions() []*discordgo.ApplicationCommand {
 						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Reminder ID", Required: true},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "edit",
+					Description: "Change a reminder's message, schedule, timezone, or channel",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Reminder ID", Required: true},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "message", Description: "New reminder text", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "schedule", Description: "New schedule kind", Required: false, Choices: scheduleChoices},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "at", Description: "Once: 10m, 2h, or YYYY-MM-DD HH:MM. Daily/weekly/monthly: HH:MM. Hourly: :MM.", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "days", Description: "Weekly: days to run, e.g. mon,wed,fri or weekdays", Required: false},
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "day", Description: "Monthly: day of the month (1-31)", Required: false, MinValue: &minDayOfMonth, MaxValue: 31},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "every", Description: "Every N minutes/hours: e.g. 15m or 2h", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "cron", Description: "Cron: five-field expression, e.g. 0 9 * * 1-5", Required: false},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "timezone", Description: "IANA timezone, e.g. Asia/Tokyo", Required: false},
+						{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "Channel to send this reminder in", Required: false},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "pause",
+					Description: "Stop a reminder without deleting it",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Reminder ID", Required: true},
+						{Type: discordgo.ApplicationCommandOptionString, Name: "until", Description: "Resume automatically: 3d, 2026-08-01, or 2026-08-01 09:00. Omit to pause until resumed.", Required: false},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "resume",
+					Description: "Resume a paused reminder",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Reminder ID", Required: true},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "subscribe",
+					Description: "Get mentioned by a shared reminder in this server",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Shared reminder ID", Required: true},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "unsubscribe",
+					Description: "Stop getting mentioned by a shared reminder",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Shared reminder ID", Required: true},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "history",
+					Description: "Show recent delivery attempts for a reminder",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "Reminder ID", Required: true},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Download your reminders as an iCalendar (.ics) file",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "feed",
+					Description: "Get a private calendar feed URL of your upcoming reminders",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "action",
+							Description: "show (default), rotate to a new secret URL, or disable",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "show", Value: "show"},
+								{Name: "rotate", Value: "rotate"},
+								{Name: "disable", Value: "disable"},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "import",
+					Description: "Create reminders from an iCalendar (.ics) file, delivered in this channel",
+					Options: []*discordgo.ApplicationCommandOption{
+						{Type: discordgo.ApplicationCommandOptionAttachment, Name: "file", Description: "The .ics file to import", Required: true},
+					},
+				},
 			},
 		},
 	}
 }
 
-func (m *RemindModule) Handle(responder Responder, _ *discordgo.Session, i *discordgo.InteractionCreate) bool {
+func (m *RemindModule) Handle(responder Responder, s *discordgo.Session, i *discordgo.InteractionCreate) bool {
 	if i.ApplicationCommandData().Name != "remind" {
 		return false
 	}
@@ -86,20 +224,196 @@ func (m *RemindModule) Handle(responder Responder, _ *discordgo.Session, i *disc
 
 	switch options[0].Name {
 	case "add":
-		m.handleAdd(responder, i)
+		m.handleAdd(responder, s, i)
 	case "list":
 		m.handleList(responder, i)
 	case "delete":
 		m.handleDelete(responder, i)
+	case "edit":
+		m.handleEdit(responder, i)
+	case "pause":
+		m.handlePause(responder, i)
+	case "resume":
+		m.handleResume(responder, i)
+	case "history":
+		m.handleHistory(responder, i)
+	case "export":
+		m.handleExport(responder, i)
+	case "import":
+		m.handleImport(responder, i)
+	case "feed":
+		m.handleFeed(responder, i)
+	case "subscribe", "unsubscribe":
+		userID := userIDFromInteraction(i)
+		if userID == "" {
+			responder.Respond(i, "Unable to identify the user for this reminder.", true)
+			return true
+		}
+		var id int64
+		for _, o := range options[0].Options {
+			if o.Name == "id" {
+				id = o.IntValue()
+			}
+		}
+		m.respondSubscription(responder, i, id, userID, options[0].Name == "subscribe")
 	default:
 		responder.Respond(i, "Unknown subcommand.", true)
 	}
 	return true
 }
 
-func (m *RemindModule) handleAdd(responder Responder, i *discordgo.InteractionCreate) {
+// ReminderActionComponents returns the snooze and done buttons attached to a
+// delivered reminder message.
+func ReminderActionComponents(reminderID int64) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Snooze 10m", Style: discordgo.SecondaryButton, CustomID: reminderComponentID(reminderActionSnooze10m, reminderID)},
+				discordgo.Button{Label: "Snooze 1h", Style: discordgo.SecondaryButton, CustomID: reminderComponentID(reminderActionSnooze1h, reminderID)},
+				discordgo.Button{Label: "Tomorrow", Style: discordgo.SecondaryButton, CustomID: reminderComponentID(reminderActionTomorrow, reminderID)},
+				discordgo.Button{Label: "Done", Style: discordgo.SuccessButton, CustomID: reminderComponentID(reminderActionAcknowledge, reminderID)},
+			},
+		},
+	}
+}
+
+// SharedReminderComponents returns the join and leave buttons attached to a
+// delivered shared reminder.
+func SharedReminderComponents(reminderID int64) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Remind me too", Style: discordgo.PrimaryButton, CustomID: reminderComponentID(reminderActionJoin, reminderID)},
+				discordgo.Button{Label: "Stop reminding me", Style: discordgo.SecondaryButton, CustomID: reminderComponentID(reminderActionLeave, reminderID)},
+			},
+		},
+	}
+}
+
+func reminderComponentID(action string, reminderID int64) string {
+	return reminderComponentPrefix + action + ":" + strconv.FormatInt(reminderID, 10)
+}
+
+func parseReminderComponentID(customID string) (string, int64, bool) {
+	rest, ok := strings.CutPrefix(customID, reminderComponentPrefix)
+	if !ok {
+		return "", 0, false
+	}
+	action, rawID, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	return action, id, true
+}
+
+func (m *RemindModule) HandleComponent(responder Responder, _ *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	action, id, ok := parseReminderComponentID(i.MessageComponentData().CustomID)
+	if !ok {
+		return false
+	}
+
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return true
+	}
+
+	if action == reminderActionJoin || action == reminderActionLeave {
+		m.respondSubscription(responder, i, id, userID, action == reminderActionJoin)
+		return true
+	}
+
+	ctx := context.Background()
+	var (
+		reminder reminders.Reminder
+		found    bool
+		err      error
+	)
+	switch action {
+	case reminderActionSnooze10m:
+		reminder, found, err = m.service.SnoozeReminder(ctx, id, userID, 10*time.Minute)
+	case reminderActionSnooze1h:
+		reminder, found, err = m.service.SnoozeReminder(ctx, id, userID, time.Hour)
+	case reminderActionTomorrow:
+		reminder, found, err = m.service.SnoozeReminderUntilTomorrow(ctx, id, userID)
+	case reminderActionAcknowledge:
+		reminder, found, err = m.service.AcknowledgeReminder(ctx, id, userID)
+	default:
+		responder.Respond(i, "Unknown reminder action.", true)
+		return true
+	}
+	if err != nil {
+		log.Printf("reminder component error: action=%s reminder_id=%d user_id=%s error=%v", action, id, userID, err)
+		responder.Respond(i, "Failed to update reminder.", true)
+		return true
+	}
+	if !found {
+		responder.Respond(i, "This reminder doesn't belong to you or no longer exists.", true)
+		return true
+	}
+
+	status := "Snoozed until " + formatReminderTime(reminder.NextRun)
+	if action == reminderActionAcknowledge {
+		status = "Marked as done."
+		if !reminder.Once {
+			status += " Next: " + formatReminderTime(reminder.NextRun)
+		}
+	}
+	content := status
+	if i.Message != nil && i.Message.Content != "" {
+		content = i.Message.Content + "\n\n" + status
+	}
+	responder.UpdateComponentMessage(i, content)
+	return true
+}
+
+// respondSubscription joins or leaves a shared reminder for the user and
+// answers privately, leaving the reminder message and its buttons intact.
+func (m *RemindModule) respondSubscription(responder Responder, i *discordgo.InteractionCreate, id int64, userID string, join bool) {
+	if i.GuildID == "" {
+		responder.Respond(i, "Shared reminders only work in servers.", true)
+		return
+	}
+	ctx := context.Background()
+	var (
+		reminder       reminders.Reminder
+		found, changed bool
+		err            error
+	)
+	if join {
+		reminder, found, changed, err = m.service.SubscribeReminder(ctx, id, i.GuildID, userID)
+	} else {
+		reminder, found, changed, err = m.service.UnsubscribeReminder(ctx, id, i.GuildID, userID)
+	}
+	if err != nil {
+		log.Printf("reminder subscription error: reminder_id=%d user_id=%s join=%v error=%v", id, userID, join, err)
+		responder.Respond(i, "Failed to update your subscription.", true)
+		return
+	}
+	switch {
+	case !found:
+		responder.Respond(i, "No shared reminder with that ID in this server.", true)
+	case join && !changed:
+		responder.Respond(i, "You're already subscribed to this reminder.", true)
+	case join:
+		responder.Respond(i, fmt.Sprintf("You'll be mentioned on reminder `%d`. Next: %s", reminder.ID, formatReminderTime(reminder.NextRun)), true)
+	case !changed:
+		responder.Respond(i, "You weren't subscribed to this reminder.", true)
+	default:
+		responder.Respond(i, fmt.Sprintf("You won't be mentioned on reminder `%d` anymore.", reminder.ID), true)
+	}
+}
+
+func (m *RemindModule) handleAdd(responder Responder, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	opts := i.ApplicationCommandData().Options[0].Options
-	var message, scheduleStr, at string
+	var message, scheduleStr, at, catchUp, days, every, cronExpr, endsAt, mention, delivery string
+	var dayOfMonth int
+	var runs int64
+	var shared bool
 	channelID := i.ChannelID
 	for _, o := range opts {
 		switch o.Name {
@@ -109,6 +423,26 @@ func (m *RemindModule) handleAdd(responder Responder, i *discordgo.InteractionCr
 			scheduleStr = o.StringValue()
 		case "at":
 			at = o.StringValue()
+		case "days":
+			days = o.StringValue()
+		case "day":
+			dayOfMonth = int(o.IntValue())
+		case "every":
+			every = o.StringValue()
+		case "cron":
+			cronExpr = o.StringValue()
+		case "ends_at":
+			endsAt = o.StringValue()
+		case "runs":
+			runs = o.IntValue()
+		case "mention":
+			mention = o.StringValue()
+		case "delivery":
+			delivery = o.StringValue()
+		case "shared":
+			shared = o.BoolValue()
+		case "catch_up":
+			catchUp = o.StringValue()
 		case "channel":
 			if selected := channelIDFromOption(o); selected != "" {
 				channelID = selected
@@ -116,13 +450,32 @@ func (m *RemindModule) handleAdd(responder Responder, i *discordgo.InteractionCr
 		}
 	}
 
+	if strings.TrimSpace(scheduleStr) == "" && strings.TrimSpace(at) == "" {
+		responder.Respond(i, "Say when with `at` (e.g. `tomorrow 9am` or `every weekday at 8:30`), or pick a schedule.", true)
+		return
+	}
+
 	guildID := i.GuildID
 	userID := userIDFromInteraction(i)
 	if userID == "" {
 		responder.Respond(i, "Unable to identify the user for this reminder.", true)
 		return
 	}
-	timezone := m.userTimezone(context.Background(), userID)
+	target, err := reminders.ParseMentionTarget(mention)
+	if err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	if err := checkMentionPermission(s, guildID, channelID, userID, target); err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	timezone := userTimezone(context.Background(), m.settingsService, userID)
+	// Mentions only reach anyone in the channel, so the saved default is
+	// for reminders that ping just their owner.
+	if delivery == "" && !shared && target.IsZero() {
+		delivery = string(userDeliveryMode(context.Background(), m.settingsService, userID))
+	}
 
 	reminder, err := m.service.CreateReminder(context.Background(), reminders.CreateReminderInput{
 		UserID:    userID,
@@ -131,33 +484,318 @@ func (m *RemindModule) handleAdd(responder Responder, i *discordgo.InteractionCr
 		Message:   message,
 		Schedule:  scheduleStr,
 		At:        at,
+		CronExpr:  cronExpr,
 		Timezone:  timezone,
+		CatchUp:   catchUp,
+
+		Weekdays:   days,
+		DayOfMonth: dayOfMonth,
+		Every:      every,
+
+		EndsAt:        endsAt,
+		RemainingRuns: runs,
+		Mentions:      target,
+		DeliveryMode:  delivery,
+		Shared:        shared,
 	})
 	if err != nil {
 		responder.Respond(i, err.Error(), true)
 		return
 	}
 
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "ID", Value: fmt.Sprintf("%d", reminder.ID), Inline: true},
+		{Name: "Schedule", Value: formatReminderRepeat(*reminder), Inline: true},
+		{Name: "Channel", Value: renderChannelOrFallback(reminder.ChannelID, "Current channel"), Inline: true},
+		{Name: "Timezone", Value: "`" + reminder.Timezone + "`", Inline: true},
+		{Name: "Delivery", Value: describeDeliveryMode(reminder.DeliveryMode), Inline: true},
+		{Name: "Next Run", Value: formatReminderTime(reminder.NextRun), Inline: false},
+	}
+	if !reminder.Mentions.IsZero() {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Mentions", Value: reminder.Mentions.String(), Inline: false})
+	}
+	if reminder.HasEndCondition() {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Ends", Value: formatReminderEnd(*reminder), Inline: false})
+	}
+	if preview := formatUpcomingRuns(*reminder, 5); preview != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Upcoming Runs", Value: preview, Inline: false})
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "Message", Value: trimForField(reminder.Message, 900), Inline: false})
+	footer := "Reminder delivery will mention you only"
+	if !reminder.Mentions.IsZero() {
+		footer = "Reminder delivery will mention only the targets above"
+	}
+	if reminder.Shared {
+		footer = fmt.Sprintf("Shared: others can join with /remind subscribe id:%d or the button on each reminder", reminder.ID)
+	}
+
 	responder.RespondEmbed(i, &discordgo.MessageEmbed{
-		Title: "Reminder Added",
-		Color: reminderEmbedColor,
-		Fields: []*discordgo.MessageEmbedField{
-			{Name: "ID", Value: fmt.Sprintf("%d", reminder.ID), Inline: true},
-			{Name: "Schedule", Value: string(reminder.Schedule), Inline: true},
-			{Name: "Channel", Value: renderChannelOrFallback(reminder.ChannelID, "Current channel"), Inline: true},
-			{Name: "Timezone", Value: "`" + reminder.Timezone + "`", Inline: true},
-			{Name: "Next Run", Value: formatReminderTime(reminder.NextRun), Inline: false},
-			{Name: "Message", Value: trimForField(reminder.Message, 900), Inline: false},
-		},
-		Footer: &discordgo.MessageEmbedFooter{Text: "Reminder delivery will mention you only"},
+		Title:  "Reminder Added",
+		Color:  reminderEmbedColor,
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{Text: footer},
+	}, true)
+}
+
+// checkMentionPermission makes sure the creator may ping target in the
+// reminder's channel: @here and roles that aren't mentionable need the
+// Mention @everyone, @here, and All Roles permission, as they would when
+// the creator posted the mention themselves.
+func checkMentionPermission(s *discordgo.Session, guildID, channelID, userID string, target reminders.MentionTarget) error {
+	if target.IsZero() {
+		return nil
+	}
+	if guildID == "" {
+		return errors.New("Mentions only work in server channels.")
+	}
+	if s == nil {
+		return errors.New("Unable to check mention permissions right now.")
+	}
+	perms, err := s.UserChannelPermissions(userID, channelID)
+	if err != nil {
+		return errors.New("Unable to check your permissions in that channel.")
+	}
+	if perms&discordgo.PermissionAdministrator != 0 || perms&discordgo.PermissionMentionEveryone != 0 {
+		return nil
+	}
+	if target.Here {
+		return errors.New("You need the Mention @everyone, @here, and All Roles permission to use @here.")
+	}
+	for _, roleID := range target.Roles {
+		role, err := guildRole(s, guildID, roleID)
+		if err != nil {
+			return fmt.Errorf("Unknown role <@&%s>.", roleID)
+		}
+		if !role.Mentionable {
+			return fmt.Errorf("You can't mention <@&%s>: it isn't mentionable and you lack the Mention All Roles permission.", roleID)
+		}
+	}
+	return nil
+}
+
+// NewChannelAccess checks reminder channel changes against s, applying the
+// same mention rules as /remind add.
+func NewChannelAccess(s *discordgo.Session) reminders.ChannelAccess {
+	return channelAccess{s: s}
+}
+
+type channelAccess struct {
+	s *discordgo.Session
+}
+
+func (a channelAccess) CheckChannel(guildID, channelID, userID string) error {
+	if guildID == "" {
+		return errors.New("Only reminders made in a server can move to another channel.")
+	}
+	channel, err := guildChannel(a.s, channelID)
+	if err != nil || channel.GuildID != guildID {
+		return errors.New("That channel isn't in this reminder's server.")
+	}
+	perms, err := a.s.UserChannelPermissions(userID, channelID)
+	if err != nil {
+		return errors.New("Unable to check your permissions in that channel.")
+	}
+	if perms&discordgo.PermissionViewChannel == 0 || perms&discordgo.PermissionSendMessages == 0 {
+		return errors.New("You can't send messages in that channel.")
+	}
+	return nil
+}
+
+func (a channelAccess) CheckMentions(guildID, channelID, userID string, target reminders.MentionTarget) error {
+	return checkMentionPermission(a.s, guildID, channelID, userID, target)
+}
+
+func guildChannel(s *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	if s.State != nil {
+		if channel, err := s.State.Channel(channelID); err == nil {
+			return channel, nil
+		}
+	}
+	return s.Channel(channelID)
+}
+
+func guildRole(s *discordgo.Session, guildID, roleID string) (*discordgo.Role, error) {
+	if s.State != nil {
+		if role, err := s.State.Role(guildID, roleID); err == nil {
+			return role, nil
+		}
+	}
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role, nil
+		}
+	}
+	return nil, discordgo.ErrStateNotFound
+}
+
+func (m *RemindModule) handleEdit(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+
+	input := reminders.UpdateReminderInput{UserID: userID}
+	for _, o := range i.ApplicationCommandData().Options[0].Options {
+		switch o.Name {
+		case "id":
+			input.ID = o.IntValue()
+		case "message":
+			v := o.StringValue()
+			input.Message = &v
+		case "schedule":
+			v := o.StringValue()
+			input.Schedule = &v
+		case "at":
+			v := o.StringValue()
+			input.At = &v
+		case "days":
+			v := o.StringValue()
+			input.Weekdays = &v
+		case "day":
+			v := int(o.IntValue())
+			input.DayOfMonth = &v
+		case "every":
+			v := o.StringValue()
+			input.Every = &v
+		case "cron":
+			v := o.StringValue()
+			input.CronExpr = &v
+		case "timezone":
+			v := o.StringValue()
+			input.Timezone = &v
+		case "channel":
+			if selected := channelIDFromOption(o); selected != "" {
+				input.ChannelID = &selected
+			}
+		}
+	}
+	if input.ID <= 0 {
+		responder.Respond(i, "Invalid id", true)
+		return
+	}
+	if input.Message == nil && input.Schedule == nil && input.At == nil && input.Timezone == nil && input.ChannelID == nil &&
+		input.Weekdays == nil && input.DayOfMonth == nil && input.Every == nil && input.CronExpr == nil {
+		responder.Respond(i, "Nothing to change. Pass at least one of message, schedule, at, days, day, every, cron, timezone, or channel.", true)
+		return
+	}
+
+	reminder, ok, err := m.service.UpdateReminder(context.Background(), input)
+	if err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	if !ok {
+		responder.Respond(i, "Reminder not found.", true)
+		return
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "ID", Value: fmt.Sprintf("%d", reminder.ID), Inline: true},
+		{Name: "Repeat", Value: formatReminderRepeat(reminder), Inline: true},
+		{Name: "Channel", Value: renderChannelOrFallback(reminder.ChannelID, "Unknown"), Inline: true},
+		{Name: "Timezone", Value: "`" + reminder.Timezone + "`", Inline: true},
+		{Name: "Next Run", Value: formatReminderTime(reminder.NextRun), Inline: false},
+	}
+	if preview := formatUpcomingRuns(reminder, 5); preview != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Upcoming Runs", Value: preview, Inline: false})
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "Message", Value: trimForField(reminder.Message, 900), Inline: false})
+	responder.RespondEmbed(i, &discordgo.MessageEmbed{
+		Title:  "Reminder Updated",
+		Color:  reminderEmbedColor,
+		Fields: fields,
 	}, true)
 }
 
-func (m *RemindModule) userTimezone(ctx context.Context, userID string) string {
-	if m.settingsService == nil {
+func (m *RemindModule) handlePause(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+	var id int64
+	var rawUntil string
+	for _, o := range i.ApplicationCommandData().Options[0].Options {
+		switch o.Name {
+		case "id":
+			id = o.IntValue()
+		case "until":
+			rawUntil = o.StringValue()
+		}
+	}
+	if id <= 0 {
+		responder.Respond(i, "Invalid id", true)
+		return
+	}
+
+	ctx := context.Background()
+	var until time.Time
+	if strings.TrimSpace(rawUntil) != "" {
+		parsed, err := reminders.ParseUntil(time.Now().UTC(), rawUntil, userTimezone(ctx, m.settingsService, userID))
+		if err != nil {
+			responder.Respond(i, err.Error(), true)
+			return
+		}
+		until = parsed
+	}
+
+	reminder, ok, err := m.service.PauseReminder(ctx, id, userID, until)
+	if err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	if !ok {
+		responder.Respond(i, "Reminder not found.", true)
+		return
+	}
+	if until.IsZero() {
+		responder.Respond(i, fmt.Sprintf("Paused reminder #%d. Use `/remind resume id:%d` to turn it back on.", reminder.ID, reminder.ID), true)
+		return
+	}
+	responder.Respond(i, fmt.Sprintf("Paused reminder #%d until %s.", reminder.ID, formatReminderTime(until)), true)
+}
+
+func (m *RemindModule) handleResume(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+	var id int64
+	for _, o := range i.ApplicationCommandData().Options[0].Options {
+		if o.Name == "id" {
+			id = o.IntValue()
+		}
+	}
+	if id <= 0 {
+		responder.Respond(i, "Invalid id", true)
+		return
+	}
+
+	reminder, ok, err := m.service.ResumeReminder(context.Background(), id, userID)
+	if err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	if !ok {
+		responder.Respond(i, "Reminder not found.", true)
+		return
+	}
+	responder.Respond(i, fmt.Sprintf("Resumed reminder #%d. Next run: %s.", reminder.ID, formatReminderTime(reminder.NextRun)), true)
+}
+
+// userTimezone returns the user's configured timezone, or the default when
+// settings are unavailable.
+func userTimezone(ctx context.Context, settingsService *usersettings.Service, userID string) string {
+	if settingsService == nil {
 		return usersettings.DefaultTimezone
 	}
-	timezone, _, err := m.settingsService.GetTimezone(ctx, userID)
+	timezone, _, err := settingsService.GetTimezone(ctx, userID)
 	if err != nil {
 		log.Printf("load user timezone error: user_id=%s error=%v", userID, err)
 		return usersettings.DefaultTimezone
@@ -165,6 +803,20 @@ func (m *RemindModule) userTimezone(ctx context.Context, userID string) string {
 	return timezone
 }
 
+// userDeliveryMode returns the user's default reminder delivery mode,
+// falling back to channel delivery.
+func userDeliveryMode(ctx context.Context, settingsService *usersettings.Service, userID string) reminders.DeliveryMode {
+	if settingsService == nil {
+		return reminders.DeliveryChannel
+	}
+	mode, _, err := settingsService.GetDeliveryMode(ctx, userID)
+	if err != nil {
+		log.Printf("load user delivery mode error: user_id=%s error=%v", userID, err)
+		return reminders.DeliveryChannel
+	}
+	return mode
+}
+
 func (m *RemindModule) handleList(responder Responder, i *discordgo.InteractionCreate) {
 	userID := userIDFromInteraction(i)
 	if userID == "" {
@@ -265,6 +917,67 @@ func (m *RemindModule) handleDelete(responder Responder, i *discordgo.Interactio
 	}, true)
 }
 
+func (m *RemindModule) handleHistory(responder Responder, i *discordgo.InteractionCreate) {
+	var id int64
+	for _, o := range i.ApplicationCommandData().Options[0].Options {
+		if o.Name == "id" {
+			id = o.IntValue()
+		}
+	}
+	if id <= 0 {
+		responder.Respond(i, "Invalid id", true)
+		return
+	}
+
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+
+	deliveries, ok, err := m.service.ReminderHistory(context.Background(), id, userID, 10)
+	if err != nil {
+		log.Printf("reminder history error: %v", err)
+		responder.Respond(i, "Failed to load reminder history.", true)
+		return
+	}
+	if !ok {
+		responder.Respond(i, "Reminder not found.", true)
+		return
+	}
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Reminder #%d History", id),
+		Color: reminderEmbedColor,
+	}
+	if len(deliveries) == 0 {
+		embed.Description = "No delivery attempts yet."
+		responder.RespondEmbed(i, embed, true)
+		return
+	}
+	var b strings.Builder
+	for _, d := range deliveries {
+		fmt.Fprintf(&b, "<t:%d:f> — **%s**", d.ScheduledFor.Unix(), d.Status)
+		if d.Attempts > 1 {
+			fmt.Fprintf(&b, " after %d attempts", d.Attempts)
+		}
+		if d.MissedRuns > 0 {
+			fmt.Fprintf(&b, " (%s)", reminders.MissedRunsNote(d.MissedRuns))
+		}
+		if d.Via == reminders.DeliveryDM {
+			b.WriteString(" by DM")
+		}
+		if d.Error != "" {
+			fmt.Fprintf(&b, "\n  %s", trimForField(d.Error, 150))
+		}
+		if d.FallbackReason != "" {
+			fmt.Fprintf(&b, "\n  Sent by DM because I %s", trimForField(d.FallbackReason, 150))
+		}
+		b.WriteString("\n")
+	}
+	embed.Description = strings.TrimSpace(b.String())
+	responder.RespondEmbed(i, embed, true)
+}
+
 func userIDFromInteraction(i *discordgo.InteractionCreate) string {
 	if i.Member != nil && i.Member.User != nil {
 		return i.Member.User.ID
@@ -284,6 +997,23 @@ func formatReminderListEntry(r reminders.Reminder) string {
 	fmt.Fprintf(&b, "Channel: %s", renderChannelOrFallback(r.ChannelID, "Unknown"))
 	b.WriteString("\n")
 	fmt.Fprintf(&b, "Message: %s", trimForField(r.Message, 220))
+	if r.HasEndCondition() {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Ends: %s", formatReminderEnd(r))
+	}
+	switch {
+	case r.Paused && r.PausedUntil.IsZero():
+		b.WriteString("\nStatus: paused")
+	case r.Paused:
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Status: paused until %s", formatReminderTime(r.PausedUntil))
+	case r.Dead():
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Status: failed, not retrying (%s)", trimForField(r.LastError, 160))
+	case r.FailureCount > 0:
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Status: retrying %s after %d failed sends (%s)", formatReminderTime(r.RetryAt), r.FailureCount, trimForField(r.LastError, 160))
+	}
 	return b.String()
 }
 
@@ -300,9 +1030,233 @@ func formatReminderRepeat(r reminders.Reminder) string {
 		return "hourly"
 	case reminders.ScheduleDaily:
 		return "daily"
+	case reminders.ScheduleWeekly:
+		if fields := strings.Fields(r.CronExpr); len(fields) == 5 {
+			return "weekly on " + formatCronWeekdays(fields[4])
+		}
+		return "weekly"
+	case reminders.ScheduleMonthly:
+		if fields := strings.Fields(r.CronExpr); len(fields) == 5 {
+			return "monthly on day " + fields[2]
+		}
+		return "monthly"
+	case reminders.ScheduleInterval:
+		return "every " + r.AtTime.String
 	case reminders.ScheduleCron:
-		return "custom"
+		return "custom (`" + r.CronExpr + "`)"
 	default:
 		return string(r.Schedule)
 	}
 }
+
+var (
+	minDayOfMonth   = 1.0
+	minReminderRuns = 1.0
+)
+
+// formatReminderEnd describes when a reminder with an end condition retires.
+func formatReminderEnd(r reminders.Reminder) string {
+	var parts []string
+	if !r.EndsAt.IsZero() {
+		parts = append(parts, "after "+formatReminderTime(r.EndsAt))
+	}
+	if r.RemainingRuns.Valid {
+		if r.RemainingRuns.Int64 == 1 {
+			parts = append(parts, "after 1 more run")
+		} else {
+			parts = append(parts, fmt.Sprintf("after %d more runs", r.RemainingRuns.Int64))
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+var cronWeekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+func formatCronWeekdays(field string) string {
+	parts := strings.Split(field, ",")
+	for idx, part := range parts {
+		if n, err := strconv.Atoi(part); err == nil && n >= 0 && n < len(cronWeekdayNames) {
+			parts[idx] = cronWeekdayNames[n]
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatUpcomingRuns lists the next n runs in the reminder's own timezone so
+// the user can check a schedule before it first fires.
+func formatUpcomingRuns(r reminders.Reminder, n int) string {
+	runs, err := reminders.UpcomingRuns(r, n)
+	if err != nil || len(runs) < 2 {
+		return ""
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	lines := make([]string, 0, len(runs))
+	for _, run := range runs {
+		lines = append(lines, run.In(loc).Format("Mon Jan 2 2006, 15:04 MST"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *RemindModule) handleExport(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+	export, err := m.service.ExportICS(context.Background(), userID)
+	if err != nil {
+		log.Printf("reminder export error: user_id=%s error=%v", userID, err)
+		responder.Respond(i, "Failed to export reminders.", true)
+		return
+	}
+	if export.Events == 0 && len(export.Skipped) == 0 {
+		responder.Respond(i, "You have no reminders to export.", true)
+		return
+	}
+	content := fmt.Sprintf("Exported %d reminder(s).", export.Events)
+	content += formatCalendarIssues("Not exported", export.Skipped)
+	if export.Events == 0 {
+		responder.Respond(i, truncateText(content, maxDiscordContentLength), true)
+		return
+	}
+	responder.RespondFile(i, truncateText(content, maxDiscordContentLength), &discordgo.File{
+		Name:        "reminders.ics",
+		ContentType: "text/calendar",
+		Reader:      strings.NewReader(string(export.Data)),
+	}, true)
+}
+
+func (m *RemindModule) handleImport(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+	data := i.ApplicationCommandData()
+	var attachment *discordgo.MessageAttachment
+	for _, o := range data.Options[0].Options {
+		if o.Name == "file" && data.Resolved != nil {
+			attachment = data.Resolved.Attachments[o.StringValue()]
+		}
+	}
+	if attachment == nil {
+		responder.Respond(i, "Attach an .ics file to import.", true)
+		return
+	}
+	if !strings.HasSuffix(strings.ToLower(attachment.Filename), ".ics") {
+		responder.Respond(i, "That file isn't an iCalendar (.ics) file.", true)
+		return
+	}
+	if attachment.Size > maxCalendarImportBytes {
+		responder.Respond(i, "That calendar file is too large to import (1 MB max).", true)
+		return
+	}
+
+	// Downloading and importing can outlast the interaction deadline.
+	responder.DeferResponse(i, true)
+	resp, err := calendarDownloadClient.Get(attachment.URL)
+	if err != nil {
+		log.Printf("reminder import download error: user_id=%s error=%v", userID, err)
+		responder.EditResponse(i, "Failed to download the calendar file.")
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("reminder import download error: user_id=%s status=%d", userID, resp.StatusCode)
+		responder.EditResponse(i, "Failed to download the calendar file.")
+		return
+	}
+
+	ctx := context.Background()
+	result, err := m.service.ImportICS(ctx, io.LimitReader(resp.Body, maxCalendarImportBytes), reminders.CreateReminderInput{
+		UserID:       userID,
+		ChannelID:    i.ChannelID,
+		GuildID:      i.GuildID,
+		Timezone:     userTimezone(ctx, m.settingsService, userID),
+		DeliveryMode: string(userDeliveryMode(ctx, m.settingsService, userID)),
+	})
+	if err != nil {
+		responder.EditResponse(i, err.Error())
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Imported %d reminder(s).", len(result.Created))
+	for _, r := range result.Created {
+		fmt.Fprintf(&b, "\n`%d` %s — next %s", r.ID, truncateText(r.Message, 60), formatReminderTime(r.NextRun))
+	}
+	b.WriteString(formatCalendarIssues("Changed", result.Notes))
+	b.WriteString(formatCalendarIssues("Not imported", result.Skipped))
+	responder.EditResponse(i, truncateText(b.String(), maxDiscordContentLength))
+}
+
+// formatCalendarIssues lists export or import problems under a heading.
+func formatCalendarIssues(heading string, issues []reminders.CalendarIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\n**%s:**", heading)
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "\n- %s: %s", truncateText(issue.Subject, 60), issue.Reason)
+	}
+	return b.String()
+}
+
+// truncateText shortens s to at most max runes, marking the cut with "…".
+func truncateText(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func (m *RemindModule) handleFeed(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user for this reminder.", true)
+		return
+	}
+	action := "show"
+	for _, o := range i.ApplicationCommandData().Options[0].Options {
+		if o.Name == "action" {
+			action = o.StringValue()
+		}
+	}
+	if !m.feeds.Available() {
+		responder.Respond(i, "Calendar feeds aren't configured on this bot.", true)
+		return
+	}
+
+	ctx := context.Background()
+	if action == "disable" {
+		removed, err := m.feeds.Disable(ctx, userID)
+		if err != nil {
+			log.Printf("reminder feed disable error: user_id=%s error=%v", userID, err)
+			responder.Respond(i, "Failed to disable your calendar feed.", true)
+			return
+		}
+		if !removed {
+			responder.Respond(i, "You don't have a calendar feed.", true)
+			return
+		}
+		responder.Respond(i, "Calendar feed disabled. The old URL no longer works.", true)
+		return
+	}
+
+	url, err := m.feeds.Enable(ctx, userID, action == "rotate")
+	if err != nil {
+		log.Printf("reminder feed publish error: user_id=%s error=%v", userID, err)
+		responder.Respond(i, "Failed to publish your calendar feed.", true)
+		return
+	}
+	content := "Subscribe to this URL in Google or Apple Calendar to see your upcoming reminders. Keep it private; anyone with the link can read it."
+	if action == "rotate" {
+		content = "Your calendar feed has a new URL; the old one no longer updates. Keep it private."
+	}
+	responder.Respond(i, content+"\n"+url, true)
+}
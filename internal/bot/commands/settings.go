@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"mizubot-go/internal/reminders"
 	"mizubot-go/internal/usersettings"
 
 	"github.com/bwmarrin/discordgo"
@@ -45,6 +46,26 @@ func (m *SettingsModule) Definitions() []*discordgo.ApplicationCommand {
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "delivery",
+					Description: "Manage where your reminders are sent by default",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "get",
+							Description: "Show your default reminder delivery",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "set",
+							Description: "Set your default reminder delivery",
+							Options: []*discordgo.ApplicationCommandOption{
+								{Type: discordgo.ApplicationCommandOptionString, Name: "mode", Description: "Where reminders go", Required: true, Choices: deliveryModeChoices},
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -60,22 +81,27 @@ func (m *SettingsModule) Handle(responder Responder, _ *discordgo.Session, i *di
 		responder.Respond(i, "Missing settings group.", true)
 		return true
 	}
-	if options[0].Name != "timezone" {
+	group := options[0].Name
+	if group != "timezone" && group != "delivery" {
 		responder.Respond(i, "Unknown settings group.", true)
 		return true
 	}
 	if len(options[0].Options) == 0 {
-		responder.Respond(i, "Missing timezone subcommand.", true)
+		responder.Respond(i, fmt.Sprintf("Missing %s subcommand.", group), true)
 		return true
 	}
 
-	switch options[0].Options[0].Name {
-	case "get":
+	switch group + " " + options[0].Options[0].Name {
+	case "timezone get":
 		m.handleTimezoneGet(responder, i)
-	case "set":
+	case "timezone set":
 		m.handleTimezoneSet(responder, i, options[0].Options[0])
+	case "delivery get":
+		m.handleDeliveryGet(responder, i)
+	case "delivery set":
+		m.handleDeliverySet(responder, i, options[0].Options[0])
 	default:
-		responder.Respond(i, "Unknown timezone subcommand.", true)
+		responder.Respond(i, fmt.Sprintf("Unknown %s subcommand.", group), true)
 	}
 	return true
 }
@@ -130,3 +156,69 @@ func (m *SettingsModule) handleTimezoneSet(responder Responder, i *discordgo.Int
 		},
 	}, true)
 }
+
+func (m *SettingsModule) handleDeliveryGet(responder Responder, i *discordgo.InteractionCreate) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user.", true)
+		return
+	}
+
+	mode, configured, err := m.service.GetDeliveryMode(context.Background(), userID)
+	if err != nil {
+		responder.Respond(i, "Failed to load delivery setting.", true)
+		return
+	}
+	description := fmt.Sprintf("New reminders are delivered by default as: %s.", describeDeliveryMode(mode))
+	if !configured {
+		description = fmt.Sprintf("No default is configured. New reminders are delivered as: %s.", describeDeliveryMode(mode))
+	}
+	responder.RespondEmbed(i, &discordgo.MessageEmbed{
+		Title:       "Reminder Delivery",
+		Color:       settingsEmbedColor,
+		Description: description,
+	}, true)
+}
+
+func (m *SettingsModule) handleDeliverySet(responder Responder, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	userID := userIDFromInteraction(i)
+	if userID == "" {
+		responder.Respond(i, "Unable to identify the user.", true)
+		return
+	}
+
+	var mode string
+	for _, opt := range sub.Options {
+		if opt.Name == "mode" {
+			mode = opt.StringValue()
+		}
+	}
+
+	settings, err := m.service.SetDeliveryMode(context.Background(), userID, mode)
+	if err != nil {
+		responder.Respond(i, err.Error(), true)
+		return
+	}
+	responder.RespondEmbed(i, &discordgo.MessageEmbed{
+		Title:       "Reminder Delivery Updated",
+		Color:       settingsEmbedColor,
+		Description: "New reminders are delivered by default as: " + describeDeliveryMode(reminders.DeliveryMode(settings.DeliveryMode)) + ". Existing reminders keep their mode.",
+	}, true)
+}
+
+var deliveryModeChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "in the channel", Value: string(reminders.DeliveryChannel)},
+	{Name: "by DM", Value: string(reminders.DeliveryDM)},
+	{Name: "in the channel, DM if that fails", Value: string(reminders.DeliveryFallback)},
+}
+
+func describeDeliveryMode(mode reminders.DeliveryMode) string {
+	switch mode {
+	case reminders.DeliveryDM:
+		return "DM"
+	case reminders.DeliveryFallback:
+		return "channel, with DM fallback"
+	default:
+		return "channel"
+	}
+}
@@ -10,8 +10,10 @@ import (
 
 	"mizubot-go/internal/animefeed"
 	"mizubot-go/internal/bot/commands"
+	"mizubot-go/internal/lifecycle"
 	"mizubot-go/internal/llm"
 	"mizubot-go/internal/llmstats"
+	"mizubot-go/internal/memories"
 	"mizubot-go/internal/pagemonitor"
 	"mizubot-go/internal/reminders"
 	"mizubot-go/internal/usersettings"
@@ -45,6 +47,17 @@ type Bot struct {
 	llm          *llm.Service
 	llmLogger    llmMessageLogger
 	userSettings *usersettings.Service
+	// replies tracks LLM replies in progress so shutdown can let them
+	// finish before the session closes.
+	replies lifecycle.Group
+	// replyGate, when set, must return true for this instance to answer
+	// mentions, slash commands and buttons; a leader-election standby
+	// leaves them to the leader.
+	replyGate func() bool
+	// threadSummaries and summarizer, when set, condense reply chains
+	// longer than the history window.
+	threadSummaries threadSummaryStore
+	summarizer      historySummarizer
 }
 
 func New(token string, store *reminders.Store, animeService *animefeed.Service, monitorService *pagemonitor.Service, llmService *llm.Service, userSettingsService *usersettings.Service, llmLogger llmMessageLogger) (*Bot, error) {
@@ -60,8 +73,10 @@ func New(token string, store *reminders.Store, animeService *animefeed.Service,
 	// Developer Portal (Bot > Privileged Gateway Intents), or the gateway
 	// will reject the connection with a disallowed-intents error.
 	s.Identify.Intents |= discordgo.IntentMessageContent
+	instrumentDiscordClient(s.Client)
 
 	reminderService := reminders.NewService(store)
+	reminderService.SetChannelAccess(commands.NewChannelAccess(s))
 	modules := []commands.Module{
 		commands.NewRemindModule(reminderService, userSettingsService),
 	}
@@ -69,7 +84,7 @@ func New(token string, store *reminders.Store, animeService *animefeed.Service,
 		modules = append(modules, commands.NewAnimeModule(animeService))
 	}
 	if monitorService != nil {
-		modules = append(modules, commands.NewMonitorModule(monitorService))
+		modules = append(modules, commands.NewMonitorModule(monitorService, userSettingsService))
 	}
 	if userSettingsService != nil {
 		modules = append(modules, commands.NewSettingsModule(userSettingsService))
@@ -91,6 +106,20 @@ func (b *Bot) Open() error {
 	return b.session.Open()
 }
 
+// Connected reports whether the Discord gateway session is ready.
+func (b *Bot) Connected() bool {
+	b.session.RLock()
+	defer b.session.RUnlock()
+	return b.session.DataReady
+}
+
+// StopReplies stops answering new mentions and waits for replies already
+// being generated or sent. It returns ctx.Err() if they don't finish in
+// time. Call it before Close so the replies can still be delivered.
+func (b *Bot) StopReplies(ctx context.Context) error {
+	return b.replies.Stop(ctx)
+}
+
 func (b *Bot) Close() error {
 	return b.session.Close()
 }
@@ -103,15 +132,182 @@ func (b *Bot) SendChannelMessage(channelID, content string) error {
 	return err
 }
 
-func (b *Bot) SendReminder(reminder reminders.Reminder) error {
+// SendReminder delivers a reminder according to its delivery mode. A
+// fallback reminder that can't be posted in its channel is sent to its owner
+// by DM instead, with a note saying why.
+func (b *Bot) SendReminder(reminder reminders.Reminder) (reminders.Receipt, error) {
+	if b.dryRun {
+		return reminders.Receipt{Via: reminders.DeliveryChannel}, nil
+	}
+	message, err := reminders.RenderMessage(reminder)
+	if err != nil {
+		// Templates are validated on create; send the raw text rather than nothing.
+		log.Printf("render reminder %d template error: %v", reminder.ID, err)
+		message = reminder.Message
+	}
+	notes := reminderNotes(reminder)
+	if reminder.DeliveryMode == reminders.DeliveryDM {
+		return reminders.Receipt{Via: reminders.DeliveryDM}, b.sendReminderDM(reminder, message+notes)
+	}
+
+	posts := reminderPosts(reminder, message+notes)
+	_, err = b.session.ChannelMessageSendComplex(reminder.ChannelID, posts[0])
+	if err == nil {
+		for _, post := range posts[1:] {
+			// The reminder itself went out; a lost overflow batch of
+			// mentions is not worth resending everything for.
+			if _, err := b.session.ChannelMessageSendComplex(reminder.ChannelID, post); err != nil {
+				log.Printf("send reminder %d mention batch error: %v", reminder.ID, err)
+			}
+		}
+		return reminders.Receipt{Via: reminders.DeliveryChannel}, nil
+	}
+	if reminder.DeliveryMode != reminders.DeliveryFallback {
+		return reminders.Receipt{}, err
+	}
+
+	reason := fmt.Sprintf("could not post in <#%s>: %v", reminder.ChannelID, err)
+	if dmErr := b.sendReminderDM(reminder, message+notes+"\n-# (sent by DM because I "+reason+")"); dmErr != nil {
+		return reminders.Receipt{}, fmt.Errorf("%v; DM fallback failed: %w", err, dmErr)
+	}
+	return reminders.Receipt{Via: reminders.DeliveryDM, FallbackReason: reason}, nil
+}
+
+// maxMentionsPerMessage is Discord's cap on user IDs in allowed_mentions.
+const maxMentionsPerMessage = 100
+
+// reminderPosts builds the channel messages for one delivery. Shared
+// reminders mention every subscriber, so their mentions are split into
+// batches that fit Discord's message length and mention limits; the first
+// message carries the reminder text and buttons.
+func reminderPosts(reminder reminders.Reminder, body string) []*discordgo.MessageSend {
+	if !reminder.Shared {
+		mention := "<@" + reminder.UserID + ">"
+		if !reminder.Mentions.IsZero() {
+			mention = reminder.Mentions.String()
+		}
+		return []*discordgo.MessageSend{{
+			Content:         mention + "\n\n" + body,
+			AllowedMentions: reminderAllowedMentions(reminder),
+			Components:      commands.ReminderActionComponents(reminder.ID),
+		}}
+	}
+
+	const maxDiscordMessageLength = 2000
+	first := &discordgo.MessageSend{
+		Content:         body,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Components:      commands.SharedReminderComponents(reminder.ID),
+	}
+	posts := []*discordgo.MessageSend{first}
+	rest := reminder.Subscribers
+	if budget := maxDiscordMessageLength - len([]rune(body)) - 2; budget > 0 {
+		batch, remaining := nextMentionBatch(rest, budget)
+		if len(batch) > 0 {
+			first.Content = mentionLine(batch) + "\n\n" + body
+			first.AllowedMentions.Users = batch
+		}
+		rest = remaining
+	}
+	for len(rest) > 0 {
+		batch, remaining := nextMentionBatch(rest, maxDiscordMessageLength)
+		if len(batch) == 0 {
+			break
+		}
+		posts = append(posts, &discordgo.MessageSend{
+			Content:         mentionLine(batch),
+			AllowedMentions: &discordgo.MessageAllowedMentions{Users: batch},
+		})
+		rest = remaining
+	}
+	return posts
+}
+
+// nextMentionBatch takes as many user IDs from the front of userIDs as fit
+// in budget characters of mentions and Discord's per-message mention cap.
+func nextMentionBatch(userIDs []string, budget int) (batch, rest []string) {
+	used := 0
+	n := 0
+	for n < len(userIDs) && n < maxMentionsPerMessage {
+		size := len("<@>") + len(userIDs[n])
+		if n > 0 {
+			size++ // separating space
+		}
+		if used+size > budget {
+			break
+		}
+		used += size
+		n++
+	}
+	return userIDs[:n], userIDs[n:]
+}
+
+func mentionLine(userIDs []string) string {
+	mentions := make([]string, len(userIDs))
+	for idx, id := range userIDs {
+		mentions[idx] = "<@" + id + ">"
+	}
+	return strings.Join(mentions, " ")
+}
+
+// reminderNotes returns the subtext lines appended to a delivered reminder.
+func reminderNotes(reminder reminders.Reminder) string {
+	var notes string
+	if reminder.MissedRuns > 0 && reminder.CatchUp != reminders.CatchUpSkip {
+		notes += "\n-# (" + reminders.MissedRunsNote(reminder.MissedRuns) + ")"
+	}
+	if reminder.LastRun {
+		notes += "\n-# (last reminder)"
+	}
+	return notes
+}
+
+func (b *Bot) sendReminderDM(reminder reminders.Reminder, content string) error {
+	channel, err := b.session.UserChannelCreate(reminder.UserID)
+	if err != nil {
+		return err
+	}
+	_, err = b.session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Components:      commands.ReminderActionComponents(reminder.ID),
+	})
+	return err
+}
+
+// reminderAllowedMentions limits a delivery's pings to exactly the
+// reminder's mention target, or to its owner when it has none.
+func reminderAllowedMentions(reminder reminders.Reminder) *discordgo.MessageAllowedMentions {
+	target := reminder.Mentions
+	if target.IsZero() {
+		return &discordgo.MessageAllowedMentions{Users: []string{reminder.UserID}}
+	}
+	allowed := &discordgo.MessageAllowedMentions{Users: target.Users, Roles: target.Roles}
+	if target.Here {
+		// @here is governed by the "everyone" parse type.
+		allowed.Parse = []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone}
+	}
+	return allowed
+}
+
+// SendDeadReminderNotice DMs the owner of a reminder that stopped retrying
+// after repeated send failures, so they can fix the channel or remove it.
+func (b *Bot) SendDeadReminderNotice(reminder reminders.Reminder) error {
 	if b.dryRun {
 		return nil
 	}
-	_, err := b.session.ChannelMessageSendComplex(reminder.ChannelID, &discordgo.MessageSend{
-		Content: "<@" + reminder.UserID + ">\n\n" + reminder.Message,
-		AllowedMentions: &discordgo.MessageAllowedMentions{
-			Users: []string{reminder.UserID},
-		},
+	channel, err := b.session.UserChannelCreate(reminder.UserID)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf(
+		"Your reminder `%d` in <#%s> could not be delivered after %d attempts, so I stopped retrying it.\nReason: %s\nMessage: %s\n\nSnooze it below to try again once the problem is fixed, or remove it with `/remind delete id:%d`.",
+		reminder.ID, reminder.ChannelID, reminder.FailureCount, reminder.LastError, reminder.Message, reminder.ID,
+	)
+	_, err = b.session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Components:      commands.ReminderActionComponents(reminder.ID),
 	})
 	return err
 }
@@ -203,12 +399,36 @@ func (b *Bot) RegisterCommandsForGuild(guildID string) error {
 	return b.registrar.RegisterCommands(b.session.State.User.ID, guildID, b.commandDefinitions())
 }
 
+// ChannelAccess checks a user's rights in the bot's channels, for
+// reminder services built outside the bot.
+func (b *Bot) ChannelAccess() reminders.ChannelAccess {
+	return commands.NewChannelAccess(b.session)
+}
+
 func (b *Bot) SetDryRun(d bool) { b.dryRun = d }
 
+// SetReminderFeeds enables /remind feed.
+func (b *Bot) SetReminderFeeds(feeds *reminders.FeedService) {
+	for _, module := range b.modules {
+		if remind, ok := module.(*commands.RemindModule); ok {
+			remind.SetFeedService(feeds)
+		}
+	}
+}
+
+// SetMemoryStore enables /memory.
+func (b *Bot) SetMemoryStore(store *memories.Store) {
+	b.modules = append(b.modules, commands.NewMemoryModule(store))
+}
+
 // SetDebugHistory toggles verbose logging of the conversation history
 // (path used, message count, and each history entry) built for LLM requests.
 func (b *Bot) SetDebugHistory(d bool) { b.debugHistory = d }
 
+// SetReplyGate limits LLM replies and interaction handling to when gate
+// returns true.
+func (b *Bot) SetReplyGate(gate func() bool) { b.replyGate = gate }
+
 func (b *Bot) commandDefinitions() []*discordgo.ApplicationCommand {
 	defs := make([]*discordgo.ApplicationCommand, 0, len(b.modules))
 	for _, module := range b.modules {
@@ -218,12 +438,22 @@ func (b *Bot) commandDefinitions() []*discordgo.ApplicationCommand {
 }
 
 func (b *Bot) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.Type != discordgo.InteractionApplicationCommand {
+	if b.replyGate != nil && !b.replyGate() {
 		return
 	}
-	for _, module := range b.modules {
-		if module.Handle(b, s, i) {
-			return
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		for _, module := range b.modules {
+			if module.Handle(b, s, i) {
+				return
+			}
+		}
+	case discordgo.InteractionMessageComponent:
+		for _, module := range b.modules {
+			handler, ok := module.(commands.ComponentHandler)
+			if ok && handler.HandleComponent(b, s, i) {
+				return
+			}
 		}
 	}
 }
@@ -241,8 +471,18 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 	if b.dryRun {
 		return
 	}
+	if b.replyGate != nil && !b.replyGate() {
+		return
+	}
+	if !b.replies.Begin() {
+		return
+	}
+	defer b.replies.Done()
 
 	response := "Hello"
+	// reply is set when the answer streams into a placeholder message
+	// instead of being sent once it is complete.
+	var reply *streamingReply
 	if b.llm != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		stopTyping := b.startTyping(ctx, s, m.ChannelID)
@@ -250,9 +490,19 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 		log.Printf("generating llm response: channel_id=%s user_id=%s message_id=%s", m.ChannelID, m.Author.ID, m.ID)
 		startedAt := time.Now()
 		timezone := b.userTimezoneForMessage(ctx, m.Author.ID)
-		history := buildConversationHistory(s, s, m.Message)
+		limits := historyLimitsFor(b.llm.ContextWindow(ctx, llm.Message{ChannelID: m.ChannelID, GuildID: m.GuildID}))
+		history, older := resolveConversationHistory(s, s, m.Message, limits)
+		if summary, ok := b.summarizeOlderTurns(ctx, s, s, m.Message, older, limits); ok {
+			history = append([]llm.HistoryMessage{summary}, history...)
+		}
 		debugLogHistory(b.debugHistory, m.ChannelID, m.ID, historySourcePath(m.Message), history)
-		generated, err := b.llm.GenerateResponseWithMetrics(ctx, llm.Message{
+		var onText func(string)
+		if b.llm.Streams() {
+			reply = newStreamingReply(s, m.ChannelID, m.Reference())
+			reply.start()
+			onText = reply.update
+		}
+		generated, err := b.llm.GenerateResponseStream(ctx, llm.Message{
 			UserID:    m.Author.ID,
 			Username:  guildDisplayName(s, m.GuildID, m.Author, m.Member),
 			BotName:   guildDisplayName(s, m.GuildID, s.State.User, nil),
@@ -262,12 +512,13 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 			Timezone:  timezone,
 			Now:       startedAt,
 			History:   history,
-		})
+		}, onText)
 		latency := time.Since(startedAt)
+		debugLogPromptFit(b.debugHistory, m.ChannelID, m.ID, generated)
 		if err != nil {
 			log.Printf("llm response generation failed: channel_id=%s user_id=%s message_id=%s error=%v", m.ChannelID, m.Author.ID, m.ID, err)
 			response = "I couldn't generate a response right now."
-			b.logLLMMessage(ctx, m, llm.Response{}, latency, llmstats.StatusError, err.Error())
+			b.logLLMMessage(ctx, m, generated, latency, llmstats.StatusError, err.Error())
 		} else if generated.Content != "" {
 			response = generated.Content
 			b.logLLMMessage(ctx, m, generated, latency, llmstats.StatusSuccess, "")
@@ -280,6 +531,10 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 	} else {
 		log.Printf("llm service not configured; using fallback response: channel_id=%s user_id=%s message_id=%s", m.ChannelID, m.Author.ID, m.ID)
 	}
+	if reply != nil {
+		reply.finish(response)
+		return
+	}
 	responses := splitDiscordMessages(response)
 	for idx, part := range responses {
 		var err error
@@ -324,6 +579,7 @@ func (b *Bot) logLLMMessage(ctx context.Context, m *discordgo.MessageCreate, res
 		Latency:          latency,
 		Status:           status,
 		Error:            errText,
+		Model:            response.Model,
 	})
 	if err != nil {
 		log.Printf("llm message log failed: channel_id=%s user_id=%s message_id=%s error=%v", m.ChannelID, m.Author.ID, m.ID, err)
@@ -390,6 +646,51 @@ func (b *Bot) RespondEmbed(i *discordgo.InteractionCreate, embed *discordgo.Mess
 	})
 }
 
+func (b *Bot) RespondFile(i *discordgo.InteractionCreate, content string, file *discordgo.File, ephemeral bool) {
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+	_ = b.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         content,
+			Files:           []*discordgo.File{file},
+			Flags:           flags,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+func (b *Bot) DeferResponse(i *discordgo.InteractionCreate, ephemeral bool) {
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+	_ = b.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+}
+
+func (b *Bot) EditResponse(i *discordgo.InteractionCreate, content string) {
+	_, _ = b.session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:         &content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+}
+
+func (b *Bot) UpdateComponentMessage(i *discordgo.InteractionCreate, content string) {
+	_ = b.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:         content,
+			Components:      []discordgo.MessageComponent{},
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
 func messageMentionsUser(content, userID string) bool {
 	return strings.Contains(content, "<@"+userID+">") || strings.Contains(content, "<@!"+userID+">")
 }
@@ -2,9 +2,13 @@ package bot
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
+	"mizubot-go/internal/reminders"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -126,3 +130,65 @@ func TestSplitDiscordMessages(t *testing.T) {
 		t.Fatalf("second part = %q, want y run", got[1])
 	}
 }
+
+func TestReminderAllowedMentions(t *testing.T) {
+	owner := reminderAllowedMentions(reminders.Reminder{UserID: "u1"})
+	if len(owner.Users) != 1 || owner.Users[0] != "u1" || len(owner.Roles) != 0 || len(owner.Parse) != 0 {
+		t.Fatalf("owner mentions = %+v, want only u1", owner)
+	}
+
+	target := reminderAllowedMentions(reminders.Reminder{UserID: "u1", Mentions: reminders.MentionTarget{Users: []string{"u2"}, Roles: []string{"r1"}, Here: true}})
+	if len(target.Users) != 1 || target.Users[0] != "u2" {
+		t.Fatalf("users = %v, want [u2]", target.Users)
+	}
+	if len(target.Roles) != 1 || target.Roles[0] != "r1" {
+		t.Fatalf("roles = %v, want [r1]", target.Roles)
+	}
+	if len(target.Parse) != 1 || target.Parse[0] != discordgo.AllowedMentionTypeEveryone {
+		t.Fatalf("parse = %v, want [everyone] for @here", target.Parse)
+	}
+}
+
+func TestReminderPostsBatchesSharedSubscribers(t *testing.T) {
+	subscribers := make([]string, 250)
+	for i := range subscribers {
+		subscribers[i] = fmt.Sprintf("1000000000000%05d", i)
+	}
+	posts := reminderPosts(reminders.Reminder{ID: 7, UserID: subscribers[0], Shared: true, Subscribers: subscribers}, "raid night")
+	if len(posts) < 3 {
+		t.Fatalf("posts = %d, want at least 3 for 250 subscribers", len(posts))
+	}
+	if len(posts[0].Components) == 0 || !strings.HasSuffix(posts[0].Content, "raid night") {
+		t.Fatalf("first post = %+v, want message body and buttons", posts[0])
+	}
+	var mentioned []string
+	for i, post := range posts {
+		if n := len([]rune(post.Content)); n > 2000 {
+			t.Fatalf("post %d has %d characters", i, n)
+		}
+		if len(post.AllowedMentions.Users) > maxMentionsPerMessage {
+			t.Fatalf("post %d allows %d mentions", i, len(post.AllowedMentions.Users))
+		}
+		if i > 0 && len(post.Components) != 0 {
+			t.Fatalf("post %d repeats buttons", i)
+		}
+		mentioned = append(mentioned, post.AllowedMentions.Users...)
+	}
+	if len(mentioned) != len(subscribers) {
+		t.Fatalf("mentioned %d users, want %d", len(mentioned), len(subscribers))
+	}
+}
+
+func TestDiscordRouteCollapsesIDsAndTokens(t *testing.T) {
+	cases := map[string]string{
+		"https://discord.com/api/v9/channels/123456789012345678/messages":                            "POST /channels/:id/messages",
+		"https://discord.com/api/v9/webhooks/123/" + strings.Repeat("a", 64) + "/messages/@original": "POST /webhooks/:id/:token/messages/@original",
+		"https://discord.com/api/v9/users/@me/channels":                                              "POST /users/@me/channels",
+	}
+	for url, want := range cases {
+		req, _ := http.NewRequest(http.MethodPost, url, nil)
+		if got := discordRoute(req); got != want {
+			t.Errorf("discordRoute(%s) = %q, want %q", url, got, want)
+		}
+	}
+}
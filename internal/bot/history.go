@@ -10,12 +10,39 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-const (
-	maxReplyChainHistory    = 10
-	maxChannelBufferHistory = 8
-	maxHistoryMessageChars  = 500
+// historyLimits bound how much history is fetched from Discord. The llm
+// service then trims it further to fit the routed model's prompt budget.
+type historyLimits struct {
+	replyChainHops int
+	channelBuffer  int
+	messageChars   int
+}
+
+// defaultHistoryLimits suit llm.DefaultContextWindow. Larger windows scale
+// them up to maxHistoryLimits: every reply-chain hop may cost a Discord API
+// call, and a Discord message is at most 4000 characters.
+var (
+	defaultHistoryLimits = historyLimits{replyChainHops: 10, channelBuffer: 8, messageChars: 500}
+	maxHistoryLimits     = historyLimits{replyChainHops: 40, channelBuffer: 50, messageChars: 4000}
 )
 
+// historyLimitsFor scales defaultHistoryLimits with a model's context window
+// in tokens. Windows up to llm.DefaultContextWindow, or unknown (0), get the
+// defaults.
+func historyLimitsFor(window int) historyLimits {
+	if window <= llm.DefaultContextWindow {
+		return defaultHistoryLimits
+	}
+	scale := func(base, max int) int {
+		return min(base*window/llm.DefaultContextWindow, max)
+	}
+	return historyLimits{
+		replyChainHops: scale(defaultHistoryLimits.replyChainHops, maxHistoryLimits.replyChainHops),
+		channelBuffer:  scale(defaultHistoryLimits.channelBuffer, maxHistoryLimits.channelBuffer),
+		messageChars:   scale(defaultHistoryLimits.messageChars, maxHistoryLimits.messageChars),
+	}
+}
+
 // messageHistoryFetcher covers the discordgo.Session REST methods used to
 // resolve conversation history, so tests can supply a stub instead of
 // hitting the Discord API.
@@ -25,17 +52,25 @@ type messageHistoryFetcher interface {
 }
 
 // buildConversationHistory resolves prior conversation context for the
-// triggering message: if the message is a reply, it walks the reply chain
-// up to maxReplyChainHistory hops; otherwise it falls back to the last
-// messages in the channel.
-func buildConversationHistory(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message) []llm.HistoryMessage {
+// triggering message within limits: if the message is a reply, it walks the
+// reply chain up to limits.replyChainHops hops; otherwise it falls back to
+// the last messages in the channel.
+func buildConversationHistory(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message, limits historyLimits) []llm.HistoryMessage {
+	history, _ := resolveConversationHistory(s, fetcher, msg, limits)
+	return history
+}
+
+// resolveConversationHistory is buildConversationHistory that also returns
+// the reference to the newest reply-chain ancestor left out of the history,
+// or nil when the whole chain fit.
+func resolveConversationHistory(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message, limits historyLimits) ([]llm.HistoryMessage, *discordgo.MessageReference) {
 	if fetcher == nil || msg == nil {
-		return nil
+		return nil, nil
 	}
 	if isReply(msg) {
-		return historyFromReplyChain(s, fetcher, msg)
+		return historyFromReplyChain(s, fetcher, msg, limits)
 	}
-	return historyFromChannelBuffer(s, fetcher, msg)
+	return historyFromChannelBuffer(s, fetcher, msg, limits), nil
 }
 
 func isReply(msg *discordgo.Message) bool {
@@ -89,63 +124,123 @@ func debugLogHistory(enabled bool, channelID, messageID, path string, history []
 	}
 	log.Printf("llm history debug: channel_id=%s message_id=%s path=%s count=%d", channelID, messageID, path, len(history))
 	for i, h := range history {
-		role := "user"
-		if h.IsBot {
-			role = "bot"
-		}
 		log.Printf("llm history debug: channel_id=%s message_id=%s idx=%d role=%s author=%s content=%q",
-			channelID, messageID, i, role, h.Author, truncate(h.Content, maxDebugHistoryContentChars))
+			channelID, messageID, i, historyRole(h), h.Author, truncate(h.Content, maxDebugHistoryContentChars))
 	}
 }
 
-func historyFromReplyChain(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message) []llm.HistoryMessage {
-	chain := make([]*discordgo.Message, 0, maxReplyChainHistory)
-	ref := msg.MessageReference
-	resolved := msg.ReferencedMessage
-	seen := map[string]bool{msg.ID: true}
+// debugLogPromptFit logs, when enabled, how the history fitted the routed
+// model's prompt budget and each turn dropped to make it fit.
+func debugLogPromptFit(enabled bool, channelID, messageID string, response llm.Response) {
+	fit := response.Prompt
+	if !enabled || fit.Window == 0 {
+		return
+	}
+	log.Printf("llm history debug: channel_id=%s message_id=%s model=%s window=%d fixed_tokens=%d history_tokens=%d dropped=%d summarized=%t",
+		channelID, messageID, response.Model, fit.Window, fit.FixedTokens, fit.HistoryTokens, len(fit.Dropped), fit.Summarized)
+	for i, h := range fit.Dropped {
+		log.Printf("llm history debug: channel_id=%s message_id=%s dropped_idx=%d role=%s author=%s content=%q",
+			channelID, messageID, i, historyRole(h), h.Author, truncate(h.Content, maxDebugHistoryContentChars))
+	}
+}
 
-	for i := 0; i < maxReplyChainHistory && ref != nil && ref.MessageID != ""; i++ {
-		if seen[ref.MessageID] {
-			break
-		}
+func historyRole(h llm.HistoryMessage) string {
+	switch {
+	case h.IsSummary:
+		return "summary"
+	case h.IsBot:
+		return "bot"
+	}
+	return "user"
+}
 
-		var parent *discordgo.Message
-		if resolved != nil && resolved.ID == ref.MessageID {
-			parent = resolved
-		} else {
-			channelID := ref.ChannelID
-			if channelID == "" {
-				channelID = msg.ChannelID
-			}
-			fetched, err := fetcher.ChannelMessage(channelID, ref.MessageID)
-			if err != nil {
-				log.Printf("fetch reply-chain ancestor failed: channel_id=%s message_id=%s error=%v", channelID, ref.MessageID, err)
-				break
-			}
-			parent = fetched
-		}
+func historyFromReplyChain(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message, limits historyLimits) ([]llm.HistoryMessage, *discordgo.MessageReference) {
+	walk := newReplyChainWalk(fetcher, msg)
+	chain := make([]*discordgo.Message, 0, limits.replyChainHops)
+	for i := 0; i < limits.replyChainHops; i++ {
+		parent := walk.next()
 		if parent == nil {
 			break
 		}
-
-		seen[parent.ID] = true
 		chain = append(chain, parent)
-		ref = parent.MessageReference
-		resolved = parent.ReferencedMessage
 	}
 
 	reverseMessages(chain)
-	return historyMessagesFromDiscord(s, msg.GuildID, chain, false)
+	return historyMessagesFromDiscord(s, msg.GuildID, chain, false, limits.messageChars), walk.pending()
+}
+
+// replyChainWalk steps up a reply chain one ancestor at a time, using the
+// gateway-resolved parent when there is one and fetching it otherwise.
+type replyChainWalk struct {
+	fetcher  messageHistoryFetcher
+	channel  string
+	ref      *discordgo.MessageReference
+	resolved *discordgo.Message
+	seen     map[string]bool
+	done     bool
+}
+
+func newReplyChainWalk(fetcher messageHistoryFetcher, msg *discordgo.Message) *replyChainWalk {
+	return &replyChainWalk{
+		fetcher:  fetcher,
+		channel:  msg.ChannelID,
+		ref:      msg.MessageReference,
+		resolved: msg.ReferencedMessage,
+		seen:     map[string]bool{msg.ID: true},
+	}
+}
+
+// pending is the reference to the next ancestor, or nil once the chain has
+// ended or can't be followed further.
+func (w *replyChainWalk) pending() *discordgo.MessageReference {
+	if w.done || w.ref == nil || w.ref.MessageID == "" || w.seen[w.ref.MessageID] {
+		return nil
+	}
+	return w.ref
+}
+
+// next returns the next ancestor, or nil at the end of the chain.
+func (w *replyChainWalk) next() *discordgo.Message {
+	ref := w.pending()
+	if ref == nil {
+		return nil
+	}
+
+	var parent *discordgo.Message
+	if w.resolved != nil && w.resolved.ID == ref.MessageID {
+		parent = w.resolved
+	} else {
+		channelID := ref.ChannelID
+		if channelID == "" {
+			channelID = w.channel
+		}
+		fetched, err := w.fetcher.ChannelMessage(channelID, ref.MessageID)
+		if err != nil {
+			log.Printf("fetch reply-chain ancestor failed: channel_id=%s message_id=%s error=%v", channelID, ref.MessageID, err)
+			w.done = true
+			return nil
+		}
+		parent = fetched
+	}
+	if parent == nil {
+		w.done = true
+		return nil
+	}
+
+	w.seen[parent.ID] = true
+	w.ref = parent.MessageReference
+	w.resolved = parent.ReferencedMessage
+	return parent
 }
 
-func historyFromChannelBuffer(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message) []llm.HistoryMessage {
-	fetched, err := fetcher.ChannelMessages(msg.ChannelID, maxChannelBufferHistory, msg.ID, "", "")
+func historyFromChannelBuffer(s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message, limits historyLimits) []llm.HistoryMessage {
+	fetched, err := fetcher.ChannelMessages(msg.ChannelID, limits.channelBuffer, msg.ID, "", "")
 	if err != nil {
 		log.Printf("fetch channel history failed: channel_id=%s error=%v", msg.ChannelID, err)
 		return nil
 	}
 	reverseMessages(fetched)
-	return historyMessagesFromDiscord(s, msg.GuildID, fetched, true)
+	return historyMessagesFromDiscord(s, msg.GuildID, fetched, true, limits.messageChars)
 }
 
 // truncateMiddle caps content to roughly max runes by cutting out of the
@@ -175,10 +270,11 @@ func reverseMessages(messages []*discordgo.Message) {
 }
 
 // historyMessagesFromDiscord converts discordgo messages (already in
-// chronological order) into llm.HistoryMessage entries. When
-// filterOtherBots is set, messages from bots other than this one are
-// dropped, while this bot's own prior replies are kept for continuity.
-func historyMessagesFromDiscord(s *discordgo.Session, guildID string, messages []*discordgo.Message, filterOtherBots bool) []llm.HistoryMessage {
+// chronological order) into llm.HistoryMessage entries of at most maxChars
+// characters each. When filterOtherBots is set, messages from bots other
+// than this one are dropped, while this bot's own prior replies are kept
+// for continuity.
+func historyMessagesFromDiscord(s *discordgo.Session, guildID string, messages []*discordgo.Message, filterOtherBots bool, maxChars int) []llm.HistoryMessage {
 	var botUserID string
 	if s != nil && s.State != nil && s.State.User != nil {
 		botUserID = s.State.User.ID
@@ -201,7 +297,7 @@ func historyMessagesFromDiscord(s *discordgo.Session, guildID string, messages [
 
 		out = append(out, llm.HistoryMessage{
 			Author:  guildDisplayName(s, guildID, dm.Author, dm.Member),
-			Content: truncateMiddle(content, maxHistoryMessageChars),
+			Content: truncateMiddle(content, maxChars),
 			IsBot:   isBotAuthor,
 		})
 	}
@@ -156,7 +156,7 @@ func TestReplyToBotWithoutMentionTriggersAndUsesReplyChainHistory(t *testing.T)
 	}
 
 	fetcher := &stubHistoryFetcher{}
-	history := buildConversationHistory(s, fetcher, userReply)
+	history := buildConversationHistory(s, fetcher, userReply, defaultHistoryLimits)
 	if len(history) != 1 || history[0].Content != "here's the answer" || !history[0].IsBot {
 		t.Fatalf("expected reply-chain history to include the bot's prior message, got %#v", history)
 	}
@@ -183,7 +183,7 @@ func TestBuildConversationHistoryUsesReplyChainWhenReply(t *testing.T) {
 
 	fetcher := &stubHistoryFetcher{messagesByID: map[string]*discordgo.Message{"msg1": msg1}}
 
-	history := buildConversationHistory(s, fetcher, msg3)
+	history := buildConversationHistory(s, fetcher, msg3, defaultHistoryLimits)
 
 	if len(history) != 2 {
 		t.Fatalf("history length = %d, want 2: %#v", len(history), history)
@@ -233,13 +233,13 @@ func TestHistoryFromReplyChainCapsHops(t *testing.T) {
 	}
 
 	fetcher := &stubHistoryFetcher{messagesByID: byID}
-	history := buildConversationHistory(s, fetcher, current)
+	history := buildConversationHistory(s, fetcher, current, defaultHistoryLimits)
 
-	if len(history) != maxReplyChainHistory {
-		t.Fatalf("history length = %d, want %d", len(history), maxReplyChainHistory)
+	if len(history) != defaultHistoryLimits.replyChainHops {
+		t.Fatalf("history length = %d, want %d", len(history), defaultHistoryLimits.replyChainHops)
 	}
-	// Oldest-first: the last maxReplyChainHistory ancestors, in ascending order.
-	firstExpected := "message " + idFor(total-maxReplyChainHistory+1)
+	// Oldest-first: the last replyChainHops ancestors, in ascending order.
+	firstExpected := "message " + idFor(total-defaultHistoryLimits.replyChainHops+1)
 	lastExpected := "message " + idFor(total)
 	if history[0].Content != firstExpected {
 		t.Fatalf("history[0].Content = %q, want %q", history[0].Content, firstExpected)
@@ -249,6 +249,24 @@ func TestHistoryFromReplyChainCapsHops(t *testing.T) {
 	}
 }
 
+func TestHistoryLimitsScaleWithContextWindow(t *testing.T) {
+	cases := []struct {
+		window int
+		want   historyLimits
+	}{
+		{0, defaultHistoryLimits},
+		{4096, defaultHistoryLimits},
+		{llm.DefaultContextWindow, defaultHistoryLimits},
+		{32768, historyLimits{replyChainHops: 40, channelBuffer: 32, messageChars: 2000}},
+		{131072, maxHistoryLimits},
+	}
+	for _, tc := range cases {
+		if got := historyLimitsFor(tc.window); got != tc.want {
+			t.Fatalf("historyLimitsFor(%d) = %+v, want %+v", tc.window, got, tc.want)
+		}
+	}
+}
+
 func idFor(i int) string {
 	return "msg" + string(rune('a'+i))
 }
@@ -267,14 +285,14 @@ func TestBuildConversationHistoryUsesChannelBufferWhenNotReply(t *testing.T) {
 		},
 	}
 
-	history := buildConversationHistory(s, fetcher, current)
+	history := buildConversationHistory(s, fetcher, current, defaultHistoryLimits)
 
 	if len(fetcher.channelMessagesCalls) != 1 {
 		t.Fatalf("expected exactly one ChannelMessages call, got %d", len(fetcher.channelMessagesCalls))
 	}
 	call := fetcher.channelMessagesCalls[0]
-	if call.channelID != "chan1" || call.limit != maxChannelBufferHistory || call.beforeID != "current" {
-		t.Fatalf("ChannelMessages called with %#v, want channelID=chan1 limit=%d beforeID=current", call, maxChannelBufferHistory)
+	if call.channelID != "chan1" || call.limit != defaultHistoryLimits.channelBuffer || call.beforeID != "current" {
+		t.Fatalf("ChannelMessages called with %#v, want channelID=chan1 limit=%d beforeID=current", call, defaultHistoryLimits.channelBuffer)
 	}
 	if len(fetcher.channelMessageCalls) != 0 {
 		t.Fatalf("reply-chain path should not be used, got calls: %#v", fetcher.channelMessageCalls)
@@ -310,7 +328,7 @@ func TestChannelBufferIncludesPlainChatterNotJustBotRelatedMessages(t *testing.T
 		},
 	}
 
-	history := buildConversationHistory(s, fetcher, current)
+	history := buildConversationHistory(s, fetcher, current, defaultHistoryLimits)
 
 	if len(history) != 3 {
 		t.Fatalf("history length = %d, want 3 (plain chatter + mention + bot reply, other-bot excluded): %#v", len(history), history)
@@ -335,13 +353,13 @@ func TestHistoryMessagesFromDiscordTruncatesLongContentInTheMiddle(t *testing.T)
 	s := newTestSession("bot1")
 	prefix := "START-" + strings.Repeat("a", 50)
 	suffix := strings.Repeat("b", 50) + "-END"
-	middle := strings.Repeat("z", maxHistoryMessageChars*2)
+	middle := strings.Repeat("z", defaultHistoryLimits.messageChars*2)
 	longContent := prefix + middle + suffix
 	messages := []*discordgo.Message{
 		{ID: "m1", Content: longContent, Author: &discordgo.User{ID: "user1", Username: "account1"}},
 	}
 
-	history := historyMessagesFromDiscord(s, "guild1", messages, false)
+	history := historyMessagesFromDiscord(s, "guild1", messages, false, defaultHistoryLimits.messageChars)
 
 	if len(history) != 1 {
 		t.Fatalf("history length = %d, want 1", len(history))
@@ -381,7 +399,7 @@ func TestTruncateMiddleKeepsPrefixAndSuffixWithMarker(t *testing.T) {
 }
 
 func TestBuildConversationHistoryHandlesNilFetcher(t *testing.T) {
-	if got := buildConversationHistory(nil, nil, &discordgo.Message{ID: "m1"}); got != nil {
+	if got := buildConversationHistory(nil, nil, &discordgo.Message{ID: "m1"}, defaultHistoryLimits); got != nil {
 		t.Fatalf("expected nil history with nil fetcher, got %#v", got)
 	}
 }
@@ -457,3 +475,24 @@ func TestDebugLogHistoryTruncatesLongContent(t *testing.T) {
 		t.Fatalf("expected truncated content to end with ellipsis: %q", out)
 	}
 }
+
+func TestDebugLogPromptFitLogsDroppedTurns(t *testing.T) {
+	response := llm.Response{Model: "llama3.2", Prompt: llm.PromptFit{
+		Window:     4096,
+		Dropped:    []llm.HistoryMessage{{Content: "earlier plans", IsSummary: true}, {Author: "Alice", Content: "first question"}},
+		Summarized: true,
+	}}
+	out := captureLogOutput(t, func() {
+		debugLogPromptFit(true, "chan1", "msg1", response)
+	})
+	if !strings.Contains(out, "model=llama3.2 window=4096") || !strings.Contains(out, "dropped=2 summarized=true") {
+		t.Fatalf("log output missing budget summary: %q", out)
+	}
+	if !strings.Contains(out, "dropped_idx=0 role=summary") || !strings.Contains(out, "dropped_idx=1 role=user author=Alice") {
+		t.Fatalf("log output missing dropped turns: %q", out)
+	}
+
+	if out := captureLogOutput(t, func() { debugLogPromptFit(true, "chan1", "msg1", llm.Response{}) }); out != "" {
+		t.Fatalf("expected no log output without a budget, got %q", out)
+	}
+}
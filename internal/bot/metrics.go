@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mizubot-go/internal/metrics"
+)
+
+// discordErrorTransport counts failed Discord REST calls by route and
+// status. Snowflake IDs and interaction tokens are collapsed so the route
+// label stays low-cardinality.
+type discordErrorTransport struct {
+	next http.RoundTripper
+}
+
+func (t discordErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		metrics.DiscordAPIErrors.Inc(discordRoute(req), "error")
+	case resp.StatusCode >= 400:
+		metrics.DiscordAPIErrors.Inc(discordRoute(req), strconv.Itoa(resp.StatusCode))
+	}
+	return resp, err
+}
+
+func instrumentDiscordClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = discordErrorTransport{next: next}
+}
+
+// discordRoute turns "/api/v9/channels/123/messages" into
+// "POST /channels/:id/messages".
+func discordRoute(req *http.Request) string {
+	path := req.URL.Path
+	if rest, ok := strings.CutPrefix(path, "/api/v"); ok {
+		if _, after, found := strings.Cut(rest, "/"); found {
+			path = "/" + after
+		}
+	}
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		switch {
+		case segment != "" && strings.Trim(segment, "0123456789") == "":
+			segments[idx] = ":id"
+		case len(segment) > 32:
+			segments[idx] = ":token"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
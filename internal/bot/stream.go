@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// streamPlaceholder is posted as the reply before any text has arrived.
+	streamPlaceholder = "…"
+	// streamEditInterval spaces out edits while a reply streams in. Discord
+	// allows about five message edits per five seconds in a channel, so
+	// this leaves room for overflow messages and other traffic.
+	streamEditInterval = 1500 * time.Millisecond
+)
+
+// replyMessenger is the part of the Discord session a streamed reply uses.
+type replyMessenger interface {
+	ChannelMessageSendReply(channelID, content string, reference *discordgo.MessageReference, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSend(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEdit(channelID, messageID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error
+}
+
+// streamingReply shows an LLM reply while it is generated: a placeholder
+// reply is edited as text arrives, and text past Discord's length limit
+// overflows into follow-up messages.
+type streamingReply struct {
+	messenger replyMessenger
+	channelID string
+	reference *discordgo.MessageReference
+	interval  time.Duration
+	now       func() time.Time
+
+	ids      []string // posted messages, reply first
+	shown    []string // content currently shown in each message
+	lastSync time.Time
+}
+
+func newStreamingReply(messenger replyMessenger, channelID string, reference *discordgo.MessageReference) *streamingReply {
+	return &streamingReply{
+		messenger: messenger,
+		channelID: channelID,
+		reference: reference,
+		interval:  streamEditInterval,
+		now:       time.Now,
+	}
+}
+
+// start posts the placeholder reply.
+func (r *streamingReply) start() {
+	r.sync([]string{streamPlaceholder})
+}
+
+// update shows the text generated so far, unless the last edit was too
+// recent; the next update or finish catches up. Empty text withdraws what
+// was shown, e.g. text that preceded a tool call or came from a model that
+// failed, so the placeholder is put back at once rather than leaving that
+// text up until the next model writes.
+func (r *streamingReply) update(text string) {
+	if strings.TrimSpace(text) == "" {
+		r.sync([]string{streamPlaceholder})
+		return
+	}
+	if r.now().Sub(r.lastSync) < r.interval {
+		return
+	}
+	r.sync(splitDiscordMessages(text))
+}
+
+// finish shows the complete reply and removes overflow messages it no
+// longer needs.
+func (r *streamingReply) finish(text string) {
+	r.sync(splitDiscordMessages(text))
+}
+
+func (r *streamingReply) sync(parts []string) {
+	r.lastSync = r.now()
+	for idx, part := range parts {
+		if idx < len(r.ids) {
+			if r.shown[idx] == part {
+				continue
+			}
+			if _, err := r.messenger.ChannelMessageEdit(r.channelID, r.ids[idx], part); err != nil {
+				log.Printf("discord reply edit failed: channel_id=%s message_id=%s part=%d error=%v", r.channelID, r.ids[idx], idx+1, err)
+				continue
+			}
+			r.shown[idx] = part
+			continue
+		}
+		var msg *discordgo.Message
+		var err error
+		if idx == 0 {
+			msg, err = r.messenger.ChannelMessageSendReply(r.channelID, part, r.reference)
+		} else {
+			msg, err = r.messenger.ChannelMessageSend(r.channelID, part)
+		}
+		if err != nil {
+			// Later parts would arrive out of order; retry on the next sync.
+			log.Printf("discord reply send failed: channel_id=%s part=%d total_parts=%d error=%v", r.channelID, idx+1, len(parts), err)
+			return
+		}
+		r.ids = append(r.ids, msg.ID)
+		r.shown = append(r.shown, part)
+	}
+	for len(r.ids) > len(parts) {
+		last := len(r.ids) - 1
+		if err := r.messenger.ChannelMessageDelete(r.channelID, r.ids[last]); err != nil {
+			log.Printf("discord reply delete failed: channel_id=%s message_id=%s error=%v", r.channelID, r.ids[last], err)
+		}
+		r.ids, r.shown = r.ids[:last], r.shown[:last]
+	}
+}
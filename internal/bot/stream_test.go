@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type fakeMessenger struct {
+	next     int
+	contents map[string]string
+	order    []string
+	edits    int
+	deleted  []string
+	replyTo  string
+}
+
+func newFakeMessenger() *fakeMessenger {
+	return &fakeMessenger{contents: map[string]string{}}
+}
+
+func (f *fakeMessenger) post(content string) *discordgo.Message {
+	f.next++
+	id := fmt.Sprintf("m%d", f.next)
+	f.contents[id] = content
+	f.order = append(f.order, id)
+	return &discordgo.Message{ID: id}
+}
+
+func (f *fakeMessenger) ChannelMessageSendReply(_, content string, reference *discordgo.MessageReference, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.replyTo = reference.MessageID
+	return f.post(content), nil
+}
+
+func (f *fakeMessenger) ChannelMessageSend(_, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return f.post(content), nil
+}
+
+func (f *fakeMessenger) ChannelMessageEdit(_, messageID, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.edits++
+	f.contents[messageID] = content
+	return &discordgo.Message{ID: messageID}, nil
+}
+
+func (f *fakeMessenger) ChannelMessageDelete(_, messageID string, _ ...discordgo.RequestOption) error {
+	f.deleted = append(f.deleted, messageID)
+	delete(f.contents, messageID)
+	return nil
+}
+
+func TestStreamingReplyThrottlesEdits(t *testing.T) {
+	messenger := newFakeMessenger()
+	now := time.Unix(0, 0)
+	reply := newStreamingReply(messenger, "c1", &discordgo.MessageReference{MessageID: "q1"})
+	reply.now = func() time.Time { return now }
+
+	reply.start()
+	if messenger.contents["m1"] != streamPlaceholder || messenger.replyTo != "q1" {
+		t.Fatalf("placeholder = %q reply_to=%q", messenger.contents["m1"], messenger.replyTo)
+	}
+	for _, text := range []string{"He", "Hello", "Hello wor"} {
+		now = now.Add(100 * time.Millisecond)
+		reply.update(text)
+	}
+	if messenger.edits != 0 {
+		t.Fatalf("edited %d times within the throttle interval", messenger.edits)
+	}
+	now = now.Add(streamEditInterval)
+	reply.update("Hello world")
+	if messenger.edits != 1 || messenger.contents["m1"] != "Hello world" {
+		t.Fatalf("edits=%d content=%q, want one edit to the latest text", messenger.edits, messenger.contents["m1"])
+	}
+	reply.finish("Hello world!")
+	if messenger.contents["m1"] != "Hello world!" || len(messenger.order) != 1 {
+		t.Fatalf("final = %q with %d messages", messenger.contents["m1"], len(messenger.order))
+	}
+}
+
+func TestStreamingReplyWithdrawsTextAtOnce(t *testing.T) {
+	messenger := newFakeMessenger()
+	now := time.Unix(0, 0)
+	reply := newStreamingReply(messenger, "c1", &discordgo.MessageReference{MessageID: "q1"})
+	reply.now = func() time.Time { return now }
+	reply.start()
+
+	// Nothing shown yet: withdrawing leaves the placeholder untouched.
+	reply.update("")
+	if messenger.edits != 0 {
+		t.Fatalf("edited %d times withdrawing nothing", messenger.edits)
+	}
+
+	paragraph := strings.Repeat("word ", 300)
+	now = now.Add(streamEditInterval)
+	reply.update(paragraph + "\n" + paragraph)
+	if len(messenger.order) != 2 {
+		t.Fatalf("posted %d messages, want the reply and one overflow", len(messenger.order))
+	}
+
+	// The turn became a tool call, or its model failed: the text goes
+	// right away, even within the throttle interval.
+	now = now.Add(100 * time.Millisecond)
+	reply.update("")
+	if messenger.contents["m1"] != streamPlaceholder || len(messenger.contents) != 1 || len(messenger.deleted) != 1 {
+		t.Fatalf("after withdrawal: reply=%.20q messages=%d deleted=%v", messenger.contents["m1"], len(messenger.contents), messenger.deleted)
+	}
+
+	// The next turn's text waits out the interval as usual.
+	now = now.Add(100 * time.Millisecond)
+	reply.update("Answer")
+	if messenger.contents["m1"] != streamPlaceholder {
+		t.Fatalf("reply = %q, want the placeholder until the interval passes", messenger.contents["m1"])
+	}
+	reply.finish("Answer")
+	if messenger.contents["m1"] != "Answer" {
+		t.Fatalf("final = %q", messenger.contents["m1"])
+	}
+}
+
+func TestStreamingReplyOverflowsIntoFollowUps(t *testing.T) {
+	messenger := newFakeMessenger()
+	reply := newStreamingReply(messenger, "c1", &discordgo.MessageReference{MessageID: "q1"})
+	reply.interval = 0
+	reply.start()
+
+	paragraph := strings.Repeat("word ", 300) // 1500 characters
+	long := paragraph + "\n" + paragraph + "\n" + paragraph
+	reply.update(long)
+	want := splitDiscordMessages(long)
+	if len(want) < 3 || len(messenger.order) != len(want) {
+		t.Fatalf("posted %d messages, want %d", len(messenger.order), len(want))
+	}
+	for idx, id := range messenger.order {
+		if messenger.contents[id] != want[idx] {
+			t.Fatalf("message %d = %.40q, want %.40q", idx, messenger.contents[id], want[idx])
+		}
+	}
+
+	// A final reply shorter than what streamed removes the extra messages.
+	reply.finish("short answer")
+	if messenger.contents["m1"] != "short answer" || len(messenger.contents) != 1 || len(messenger.deleted) != len(want)-1 {
+		t.Fatalf("after finish: contents=%d deleted=%v", len(messenger.contents), messenger.deleted)
+	}
+}
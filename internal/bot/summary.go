@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"log"
+
+	"mizubot-go/internal/llm"
+	"mizubot-go/internal/threadsummaries"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSummaryHops bounds how far above the history window a thread is read
+// when no earlier summary is found on the way.
+const maxSummaryHops = 40
+
+type threadSummaryStore interface {
+	GetByThrough(ctx context.Context, messageID string) (threadsummaries.Summary, bool, error)
+	Save(ctx context.Context, summary threadsummaries.Summary) error
+}
+
+type historySummarizer interface {
+	SummarizeHistory(ctx context.Context, previous string, turns []llm.HistoryMessage) (string, error)
+}
+
+// SetThreadSummaries condenses the part of a reply chain older than the
+// history window into a summary, cached per thread, that is sent as the
+// first history entry.
+func (b *Bot) SetThreadSummaries(store *threadsummaries.Store) {
+	b.threadSummaries = store
+	b.summarizer = b.llm
+}
+
+// summarizeOlderTurns returns a summary of the reply chain from older, the
+// newest ancestor left out of the history window, back to the thread root.
+// It reuses the summary of an earlier reply in the thread and only condenses
+// the messages added since, each cut to limits.messageChars. ok is false
+// when there is nothing to add or summarizing failed.
+func (b *Bot) summarizeOlderTurns(ctx context.Context, s *discordgo.Session, fetcher messageHistoryFetcher, msg *discordgo.Message, older *discordgo.MessageReference, limits historyLimits) (llm.HistoryMessage, bool) {
+	if b.threadSummaries == nil || b.summarizer == nil || older == nil {
+		return llm.HistoryMessage{}, false
+	}
+
+	walk := &replyChainWalk{fetcher: fetcher, channel: msg.ChannelID, ref: older, seen: map[string]bool{msg.ID: true}}
+	var previous threadsummaries.Summary
+	var hasPrevious bool
+	var pending []*discordgo.Message
+	for len(pending) < maxSummaryHops {
+		ref := walk.pending()
+		if ref == nil {
+			break
+		}
+		cached, ok, err := b.threadSummaries.GetByThrough(ctx, ref.MessageID)
+		if err != nil {
+			log.Printf("load thread summary failed: channel_id=%s message_id=%s error=%v", msg.ChannelID, ref.MessageID, err)
+			return llm.HistoryMessage{}, false
+		}
+		if ok {
+			previous, hasPrevious = cached, true
+			break
+		}
+		parent := walk.next()
+		if parent == nil {
+			break
+		}
+		pending = append(pending, parent)
+	}
+	if len(pending) == 0 {
+		if !hasPrevious {
+			return llm.HistoryMessage{}, false
+		}
+		return llm.HistoryMessage{Content: previous.Summary, IsSummary: true}, true
+	}
+
+	// Without an earlier summary the oldest message read stands in for
+	// the root; it is the real root unless maxSummaryHops cut the walk.
+	rootID := pending[len(pending)-1].ID
+	if hasPrevious {
+		rootID = previous.RootID
+	}
+	reverseMessages(pending)
+	turns := historyMessagesFromDiscord(s, msg.GuildID, pending, false, limits.messageChars)
+	summary, err := b.summarizer.SummarizeHistory(ctx, previous.Summary, turns)
+	if err != nil || summary == "" {
+		log.Printf("summarize thread failed: channel_id=%s message_id=%s turns=%d error=%v", msg.ChannelID, msg.ID, len(turns), err)
+		return llm.HistoryMessage{}, false
+	}
+	if err := b.threadSummaries.Save(ctx, threadsummaries.Summary{
+		RootID:           rootID,
+		ChannelID:        msg.ChannelID,
+		ThroughMessageID: older.MessageID,
+		Summary:          summary,
+	}); err != nil {
+		log.Printf("save thread summary failed: channel_id=%s root_id=%s error=%v", msg.ChannelID, rootID, err)
+	}
+	return llm.HistoryMessage{Content: summary, IsSummary: true}, true
+}
@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mizubot-go/internal/llm"
+	"mizubot-go/internal/threadsummaries"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type memorySummaryStore struct {
+	byRoot map[string]threadsummaries.Summary
+}
+
+func (m *memorySummaryStore) GetByThrough(_ context.Context, messageID string) (threadsummaries.Summary, bool, error) {
+	for _, summary := range m.byRoot {
+		if summary.ThroughMessageID == messageID {
+			return summary, true, nil
+		}
+	}
+	return threadsummaries.Summary{}, false, nil
+}
+
+func (m *memorySummaryStore) Save(_ context.Context, summary threadsummaries.Summary) error {
+	m.byRoot[summary.RootID] = summary
+	return nil
+}
+
+type recordingSummarizer struct {
+	calls [][]string
+}
+
+func (r *recordingSummarizer) SummarizeHistory(_ context.Context, previous string, turns []llm.HistoryMessage) (string, error) {
+	contents := make([]string, 0, len(turns))
+	for _, turn := range turns {
+		contents = append(contents, turn.Content)
+	}
+	r.calls = append(r.calls, contents)
+	return strings.TrimSpace(previous + " " + strings.Join(contents, " ")), nil
+}
+
+// replyChain builds n messages where each replies to the one before, plus
+// a current message replying to the last.
+func replyChain(n int) (map[string]*discordgo.Message, *discordgo.Message) {
+	byID := make(map[string]*discordgo.Message, n)
+	for i := 1; i <= n; i++ {
+		msg := &discordgo.Message{ID: idFor(i), ChannelID: "chan1", GuildID: "guild1", Content: "m" + idFor(i), Author: &discordgo.User{ID: "user1"}}
+		if i > 1 {
+			msg.MessageReference = &discordgo.MessageReference{ChannelID: "chan1", MessageID: idFor(i - 1)}
+		}
+		byID[msg.ID] = msg
+	}
+	current := &discordgo.Message{
+		ID: "current", ChannelID: "chan1", GuildID: "guild1", Content: "current",
+		Author:           &discordgo.User{ID: "user2"},
+		MessageReference: &discordgo.MessageReference{ChannelID: "chan1", MessageID: idFor(n)},
+	}
+	return byID, current
+}
+
+func TestSummarizeOlderTurnsCondensesMessagesBeyondTheWindow(t *testing.T) {
+	s := newTestSession("bot1")
+	store := &memorySummaryStore{byRoot: map[string]threadsummaries.Summary{}}
+	summarizer := &recordingSummarizer{}
+	b := &Bot{threadSummaries: store, summarizer: summarizer}
+
+	byID, current := replyChain(defaultHistoryLimits.replyChainHops + 3)
+	fetcher := &stubHistoryFetcher{messagesByID: byID}
+	history, older := resolveConversationHistory(s, fetcher, current, defaultHistoryLimits)
+	if len(history) != defaultHistoryLimits.replyChainHops || older == nil || older.MessageID != idFor(3) {
+		t.Fatalf("history = %d messages, older = %+v", len(history), older)
+	}
+
+	summary, ok := b.summarizeOlderTurns(context.Background(), s, fetcher, current, older, defaultHistoryLimits)
+	if !ok || !summary.IsSummary || summary.Content != "m"+idFor(1)+" m"+idFor(2)+" m"+idFor(3) {
+		t.Fatalf("summary = %+v, %v", summary, ok)
+	}
+	saved := store.byRoot[idFor(1)]
+	if saved.ThroughMessageID != idFor(3) {
+		t.Fatalf("saved = %+v, want root %s through %s", store.byRoot, idFor(1), idFor(3))
+	}
+
+	// Two more replies: only the two messages that left the window are
+	// condensed, on top of the cached summary.
+	byID, current = replyChain(defaultHistoryLimits.replyChainHops + 5)
+	fetcher = &stubHistoryFetcher{messagesByID: byID}
+	_, older = resolveConversationHistory(s, fetcher, current, defaultHistoryLimits)
+	summary, ok = b.summarizeOlderTurns(context.Background(), s, fetcher, current, older, defaultHistoryLimits)
+	if !ok {
+		t.Fatal("second summary failed")
+	}
+	if last := summarizer.calls[len(summarizer.calls)-1]; len(last) != 2 || last[0] != "m"+idFor(4) {
+		t.Fatalf("second call condensed %q, want the two newer messages", last)
+	}
+	if saved := store.byRoot[idFor(1)]; saved.ThroughMessageID != idFor(5) || saved.Summary != summary.Content {
+		t.Fatalf("saved = %+v", saved)
+	}
+}
+
+func TestSummarizeOlderTurnsSkipsShortThreads(t *testing.T) {
+	s := newTestSession("bot1")
+	summarizer := &recordingSummarizer{}
+	b := &Bot{threadSummaries: &memorySummaryStore{byRoot: map[string]threadsummaries.Summary{}}, summarizer: summarizer}
+
+	byID, current := replyChain(defaultHistoryLimits.replyChainHops)
+	fetcher := &stubHistoryFetcher{messagesByID: byID}
+	_, older := resolveConversationHistory(s, fetcher, current, defaultHistoryLimits)
+	if _, ok := b.summarizeOlderTurns(context.Background(), s, fetcher, current, older, defaultHistoryLimits); ok || len(summarizer.calls) != 0 {
+		t.Fatalf("a thread that fits the window was summarized: older=%+v calls=%d", older, len(summarizer.calls))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -28,8 +30,23 @@ type Config struct {
 	OllamaBaseURL          string
 	OllamaModel            string
 	OllamaTimeout          time.Duration
+	OllamaEmbedModel       string  // empty keeps keyword-only tool selection
+	LLMToolSimilarity      float64 // message/tool similarity that offers a tool
+	LLMProvider            string  // "ollama" or "openai"
+	OpenAIBaseURL          string
+	OpenAIAPIKey           string
+	OpenAIModel            string
+	OpenAITimeout          time.Duration
+	LLMFallbackModels      []string            // tried in order when the primary model errors or times out
+	LLMFallbackAfter       time.Duration       // how long a model gets before the next one is tried
+	LLMModelRoutes         map[string][]string // guild or channel ID -> model chain, primary first
+	LLMContextWindow       int                 // tokens, for models not in LLMContextWindows
+	LLMContextWindows      map[string]int      // model -> context window in tokens
+	LLMCompletionReserve   int                 // tokens of the window kept free for the reply
 	GuildInstructions      map[string]string
 	LLMDebugHistory        bool
+	HTTPAddr               string // empty disables the health/metrics listener
+	LeaderElection         bool   // share the database with a standby instance
 }
 
 type fileConfig struct {
@@ -42,8 +59,12 @@ type fileConfig struct {
 	TestGuildID       string            `yaml:"test_guild_id"`
 	DryRun            bool              `yaml:"dry_run"`
 	Ollama            ollamaFileConfig  `yaml:"ollama"`
+	LLM               llmFileConfig     `yaml:"llm"`
+	OpenAI            openAIFileConfig  `yaml:"openai"`
 	GuildInstructions map[string]string `yaml:"guild_instructions"`
 	LLMDebugHistory   bool              `yaml:"llm_debug_history"`
+	HTTPAddr          string            `yaml:"http_addr"`
+	LeaderElection    bool              `yaml:"leader_election"`
 }
 
 type animeFileConfig struct {
@@ -61,7 +82,26 @@ type awsFileConfig struct {
 }
 
 type ollamaFileConfig struct {
+	BaseURL    string `yaml:"base_url"`
+	Model      string `yaml:"model"`
+	Timeout    string `yaml:"timeout"`
+	EmbedModel string `yaml:"embed_model"`
+}
+
+type llmFileConfig struct {
+	Provider          string              `yaml:"provider"`
+	FallbackModels    []string            `yaml:"fallback_models"`
+	FallbackAfter     string              `yaml:"fallback_after"`
+	ModelRoutes       map[string][]string `yaml:"model_routes"`
+	ToolSimilarity    float64             `yaml:"tool_similarity"`
+	ContextWindow     int                 `yaml:"context_window"`
+	ContextWindows    map[string]int      `yaml:"context_windows"`
+	CompletionReserve int                 `yaml:"completion_reserve"`
+}
+
+type openAIFileConfig struct {
 	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
 	Model   string `yaml:"model"`
 	Timeout string `yaml:"timeout"`
 }
@@ -110,7 +150,20 @@ type envVals struct {
 	OllamaBaseURL          string
 	OllamaModel            string
 	OllamaTimeout          string
+	OllamaEmbedModel       string
+	LLMToolSimilarity      string
+	LLMProvider            string
+	OpenAIBaseURL          string
+	OpenAIAPIKey           string
+	OpenAIModel            string
+	OpenAITimeout          string
+	LLMFallbackModels      string
+	LLMFallbackAfter       string
+	LLMContextWindow       string
+	LLMCompletionReserve   string
 	LLMDebugHistory        string
+	HTTPAddr               string
+	LeaderElection         string
 }
 
 func osEnv() envVals {
@@ -133,7 +186,20 @@ func osEnv() envVals {
 		OllamaBaseURL:          os.Getenv("OLLAMA_BASE_URL"),
 		OllamaModel:            os.Getenv("OLLAMA_MODEL"),
 		OllamaTimeout:          os.Getenv("OLLAMA_TIMEOUT"),
+		OllamaEmbedModel:       os.Getenv("OLLAMA_EMBED_MODEL"),
+		LLMToolSimilarity:      os.Getenv("LLM_TOOL_SIMILARITY"),
+		LLMProvider:            os.Getenv("LLM_PROVIDER"),
+		OpenAIBaseURL:          os.Getenv("OPENAI_BASE_URL"),
+		OpenAIAPIKey:           os.Getenv("OPENAI_API_KEY"),
+		OpenAIModel:            os.Getenv("OPENAI_MODEL"),
+		OpenAITimeout:          os.Getenv("OPENAI_TIMEOUT"),
+		LLMFallbackModels:      os.Getenv("LLM_FALLBACK_MODELS"),
+		LLMFallbackAfter:       os.Getenv("LLM_FALLBACK_AFTER"),
+		LLMContextWindow:       os.Getenv("LLM_CONTEXT_WINDOW"),
+		LLMCompletionReserve:   os.Getenv("LLM_COMPLETION_RESERVE"),
 		LLMDebugHistory:        os.Getenv("LLM_DEBUG_HISTORY"),
+		HTTPAddr:               os.Getenv("HTTP_ADDR"),
+		LeaderElection:         os.Getenv("LEADER_ELECTION"),
 	}
 }
 
@@ -175,12 +241,53 @@ func fromValues(f fileConfig, e envVals) (Config, error) {
 		llmDebugHistory = true
 	}
 
+	leaderElection := f.LeaderElection
+	if e.LeaderElection == "1" || e.LeaderElection == "true" || e.LeaderElection == "TRUE" {
+		leaderElection = true
+	}
+
 	ollamaTimeoutStr := fallback(e.OllamaTimeout, f.Ollama.Timeout, "60s")
 	ollamaTimeout := time.Minute
 	if d, err := time.ParseDuration(ollamaTimeoutStr); err == nil {
 		ollamaTimeout = d
 	}
 
+	llmProvider := fallback(e.LLMProvider, f.LLM.Provider, "ollama")
+	openAIModel := fallback(e.OpenAIModel, f.OpenAI.Model, "")
+	switch llmProvider {
+	case "ollama":
+	case "openai":
+		if openAIModel == "" {
+			return Config{}, errors.New("missing model: set openai.model in YAML or OPENAI_MODEL when llm.provider is openai")
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown llm.provider %q: use ollama or openai", llmProvider)
+	}
+
+	openAITimeoutStr := fallback(e.OpenAITimeout, f.OpenAI.Timeout, "60s")
+	openAITimeout := time.Minute
+	if d, err := time.ParseDuration(openAITimeoutStr); err == nil {
+		openAITimeout = d
+	}
+
+	fallbackModels := f.LLM.FallbackModels
+	if e.LLMFallbackModels != "" {
+		fallbackModels = splitList(e.LLMFallbackModels)
+	}
+	fallbackAfterStr := fallback(e.LLMFallbackAfter, f.LLM.FallbackAfter, "25s")
+	fallbackAfter := 25 * time.Second
+	if d, err := time.ParseDuration(fallbackAfterStr); err == nil && d > 0 {
+		fallbackAfter = d
+	}
+
+	toolSimilarity := f.LLM.ToolSimilarity
+	if v, err := strconv.ParseFloat(e.LLMToolSimilarity, 64); err == nil {
+		toolSimilarity = v
+	}
+
+	contextWindow := positiveInt(e.LLMContextWindow, f.LLM.ContextWindow, 8192)
+	completionReserve := positiveInt(e.LLMCompletionReserve, f.LLM.CompletionReserve, 1024)
+
 	testGuild := fallback(e.TestGuildID, f.TestGuildID, "")
 
 	return Config{
@@ -201,11 +308,38 @@ func fromValues(f fileConfig, e envVals) (Config, error) {
 		OllamaBaseURL:          fallback(e.OllamaBaseURL, f.Ollama.BaseURL, "http://localhost:11434"),
 		OllamaModel:            fallback(e.OllamaModel, f.Ollama.Model, "llama3.2"),
 		OllamaTimeout:          ollamaTimeout,
+		OllamaEmbedModel:       fallback(e.OllamaEmbedModel, f.Ollama.EmbedModel, ""),
+		LLMToolSimilarity:      toolSimilarity,
+		LLMProvider:            llmProvider,
+		OpenAIBaseURL:          fallback(e.OpenAIBaseURL, f.OpenAI.BaseURL, "https://api.openai.com/v1"),
+		OpenAIAPIKey:           fallback(e.OpenAIAPIKey, f.OpenAI.APIKey, ""),
+		OpenAIModel:            openAIModel,
+		OpenAITimeout:          openAITimeout,
+		LLMFallbackModels:      fallbackModels,
+		LLMFallbackAfter:       fallbackAfter,
+		LLMModelRoutes:         f.LLM.ModelRoutes,
+		LLMContextWindow:       contextWindow,
+		LLMContextWindows:      f.LLM.ContextWindows,
+		LLMCompletionReserve:   completionReserve,
 		GuildInstructions:      f.GuildInstructions,
 		LLMDebugHistory:        llmDebugHistory,
+		HTTPAddr:               fallback(e.HTTPAddr, f.HTTPAddr, ""),
+		LeaderElection:         leaderElection,
 	}, nil
 }
 
+// positiveInt returns the env value if it parses as a positive integer,
+// else the file value if positive, else def.
+func positiveInt(env string, file, def int) int {
+	if v, err := strconv.Atoi(env); err == nil && v > 0 {
+		return v
+	}
+	if file > 0 {
+		return file
+	}
+	return def
+}
+
 func fallback(values ...string) string {
 	for _, v := range values {
 		if v != "" {
@@ -214,3 +348,13 @@ func fallback(values ...string) string {
 	}
 	return ""
 }
+
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -89,6 +90,12 @@ func TestLoadFromFileAndEnvOverride(t *testing.T) {
 	if cfg.LLMDebugHistory {
 		t.Fatalf("llm_debug_history should default to false")
 	}
+	if cfg.HTTPAddr != "" {
+		t.Fatalf("http_addr should default to empty, got %q", cfg.HTTPAddr)
+	}
+	if cfg.LeaderElection {
+		t.Fatalf("leader_election should default to false")
+	}
 }
 
 func TestLLMDebugHistoryFromFileAndEnv(t *testing.T) {
@@ -115,3 +122,113 @@ func TestLLMDebugHistoryFromFileAndEnv(t *testing.T) {
 		t.Fatalf("LLM_DEBUG_HISTORY=1 env override should enable debug history")
 	}
 }
+
+func TestLLMProviderSelection(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	if err := os.WriteFile(p, []byte(sampleYAML+"llm:\n  provider: openai\nopenai:\n  base_url: http://llama.local/v1\n  model: qwen\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(p)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.LLMProvider != "openai" || cfg.OpenAIBaseURL != "http://llama.local/v1" || cfg.OpenAIModel != "qwen" || cfg.OpenAITimeout.String() != "1m0s" {
+		t.Fatalf("openai config = %q %q %q %s", cfg.LLMProvider, cfg.OpenAIBaseURL, cfg.OpenAIModel, cfg.OpenAITimeout)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+	t.Setenv("LLM_PROVIDER", "ollama")
+	cfg, err = LoadFromFile(p)
+	if err != nil || cfg.LLMProvider != "ollama" || cfg.OpenAIAPIKey != "sk-env" {
+		t.Fatalf("env override = %q key=%q err=%v", cfg.LLMProvider, cfg.OpenAIAPIKey, err)
+	}
+
+	t.Setenv("LLM_PROVIDER", "bard")
+	if _, err := LoadFromFile(p); err == nil {
+		t.Fatalf("expected an error for an unknown provider")
+	}
+	t.Setenv("LLM_PROVIDER", "openai")
+	bare := filepath.Join(dir, "bare.yaml")
+	if err := os.WriteFile(bare, []byte(sampleYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(bare); err == nil {
+		t.Fatalf("expected an error for openai without a model")
+	}
+}
+
+func TestLLMModelRoutingFromFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	yaml := sampleYAML + "llm:\n  fallback_models: [\"llama3.2:1b\"]\n  fallback_after: 15s\n  model_routes:\n    \"123\": [\"llama3.1:70b\"]\n"
+	if err := os.WriteFile(p, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(p)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if len(cfg.LLMFallbackModels) != 1 || cfg.LLMFallbackModels[0] != "llama3.2:1b" || cfg.LLMFallbackAfter.String() != "15s" {
+		t.Fatalf("fallback = %v after %s", cfg.LLMFallbackModels, cfg.LLMFallbackAfter)
+	}
+	if models := cfg.LLMModelRoutes["123"]; len(models) != 1 || models[0] != "llama3.1:70b" {
+		t.Fatalf("routes = %v", cfg.LLMModelRoutes)
+	}
+
+	t.Setenv("LLM_FALLBACK_MODELS", "qwen2.5:3b, llama3.2:1b")
+	cfg, err = LoadFromFile(p)
+	if err != nil || len(cfg.LLMFallbackModels) != 2 || cfg.LLMFallbackModels[0] != "qwen2.5:3b" {
+		t.Fatalf("env override = %v err=%v", cfg.LLMFallbackModels, err)
+	}
+}
+
+func TestEmbeddingToolSelectionFromFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	yaml := strings.Replace(sampleYAML, "ollama:\n", "ollama:\n  embed_model: nomic-embed-text\n", 1) + "llm:\n  tool_similarity: 0.6\n"
+	if err := os.WriteFile(p, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(p)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.OllamaEmbedModel != "nomic-embed-text" || cfg.LLMToolSimilarity != 0.6 {
+		t.Fatalf("embedding config = %q %v", cfg.OllamaEmbedModel, cfg.LLMToolSimilarity)
+	}
+
+	t.Setenv("LLM_TOOL_SIMILARITY", "0.45")
+	cfg, err = LoadFromFile(p)
+	if err != nil || cfg.LLMToolSimilarity != 0.45 {
+		t.Fatalf("env override = %v err=%v", cfg.LLMToolSimilarity, err)
+	}
+}
+
+func TestLLMPromptBudgetFromFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	if err := os.WriteFile(p, []byte(sampleYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(p)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.LLMContextWindow != 8192 || cfg.LLMCompletionReserve != 1024 {
+		t.Fatalf("defaults = %d %d", cfg.LLMContextWindow, cfg.LLMCompletionReserve)
+	}
+
+	yaml := sampleYAML + "llm:\n  context_window: 4096\n  completion_reserve: 512\n  context_windows:\n    \"llama3.1:70b\": 131072\n"
+	if err := os.WriteFile(p, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LLM_CONTEXT_WINDOW", "32768")
+	cfg, err = LoadFromFile(p)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cfg.LLMContextWindow != 32768 || cfg.LLMCompletionReserve != 512 || cfg.LLMContextWindows["llama3.1:70b"] != 131072 {
+		t.Fatalf("budget = %d %d %v", cfg.LLMContextWindow, cfg.LLMCompletionReserve, cfg.LLMContextWindows)
+	}
+}
@@ -23,10 +23,11 @@ INSERT INTO llm_message_logs(
     latency_ms,
     status,
     error,
+    model,
     created_at
 )
-VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at, model
 `
 
 type CreateLLMMessageLogParams struct {
@@ -42,6 +43,7 @@ type CreateLLMMessageLogParams struct {
 	LatencyMs        int64   `json:"latency_ms"`
 	Status           string  `json:"status"`
 	Error            string  `json:"error"`
+	Model            string  `json:"model"`
 	CreatedAt        int64   `json:"created_at"`
 }
 
@@ -59,6 +61,7 @@ func (q *Queries) CreateLLMMessageLog(ctx context.Context, db DBTX, arg CreateLL
 		arg.LatencyMs,
 		arg.Status,
 		arg.Error,
+		arg.Model,
 		arg.CreatedAt,
 	)
 	var i LlmMessageLog
@@ -77,12 +80,13 @@ func (q *Queries) CreateLLMMessageLog(ctx context.Context, db DBTX, arg CreateLL
 		&i.Status,
 		&i.Error,
 		&i.CreatedAt,
+		&i.Model,
 	)
 	return i, err
 }
 
 const listLLMMessageLogsByGuild = `-- name: ListLLMMessageLogsByGuild :many
-SELECT id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at
+SELECT id, guild_id, channel_id, user_id, message_id, prompt_tokens, completion_tokens, total_tokens, llm_turns, tool_calls, latency_ms, status, error, created_at, model
 FROM llm_message_logs
 WHERE guild_id = ?
 ORDER BY created_at DESC
@@ -113,6 +117,7 @@ func (q *Queries) ListLLMMessageLogsByGuild(ctx context.Context, db DBTX, guildI
 			&i.Status,
 			&i.Error,
 			&i.CreatedAt,
+			&i.Model,
 		); err != nil {
 			return nil, err
 		}
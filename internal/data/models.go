@@ -11,6 +11,21 @@ type GuildInstruction struct {
 	UpdatedAt    int64  `json:"updated_at"`
 }
 
+type LeaderLease struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	ExpiresAt int64  `json:"expires_at"`
+	RenewedAt int64  `json:"renewed_at"`
+}
+
+type LlmMemory struct {
+	ID        int64  `json:"id"`
+	UserID    string `json:"user_id"`
+	GuildID   string `json:"guild_id"`
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type LlmMessageLog struct {
 	ID               int64   `json:"id"`
 	GuildID          *string `json:"guild_id"`
@@ -26,6 +41,23 @@ type LlmMessageLog struct {
 	Status           string  `json:"status"`
 	Error            string  `json:"error"`
 	CreatedAt        int64   `json:"created_at"`
+	Model            string  `json:"model"`
+}
+
+type LlmModelRoute struct {
+	ScopeID   string `json:"scope_id"`
+	Models    string `json:"models"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+type LlmThreadSummary struct {
+	RootID           string `json:"root_id"`
+	ChannelID        string `json:"channel_id"`
+	ThroughMessageID string `json:"through_message_id"`
+	Summary          string `json:"summary"`
+	CreatedAt        int64  `json:"created_at"`
+	UpdatedAt        int64  `json:"updated_at"`
 }
 
 type PageMonitor struct {
@@ -43,6 +75,8 @@ type PageMonitor struct {
 	ContentHash   string  `json:"content_hash"`
 	LastContent   string  `json:"last_content"`
 	Selector      string  `json:"selector"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
 }
 
 type ProcessedRssEntry struct {
@@ -54,19 +88,60 @@ type ProcessedRssEntry struct {
 }
 
 type Reminder struct {
-	ID        int64   `json:"id"`
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	CompletedAt   *int64  `json:"completed_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
+}
+
+type ReminderDelivery struct {
+	ID             int64  `json:"id"`
+	ReminderID     int64  `json:"reminder_id"`
+	ScheduledFor   int64  `json:"scheduled_for"`
+	Status         string `json:"status"`
+	Attempts       int64  `json:"attempts"`
+	Error          string `json:"error"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+	MissedRuns     int64  `json:"missed_runs"`
+	DeliveredVia   string `json:"delivered_via"`
+	FallbackReason string `json:"fallback_reason"`
+}
+
+type ReminderFeedToken struct {
+	UserID    string `json:"user_id"`
+	Token     string `json:"token"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ReminderSubscriber struct {
+	ReminderID int64  `json:"reminder_id"`
+	UserID     string `json:"user_id"`
+	CreatedAt  int64  `json:"created_at"`
 }
 
 type UserAnimeEntry struct {
@@ -102,8 +177,9 @@ type UserAnimeSetting struct {
 }
 
 type UserSetting struct {
-	UserID    string `json:"user_id"`
-	Timezone  string `json:"timezone"`
-	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
+	UserID       string `json:"user_id"`
+	Timezone     string `json:"timezone"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	DeliveryMode string `json:"delivery_mode"`
 }
@@ -12,7 +12,7 @@ import (
 const createPageMonitor = `-- name: CreatePageMonitor :one
 INSERT INTO page_monitors(user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at)
 VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at
+RETURNING id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until
 `
 
 type CreatePageMonitorParams struct {
@@ -46,6 +46,8 @@ type CreatePageMonitorRow struct {
 	NextCheck     int64   `json:"next_check"`
 	CreatedAt     int64   `json:"created_at"`
 	UpdatedAt     int64   `json:"updated_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
 }
 
 func (q *Queries) CreatePageMonitor(ctx context.Context, db DBTX, arg CreatePageMonitorParams) (CreatePageMonitorRow, error) {
@@ -80,6 +82,8 @@ func (q *Queries) CreatePageMonitor(ctx context.Context, db DBTX, arg CreatePage
 		&i.NextCheck,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Paused,
+		&i.PausedUntil,
 	)
 	return i, err
 }
@@ -97,9 +101,9 @@ func (q *Queries) DeletePageMonitor(ctx context.Context, db DBTX, iD int64, user
 }
 
 const listDuePageMonitors = `-- name: ListDuePageMonitors :many
-SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until
 FROM page_monitors
-WHERE next_check <= ?
+WHERE next_check <= ? AND paused = 0
 ORDER BY next_check ASC
 LIMIT ?
 `
@@ -119,6 +123,8 @@ type ListDuePageMonitorsRow struct {
 	NextCheck     int64   `json:"next_check"`
 	CreatedAt     int64   `json:"created_at"`
 	UpdatedAt     int64   `json:"updated_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
 }
 
 func (q *Queries) ListDuePageMonitors(ctx context.Context, db DBTX, nextCheck int64, limit int64) ([]ListDuePageMonitorsRow, error) {
@@ -145,6 +151,8 @@ func (q *Queries) ListDuePageMonitors(ctx context.Context, db DBTX, nextCheck in
 			&i.NextCheck,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Paused,
+			&i.PausedUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -160,7 +168,7 @@ func (q *Queries) ListDuePageMonitors(ctx context.Context, db DBTX, nextCheck in
 }
 
 const listPageMonitorsByUser = `-- name: ListPageMonitorsByUser :many
-SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, url, label, selector, last_status, content_hash, last_content, check_interval, next_check, created_at, updated_at, paused, paused_until
 FROM page_monitors
 WHERE user_id = ?
 ORDER BY created_at ASC
@@ -181,6 +189,8 @@ type ListPageMonitorsByUserRow struct {
 	NextCheck     int64   `json:"next_check"`
 	CreatedAt     int64   `json:"created_at"`
 	UpdatedAt     int64   `json:"updated_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
 }
 
 func (q *Queries) ListPageMonitorsByUser(ctx context.Context, db DBTX, userID string) ([]ListPageMonitorsByUserRow, error) {
@@ -207,6 +217,8 @@ func (q *Queries) ListPageMonitorsByUser(ctx context.Context, db DBTX, userID st
 			&i.NextCheck,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Paused,
+			&i.PausedUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -221,6 +233,67 @@ func (q *Queries) ListPageMonitorsByUser(ctx context.Context, db DBTX, userID st
 	return items, nil
 }
 
+const pausePageMonitor = `-- name: PausePageMonitor :execrows
+UPDATE page_monitors SET paused = 1, paused_until = ?, updated_at = ? WHERE id = ? AND user_id = ?
+`
+
+type PausePageMonitorParams struct {
+	PausedUntil *int64 `json:"paused_until"`
+	UpdatedAt   int64  `json:"updated_at"`
+	ID          int64  `json:"id"`
+	UserID      string `json:"user_id"`
+}
+
+func (q *Queries) PausePageMonitor(ctx context.Context, db DBTX, arg PausePageMonitorParams) (int64, error) {
+	result, err := db.ExecContext(ctx, pausePageMonitor,
+		arg.PausedUntil,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resumeExpiredPageMonitors = `-- name: ResumeExpiredPageMonitors :execrows
+UPDATE page_monitors SET paused = 0, paused_until = NULL, updated_at = ?
+WHERE paused = 1 AND paused_until IS NOT NULL AND paused_until <= ?
+`
+
+func (q *Queries) ResumeExpiredPageMonitors(ctx context.Context, db DBTX, updatedAt int64, pausedUntil *int64) (int64, error) {
+	result, err := db.ExecContext(ctx, resumeExpiredPageMonitors, updatedAt, pausedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resumePageMonitor = `-- name: ResumePageMonitor :execrows
+UPDATE page_monitors SET paused = 0, paused_until = NULL, next_check = ?, updated_at = ? WHERE id = ? AND user_id = ? AND paused = 1
+`
+
+type ResumePageMonitorParams struct {
+	NextCheck int64  `json:"next_check"`
+	UpdatedAt int64  `json:"updated_at"`
+	ID        int64  `json:"id"`
+	UserID    string `json:"user_id"`
+}
+
+func (q *Queries) ResumePageMonitor(ctx context.Context, db DBTX, arg ResumePageMonitorParams) (int64, error) {
+	result, err := db.ExecContext(ctx, resumePageMonitor,
+		arg.NextCheck,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updatePageMonitorContent = `-- name: UpdatePageMonitorContent :exec
 UPDATE page_monitors SET last_status = ?, content_hash = ?, last_content = ?, next_check = ?, updated_at = ? WHERE id = ?
 `
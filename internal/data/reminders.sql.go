@@ -9,41 +9,115 @@ import (
 	"context"
 )
 
+const catchUpReminder = `-- name: CatchUpReminder :execrows
+UPDATE reminders SET next_run = ?, missed_runs = ?, updated_at = ?
+WHERE id = ? AND next_run = ? AND completed_at IS NULL
+`
+
+type CatchUpReminderParams struct {
+	NextRun    int64 `json:"next_run"`
+	MissedRuns int64 `json:"missed_runs"`
+	UpdatedAt  int64 `json:"updated_at"`
+	ID         int64 `json:"id"`
+	NextRun_2  int64 `json:"next_run_2"`
+}
+
+func (q *Queries) CatchUpReminder(ctx context.Context, db DBTX, arg CatchUpReminderParams) (int64, error) {
+	result, err := db.ExecContext(ctx, catchUpReminder,
+		arg.NextRun,
+		arg.MissedRuns,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.NextRun_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeReminder = `-- name: CompleteReminder :exec
+UPDATE reminders SET completed_at = ?, updated_at = ? WHERE id = ?
+`
+
+type CompleteReminderParams struct {
+	CompletedAt *int64 `json:"completed_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) CompleteReminder(ctx context.Context, db DBTX, arg CompleteReminderParams) error {
+	_, err := db.ExecContext(ctx, completeReminder, arg.CompletedAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const countReminderRun = `-- name: CountReminderRun :exec
+UPDATE reminders
+SET run_count = run_count + 1, remaining_runs = CASE WHEN remaining_runs IS NULL THEN NULL ELSE remaining_runs - 1 END
+WHERE id = ?
+`
+
+func (q *Queries) CountReminderRun(ctx context.Context, db DBTX, id int64) error {
+	_, err := db.ExecContext(ctx, countReminderRun, id)
+	return err
+}
+
 const createReminder = `-- name: CreateReminder :one
-INSERT INTO reminders(user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at)
-VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+INSERT INTO reminders(user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, catch_up, next_run, created_at, updated_at, ends_at, remaining_runs, mentions, delivery_mode, shared)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 `
 
 type CreateReminderParams struct {
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	CatchUp       string  `json:"catch_up"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	Shared        int64   `json:"shared"`
 }
 
 type CreateReminderRow struct {
-	ID        int64   `json:"id"`
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
 }
 
 func (q *Queries) CreateReminder(ctx context.Context, db DBTX, arg CreateReminderParams) (CreateReminderRow, error) {
@@ -57,9 +131,15 @@ func (q *Queries) CreateReminder(ctx context.Context, db DBTX, arg CreateReminde
 		arg.CronExpr,
 		arg.Once,
 		arg.Timezone,
+		arg.CatchUp,
 		arg.NextRun,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.EndsAt,
+		arg.RemainingRuns,
+		arg.Mentions,
+		arg.DeliveryMode,
+		arg.Shared,
 	)
 	var i CreateReminderRow
 	err := row.Scan(
@@ -76,6 +156,21 @@ func (q *Queries) CreateReminder(ctx context.Context, db DBTX, arg CreateReminde
 		&i.NextRun,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FailureCount,
+		&i.RetryAt,
+		&i.LastError,
+		&i.DeadAt,
+		&i.CatchUp,
+		&i.MissedRuns,
+		&i.CompletedAt,
+		&i.Paused,
+		&i.PausedUntil,
+		&i.EndsAt,
+		&i.RemainingRuns,
+		&i.Mentions,
+		&i.DeliveryMode,
+		&i.RunCount,
+		&i.Shared,
 	)
 	return i, err
 }
@@ -89,6 +184,18 @@ func (q *Queries) DeleteByID(ctx context.Context, db DBTX, id int64) error {
 	return err
 }
 
+const deleteCompletedBefore = `-- name: DeleteCompletedBefore :execrows
+DELETE FROM reminders WHERE completed_at IS NOT NULL AND completed_at < ?
+`
+
+func (q *Queries) DeleteCompletedBefore(ctx context.Context, db DBTX, completedAt *int64) (int64, error) {
+	result, err := db.ExecContext(ctx, deleteCompletedBefore, completedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteOwned = `-- name: DeleteOwned :execrows
 DELETE FROM reminders WHERE id = ? AND user_id = ?
 `
@@ -101,26 +208,52 @@ func (q *Queries) DeleteOwned(ctx context.Context, db DBTX, iD int64, userID str
 	return result.RowsAffected()
 }
 
+const getDueNextRun = `-- name: GetDueNextRun :one
+SELECT next_run FROM reminders WHERE id = ? AND completed_at IS NULL
+`
+
+func (q *Queries) GetDueNextRun(ctx context.Context, db DBTX, id int64) (int64, error) {
+	row := db.QueryRowContext(ctx, getDueNextRun, id)
+	var next_run int64
+	err := row.Scan(&next_run)
+	return next_run, err
+}
+
 const getOwned = `-- name: GetOwned :one
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
 WHERE id = ? AND user_id = ?
 `
 
 type GetOwnedRow struct {
-	ID        int64   `json:"id"`
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
 }
 
 func (q *Queries) GetOwned(ctx context.Context, db DBTX, iD int64, userID string) (GetOwnedRow, error) {
@@ -140,31 +273,134 @@ func (q *Queries) GetOwned(ctx context.Context, db DBTX, iD int64, userID string
 		&i.NextRun,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FailureCount,
+		&i.RetryAt,
+		&i.LastError,
+		&i.DeadAt,
+		&i.CatchUp,
+		&i.MissedRuns,
+		&i.CompletedAt,
+		&i.Paused,
+		&i.PausedUntil,
+		&i.EndsAt,
+		&i.RemainingRuns,
+		&i.Mentions,
+		&i.DeliveryMode,
+		&i.RunCount,
+		&i.Shared,
+	)
+	return i, err
+}
+
+const getSharedReminder = `-- name: GetSharedReminder :one
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
+FROM reminders
+WHERE id = ? AND shared = 1 AND completed_at IS NULL
+`
+
+type GetSharedReminderRow struct {
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
+}
+
+func (q *Queries) GetSharedReminder(ctx context.Context, db DBTX, id int64) (GetSharedReminderRow, error) {
+	row := db.QueryRowContext(ctx, getSharedReminder, id)
+	var i GetSharedReminderRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ChannelID,
+		&i.GuildID,
+		&i.Message,
+		&i.Schedule,
+		&i.AtTime,
+		&i.CronExpr,
+		&i.Once,
+		&i.Timezone,
+		&i.NextRun,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FailureCount,
+		&i.RetryAt,
+		&i.LastError,
+		&i.DeadAt,
+		&i.CatchUp,
+		&i.MissedRuns,
+		&i.CompletedAt,
+		&i.Paused,
+		&i.PausedUntil,
+		&i.EndsAt,
+		&i.RemainingRuns,
+		&i.Mentions,
+		&i.DeliveryMode,
+		&i.RunCount,
+		&i.Shared,
 	)
 	return i, err
 }
 
 const listByUser = `-- name: ListByUser :many
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
-WHERE user_id = ?
+WHERE user_id = ? AND completed_at IS NULL
 ORDER BY next_run ASC
 `
 
 type ListByUserRow struct {
-	ID        int64   `json:"id"`
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
 }
 
 func (q *Queries) ListByUser(ctx context.Context, db DBTX, userID string) ([]ListByUserRow, error) {
@@ -190,6 +426,21 @@ func (q *Queries) ListByUser(ctx context.Context, db DBTX, userID string) ([]Lis
 			&i.NextRun,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FailureCount,
+			&i.RetryAt,
+			&i.LastError,
+			&i.DeadAt,
+			&i.CatchUp,
+			&i.MissedRuns,
+			&i.CompletedAt,
+			&i.Paused,
+			&i.PausedUntil,
+			&i.EndsAt,
+			&i.RemainingRuns,
+			&i.Mentions,
+			&i.DeliveryMode,
+			&i.RunCount,
+			&i.Shared,
 		); err != nil {
 			return nil, err
 		}
@@ -205,31 +456,52 @@ func (q *Queries) ListByUser(ctx context.Context, db DBTX, userID string) ([]Lis
 }
 
 const listDue = `-- name: ListDue :many
-SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
 FROM reminders
-WHERE next_run <= ?
+WHERE next_run <= ? AND (retry_at IS NULL OR retry_at <= ?) AND completed_at IS NULL AND dead_at IS NULL AND paused = 0
 ORDER BY next_run ASC
 LIMIT ?
 `
 
+type ListDueParams struct {
+	NextRun int64  `json:"next_run"`
+	RetryAt *int64 `json:"retry_at"`
+	Limit   int64  `json:"limit"`
+}
+
 type ListDueRow struct {
-	ID        int64   `json:"id"`
-	UserID    string  `json:"user_id"`
-	ChannelID string  `json:"channel_id"`
-	GuildID   *string `json:"guild_id"`
-	Message   string  `json:"message"`
-	Schedule  string  `json:"schedule"`
-	AtTime    *string `json:"at_time"`
-	CronExpr  string  `json:"cron_expr"`
-	Once      int64   `json:"once"`
-	Timezone  string  `json:"timezone"`
-	NextRun   int64   `json:"next_run"`
-	CreatedAt int64   `json:"created_at"`
-	UpdatedAt int64   `json:"updated_at"`
-}
-
-func (q *Queries) ListDue(ctx context.Context, db DBTX, nextRun int64, limit int64) ([]ListDueRow, error) {
-	rows, err := db.QueryContext(ctx, listDue, nextRun, limit)
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
+}
+
+func (q *Queries) ListDue(ctx context.Context, db DBTX, arg ListDueParams) ([]ListDueRow, error) {
+	rows, err := db.QueryContext(ctx, listDue, arg.NextRun, arg.RetryAt, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -251,6 +523,112 @@ func (q *Queries) ListDue(ctx context.Context, db DBTX, nextRun int64, limit int
 			&i.NextRun,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FailureCount,
+			&i.RetryAt,
+			&i.LastError,
+			&i.DeadAt,
+			&i.CatchUp,
+			&i.MissedRuns,
+			&i.CompletedAt,
+			&i.Paused,
+			&i.PausedUntil,
+			&i.EndsAt,
+			&i.RemainingRuns,
+			&i.Mentions,
+			&i.DeliveryMode,
+			&i.RunCount,
+			&i.Shared,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPausedUntil = `-- name: ListPausedUntil :many
+SELECT id, user_id, channel_id, guild_id, message, schedule, at_time, cron_expr, once, timezone, next_run, created_at, updated_at, failure_count, retry_at, last_error, dead_at, catch_up, missed_runs, completed_at, paused, paused_until, ends_at, remaining_runs, mentions, delivery_mode, run_count, shared
+FROM reminders
+WHERE paused = 1 AND paused_until IS NOT NULL AND paused_until <= ? AND completed_at IS NULL
+ORDER BY paused_until ASC
+LIMIT ?
+`
+
+type ListPausedUntilRow struct {
+	ID            int64   `json:"id"`
+	UserID        string  `json:"user_id"`
+	ChannelID     string  `json:"channel_id"`
+	GuildID       *string `json:"guild_id"`
+	Message       string  `json:"message"`
+	Schedule      string  `json:"schedule"`
+	AtTime        *string `json:"at_time"`
+	CronExpr      string  `json:"cron_expr"`
+	Once          int64   `json:"once"`
+	Timezone      string  `json:"timezone"`
+	NextRun       int64   `json:"next_run"`
+	CreatedAt     int64   `json:"created_at"`
+	UpdatedAt     int64   `json:"updated_at"`
+	FailureCount  int64   `json:"failure_count"`
+	RetryAt       *int64  `json:"retry_at"`
+	LastError     string  `json:"last_error"`
+	DeadAt        *int64  `json:"dead_at"`
+	CatchUp       string  `json:"catch_up"`
+	MissedRuns    int64   `json:"missed_runs"`
+	CompletedAt   *int64  `json:"completed_at"`
+	Paused        int64   `json:"paused"`
+	PausedUntil   *int64  `json:"paused_until"`
+	EndsAt        *int64  `json:"ends_at"`
+	RemainingRuns *int64  `json:"remaining_runs"`
+	Mentions      string  `json:"mentions"`
+	DeliveryMode  string  `json:"delivery_mode"`
+	RunCount      int64   `json:"run_count"`
+	Shared        int64   `json:"shared"`
+}
+
+func (q *Queries) ListPausedUntil(ctx context.Context, db DBTX, pausedUntil *int64, limit int64) ([]ListPausedUntilRow, error) {
+	rows, err := db.QueryContext(ctx, listPausedUntil, pausedUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPausedUntilRow
+	for rows.Next() {
+		var i ListPausedUntilRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ChannelID,
+			&i.GuildID,
+			&i.Message,
+			&i.Schedule,
+			&i.AtTime,
+			&i.CronExpr,
+			&i.Once,
+			&i.Timezone,
+			&i.NextRun,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FailureCount,
+			&i.RetryAt,
+			&i.LastError,
+			&i.DeadAt,
+			&i.CatchUp,
+			&i.MissedRuns,
+			&i.CompletedAt,
+			&i.Paused,
+			&i.PausedUntil,
+			&i.EndsAt,
+			&i.RemainingRuns,
+			&i.Mentions,
+			&i.DeliveryMode,
+			&i.RunCount,
+			&i.Shared,
 		); err != nil {
 			return nil, err
 		}
@@ -265,8 +643,77 @@ func (q *Queries) ListDue(ctx context.Context, db DBTX, nextRun int64, limit int
 	return items, nil
 }
 
+const pauseOwned = `-- name: PauseOwned :execrows
+UPDATE reminders SET paused = 1, paused_until = ?, updated_at = ?
+WHERE id = ? AND user_id = ? AND completed_at IS NULL
+`
+
+type PauseOwnedParams struct {
+	PausedUntil *int64 `json:"paused_until"`
+	UpdatedAt   int64  `json:"updated_at"`
+	ID          int64  `json:"id"`
+	UserID      string `json:"user_id"`
+}
+
+func (q *Queries) PauseOwned(ctx context.Context, db DBTX, arg PauseOwnedParams) (int64, error) {
+	result, err := db.ExecContext(ctx, pauseOwned,
+		arg.PausedUntil,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordReminderFailure = `-- name: RecordReminderFailure :exec
+UPDATE reminders SET failure_count = ?, retry_at = ?, last_error = ?, dead_at = ?, updated_at = ? WHERE id = ?
+`
+
+type RecordReminderFailureParams struct {
+	FailureCount int64  `json:"failure_count"`
+	RetryAt      *int64 `json:"retry_at"`
+	LastError    string `json:"last_error"`
+	DeadAt       *int64 `json:"dead_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) RecordReminderFailure(ctx context.Context, db DBTX, arg RecordReminderFailureParams) error {
+	_, err := db.ExecContext(ctx, recordReminderFailure,
+		arg.FailureCount,
+		arg.RetryAt,
+		arg.LastError,
+		arg.DeadAt,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const resumeReminder = `-- name: ResumeReminder :execrows
+UPDATE reminders SET paused = 0, paused_until = NULL, next_run = ?, missed_runs = 0, updated_at = ?
+WHERE id = ? AND paused = 1
+`
+
+type ResumeReminderParams struct {
+	NextRun   int64 `json:"next_run"`
+	UpdatedAt int64 `json:"updated_at"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) ResumeReminder(ctx context.Context, db DBTX, arg ResumeReminderParams) (int64, error) {
+	result, err := db.ExecContext(ctx, resumeReminder, arg.NextRun, arg.UpdatedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const setNextRun = `-- name: SetNextRun :exec
-UPDATE reminders SET next_run = ?, updated_at = ? WHERE id = ?
+UPDATE reminders SET next_run = ?, missed_runs = 0, failure_count = 0, retry_at = NULL, last_error = '', updated_at = ? WHERE id = ?
 `
 
 type SetNextRunParams struct {
@@ -279,3 +726,70 @@ func (q *Queries) SetNextRun(ctx context.Context, db DBTX, arg SetNextRunParams)
 	_, err := db.ExecContext(ctx, setNextRun, arg.NextRun, arg.UpdatedAt, arg.ID)
 	return err
 }
+
+const snoozeOwned = `-- name: SnoozeOwned :execrows
+UPDATE reminders SET next_run = ?, completed_at = NULL, failure_count = 0, retry_at = NULL, last_error = '', dead_at = NULL, updated_at = ? WHERE id = ? AND user_id = ?
+`
+
+type SnoozeOwnedParams struct {
+	NextRun   int64  `json:"next_run"`
+	UpdatedAt int64  `json:"updated_at"`
+	ID        int64  `json:"id"`
+	UserID    string `json:"user_id"`
+}
+
+func (q *Queries) SnoozeOwned(ctx context.Context, db DBTX, arg SnoozeOwnedParams) (int64, error) {
+	result, err := db.ExecContext(ctx, snoozeOwned,
+		arg.NextRun,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateOwned = `-- name: UpdateOwned :execrows
+UPDATE reminders
+SET channel_id = ?, message = ?, schedule = ?, at_time = ?, cron_expr = ?, once = ?, timezone = ?, next_run = ?, completed_at = ?,
+    failure_count = 0, retry_at = NULL, last_error = '', dead_at = NULL, missed_runs = 0, updated_at = ?
+WHERE id = ? AND user_id = ?
+`
+
+type UpdateOwnedParams struct {
+	ChannelID   string  `json:"channel_id"`
+	Message     string  `json:"message"`
+	Schedule    string  `json:"schedule"`
+	AtTime      *string `json:"at_time"`
+	CronExpr    string  `json:"cron_expr"`
+	Once        int64   `json:"once"`
+	Timezone    string  `json:"timezone"`
+	NextRun     int64   `json:"next_run"`
+	CompletedAt *int64  `json:"completed_at"`
+	UpdatedAt   int64   `json:"updated_at"`
+	ID          int64   `json:"id"`
+	UserID      string  `json:"user_id"`
+}
+
+func (q *Queries) UpdateOwned(ctx context.Context, db DBTX, arg UpdateOwnedParams) (int64, error) {
+	result, err := db.ExecContext(ctx, updateOwned,
+		arg.ChannelID,
+		arg.Message,
+		arg.Schedule,
+		arg.AtTime,
+		arg.CronExpr,
+		arg.Once,
+		arg.Timezone,
+		arg.NextRun,
+		arg.CompletedAt,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
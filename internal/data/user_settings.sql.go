@@ -10,7 +10,7 @@ import (
 )
 
 const getUserSettings = `-- name: GetUserSettings :one
-SELECT user_id, timezone, created_at, updated_at
+SELECT user_id, timezone, created_at, updated_at, delivery_mode
 FROM user_settings
 WHERE user_id = ?
 `
@@ -23,6 +23,41 @@ func (q *Queries) GetUserSettings(ctx context.Context, db DBTX, userID string) (
 		&i.Timezone,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeliveryMode,
+	)
+	return i, err
+}
+
+const upsertUserDeliveryMode = `-- name: UpsertUserDeliveryMode :one
+INSERT INTO user_settings(user_id, timezone, delivery_mode, created_at, updated_at)
+VALUES(?, '', ?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET
+    delivery_mode = excluded.delivery_mode,
+    updated_at = excluded.updated_at
+RETURNING user_id, timezone, created_at, updated_at, delivery_mode
+`
+
+type UpsertUserDeliveryModeParams struct {
+	UserID       string `json:"user_id"`
+	DeliveryMode string `json:"delivery_mode"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+func (q *Queries) UpsertUserDeliveryMode(ctx context.Context, db DBTX, arg UpsertUserDeliveryModeParams) (UserSetting, error) {
+	row := db.QueryRowContext(ctx, upsertUserDeliveryMode,
+		arg.UserID,
+		arg.DeliveryMode,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeliveryMode,
 	)
 	return i, err
 }
@@ -33,7 +68,7 @@ VALUES(?, ?, ?, ?)
 ON CONFLICT(user_id) DO UPDATE SET
     timezone = excluded.timezone,
     updated_at = excluded.updated_at
-RETURNING user_id, timezone, created_at, updated_at
+RETURNING user_id, timezone, created_at, updated_at, delivery_mode
 `
 
 type UpsertUserTimezoneParams struct {
@@ -56,6 +91,7 @@ func (q *Queries) UpsertUserTimezone(ctx context.Context, db DBTX, arg UpsertUse
 		&i.Timezone,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeliveryMode,
 	)
 	return i, err
 }
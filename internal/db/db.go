@@ -2,10 +2,22 @@ package db
 
 import (
 	"database/sql"
+	"strings"
 
 	_ "modernc.org/sqlite"
 )
 
+// busyTimeout makes a connection wait for another instance's write lock
+// instead of failing at once with SQLITE_BUSY.
+const busyTimeout = "_pragma=busy_timeout(5000)"
+
 func Open(path string) (*sql.DB, error) {
+	if !strings.Contains(path, "busy_timeout") {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + busyTimeout
+	}
 	return sql.Open("sqlite", path)
 }
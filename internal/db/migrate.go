@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,3 +16,26 @@ func Migrate(database *sql.DB, dir string) error {
 	}
 	return nil
 }
+
+// MigrationsCurrent returns an error when the database is behind the newest
+// migration in dir.
+func MigrationsCurrent(ctx context.Context, database *sql.DB, dir string) error {
+	goose.SetDialect("sqlite3")
+	goose.SetTableName("goose_db_version")
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("collect migrations: %w", err)
+	}
+	latest, err := migrations.Last()
+	if err != nil {
+		return fmt.Errorf("collect migrations: %w", err)
+	}
+	current, err := goose.GetDBVersionContext(ctx, database)
+	if err != nil {
+		return fmt.Errorf("database version: %w", err)
+	}
+	if current < latest.Version {
+		return fmt.Errorf("database at migration %d, want %d", current, latest.Version)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Package httpserver runs the optional operations listener: liveness,
+// readiness and Prometheus metrics.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"mizubot-go/internal/metrics"
+)
+
+// checkTimeout bounds each readiness check so a stuck dependency reports
+// not-ready instead of hanging the probe.
+const checkTimeout = 2 * time.Second
+
+// Check is one readiness condition, e.g. the Discord gateway or the
+// database.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Server struct {
+	srv    *http.Server
+	checks []Check
+}
+
+func New(addr string, registry *metrics.Registry, checks ...Check) *Server {
+	s := &Server{checks: checks}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	mux.Handle("/metrics", registry.Handler())
+	s.srv = &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.srv.Handler
+}
+
+// Start binds the listener and serves in the background. Bind errors are
+// returned so a bad address fails startup.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return fmt.Errorf("http listen: %w", err)
+	}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("http server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	var lines []string
+	ready := true
+	for _, check := range s.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := check.Run(ctx)
+		cancel()
+		if err != nil {
+			ready = false
+			lines = append(lines, fmt.Sprintf("%s: %v", check.Name, err))
+			continue
+		}
+		lines = append(lines, check.Name+": ok")
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprintln(w, strings.Join(lines, "\n"))
+}
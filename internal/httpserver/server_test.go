@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mizubot-go/internal/metrics"
+)
+
+func TestHealthReadyAndMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.NewCounterVec("mizubot_test_total", "Test counter.", "result").Inc("ok")
+	gatewayUp := false
+	server := New(":0", registry,
+		Check{Name: "discord", Run: func(context.Context) error {
+			if !gatewayUp {
+				return errors.New("gateway not connected")
+			}
+			return nil
+		}},
+		Check{Name: "database", Run: func(context.Context) error { return nil }},
+	)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, body := get("/healthz"); code != http.StatusOK || strings.TrimSpace(body) != "ok" {
+		t.Fatalf("/healthz = %d %q", code, body)
+	}
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "discord: gateway not connected") || !strings.Contains(body, "database: ok") {
+		t.Fatalf("/readyz before connect = %d %q", code, body)
+	}
+	gatewayUp = true
+	if code, body := get("/readyz"); code != http.StatusOK {
+		t.Fatalf("/readyz after connect = %d %q", code, body)
+	}
+	if code, body := get("/metrics"); code != http.StatusOK || !strings.Contains(body, `mizubot_test_total{result="ok"} 1`) {
+		t.Fatalf("/metrics = %d %q", code, body)
+	}
+}
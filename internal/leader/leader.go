@@ -0,0 +1,206 @@
+// Package leader elects one bot instance to run the scheduler and pollers
+// when several instances share a database, using a renewable lease row.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"mizubot-go/internal/data"
+	"mizubot-go/internal/lifecycle"
+)
+
+// DefaultTTL is how long a lease lasts without renewal. The holder renews
+// every third of it, so a standby takes over at most DefaultTTL after the
+// leader dies.
+const DefaultTTL = 15 * time.Second
+
+type Store struct {
+	db *sql.DB
+	q  *data.Queries
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, q: data.New()}
+}
+
+// TryAcquire takes or renews the named lease for holder until now+ttl. It
+// returns false while another holder's lease is still live.
+func (s *Store) TryAcquire(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	n, err := s.q.AcquireLeaderLease(ctx, s.db, data.AcquireLeaderLeaseParams{
+		Name:      name,
+		Holder:    holder,
+		ExpiresAt: now.Add(ttl).UTC().Unix(),
+		RenewedAt: now.UTC().Unix(),
+	})
+	return n > 0, err
+}
+
+// Release gives up the lease if holder still has it, so a standby can take
+// over without waiting for it to expire.
+func (s *Store) Release(ctx context.Context, name, holder string) (bool, error) {
+	n, err := s.q.ReleaseLeaderLease(ctx, s.db, name, holder)
+	return n > 0, err
+}
+
+// Holder returns who holds the named lease and until when.
+func (s *Store) Holder(ctx context.Context, name string) (string, time.Time, bool, error) {
+	row, err := s.q.GetLeaderLease(ctx, s.db, name)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	return row.Holder, time.Unix(row.ExpiresAt, 0).UTC(), true, nil
+}
+
+// StartFunc starts the work that only the leader may run and returns how to
+// stop it. ctx is cancelled once the term has ended.
+type StartFunc func(ctx context.Context) lifecycle.Stopper
+
+// Elector campaigns for a lease and runs a term of work while it holds it.
+type Elector struct {
+	store  *Store
+	name   string
+	holder string
+	ttl    time.Duration
+	start  StartFunc
+
+	loop lifecycle.Loop
+
+	mu      sync.Mutex
+	term    *term
+	expires time.Time
+}
+
+type term struct {
+	stopper lifecycle.Stopper
+	cancel  context.CancelFunc
+}
+
+func NewElector(store *Store, name, holder string, ttl time.Duration, start StartFunc) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Elector{store: store, name: name, holder: holder, ttl: ttl, start: start}
+}
+
+// DefaultHolder identifies this process as hostname, pid and a random
+// suffix, so restarts in the same container never reuse a holder ID.
+func DefaultHolder() string {
+	host, _ := os.Hostname()
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(buf))
+}
+
+// Leading reports whether this instance currently runs the leader's work.
+func (e *Elector) Leading() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term != nil
+}
+
+// Start campaigns immediately and then every third of the TTL.
+func (e *Elector) Start(ctx context.Context) {
+	e.step(ctx, time.Now())
+	e.loop.Start(ctx, e.renewEvery(), func(ctx context.Context) {
+		e.step(ctx, time.Now())
+	})
+}
+
+// step tries to take or renew the lease and starts or ends the term
+// accordingly.
+func (e *Elector) step(ctx context.Context, now time.Time) {
+	ok, err := e.store.TryAcquire(ctx, e.name, e.holder, now, e.ttl)
+	leading := e.Leading()
+	switch {
+	case err != nil:
+		log.Printf("leader lease error: name=%s holder=%s error=%v", e.name, e.holder, err)
+		// Keep leading only while the lease we last wrote outlives the next
+		// renewal attempt; waiting for that tick would leave the term
+		// running after another instance may have taken over.
+		if leading && !now.Add(e.renewEvery()).Before(e.leaseExpiry()) {
+			log.Printf("leader lease expires before the next renewal; stepping down: name=%s holder=%s", e.name, e.holder)
+			e.demote()
+		}
+	case ok:
+		e.mu.Lock()
+		e.expires = now.Add(e.ttl)
+		e.mu.Unlock()
+		if !leading {
+			log.Printf("leader lease acquired; starting scheduler and pollers: name=%s holder=%s", e.name, e.holder)
+			e.promote(ctx)
+		}
+	case leading:
+		log.Printf("leader lease lost to another instance; stepping down: name=%s holder=%s", e.name, e.holder)
+		e.demote()
+	}
+}
+
+func (e *Elector) renewEvery() time.Duration {
+	return e.ttl / 3
+}
+
+func (e *Elector) leaseExpiry() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.expires
+}
+
+func (e *Elector) promote(ctx context.Context) {
+	termCtx, cancel := context.WithCancel(ctx)
+	stopper := e.start(termCtx)
+	e.mu.Lock()
+	e.term = &term{stopper: stopper, cancel: cancel}
+	e.mu.Unlock()
+}
+
+// demote ends the current term, giving its work only until the lease
+// expires to finish. Once a lease is lost it has already expired, so the
+// work is cut off at once.
+func (e *Elector) demote() {
+	ctx, cancel := context.WithDeadline(context.Background(), e.leaseExpiry())
+	defer cancel()
+	if err := e.endTerm(ctx); err != nil {
+		log.Printf("leader term did not drain in time: name=%s error=%v", e.name, err)
+	}
+}
+
+func (e *Elector) endTerm(ctx context.Context) error {
+	e.mu.Lock()
+	t := e.term
+	e.term = nil
+	e.mu.Unlock()
+	if t == nil {
+		return nil
+	}
+	err := t.stopper.Stop(ctx)
+	t.cancel()
+	return err
+}
+
+// Stop stops campaigning, lets the current term's work finish and then
+// releases the lease so a standby can take over right away.
+func (e *Elector) Stop(ctx context.Context) error {
+	if err := e.loop.Stop(ctx); err != nil {
+		return err
+	}
+	leading := e.Leading()
+	err := e.endTerm(ctx)
+	if leading {
+		if _, releaseErr := e.store.Release(context.WithoutCancel(ctx), e.name, e.holder); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+	}
+	return err
+}
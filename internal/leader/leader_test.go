@@ -0,0 +1,186 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mizubot-go/internal/lifecycle"
+
+	_ "modernc.org/sqlite"
+)
+
+// openSharedDB opens one SQLite file through two handles, as two bot
+// processes would.
+func openSharedDB(t *testing.T) (*sql.DB, *sql.DB) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bot.db")
+	open := func() *sql.DB {
+		db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+	first := open()
+	if _, err := first.Exec(`CREATE TABLE leader_leases (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at INTEGER NOT NULL,
+		renewed_at INTEGER NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	return first, open()
+}
+
+// instance stands in for one bot process: its term of work just counts
+// starts and stops, and active tracks how many instances are leading.
+type instance struct {
+	elector *Elector
+	starts  atomic.Int32
+	stops   atomic.Int32
+}
+
+func newInstance(db *sql.DB, holder string, active *atomic.Int32) *instance {
+	in := &instance{}
+	in.elector = NewElector(NewStore(db), "scheduler", holder, 15*time.Second, func(context.Context) lifecycle.Stopper {
+		in.starts.Add(1)
+		active.Add(1)
+		return lifecycle.StopFunc(func(context.Context) error {
+			in.stops.Add(1)
+			active.Add(-1)
+			return nil
+		})
+	})
+	return in
+}
+
+func TestStandbyTakesOverExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	dbA, dbB := openSharedDB(t)
+	var active atomic.Int32
+	a := newInstance(dbA, "a", &active)
+	b := newInstance(dbB, "b", &active)
+	now := time.Unix(1_800_000_000, 0)
+
+	a.elector.step(ctx, now)
+	b.elector.step(ctx, now)
+	if !a.elector.Leading() || b.elector.Leading() {
+		t.Fatalf("leading a=%v b=%v, want only a", a.elector.Leading(), b.elector.Leading())
+	}
+
+	// a keeps renewing, so b stays on standby past the original expiry.
+	for step := 1; step <= 6; step++ {
+		at := now.Add(time.Duration(step) * 5 * time.Second)
+		a.elector.step(ctx, at)
+		b.elector.step(ctx, at)
+		if active.Load() != 1 || b.elector.Leading() {
+			t.Fatalf("step %d: active=%d b leading=%v", step, active.Load(), b.elector.Leading())
+		}
+	}
+
+	// a hangs and stops renewing; b takes over once the lease expires.
+	lastRenew := now.Add(30 * time.Second)
+	b.elector.step(ctx, lastRenew.Add(14*time.Second))
+	if b.elector.Leading() {
+		t.Fatalf("b took over before the lease expired")
+	}
+	b.elector.step(ctx, lastRenew.Add(15*time.Second))
+	if !b.elector.Leading() {
+		t.Fatalf("b did not take over the expired lease")
+	}
+
+	// a wakes up, finds the lease gone and stops its work.
+	a.elector.step(ctx, lastRenew.Add(16*time.Second))
+	if a.elector.Leading() || a.stops.Load() != 1 || active.Load() != 1 {
+		t.Fatalf("after takeover: a leading=%v a stops=%d active=%d", a.elector.Leading(), a.stops.Load(), active.Load())
+	}
+	holder, _, ok, err := NewStore(dbA).Holder(ctx, "scheduler")
+	if err != nil || !ok || holder != "b" {
+		t.Fatalf("holder = %q ok=%v err=%v, want b", holder, ok, err)
+	}
+}
+
+func TestLeaderStepsDownBeforeUnrenewedLeaseExpires(t *testing.T) {
+	ctx := context.Background()
+	dbA, dbB := openSharedDB(t)
+	var active atomic.Int32
+	a := newInstance(dbA, "a", &active)
+	b := newInstance(dbB, "b", &active)
+	now := time.Unix(1_800_000_000, 0)
+
+	a.elector.step(ctx, now)
+	// a loses its database connection, so every renewal fails.
+	dbA.Close()
+	a.elector.step(ctx, now.Add(5*time.Second))
+	if !a.elector.Leading() {
+		t.Fatalf("a stepped down while its lease outlives the next renewal")
+	}
+	// The next tick would land on the expiry, so a stops now rather than
+	// while b may already hold the lease.
+	a.elector.step(ctx, now.Add(10*time.Second))
+	if a.elector.Leading() || a.stops.Load() != 1 || active.Load() != 0 {
+		t.Fatalf("a leading=%v stops=%d active=%d, want a stepped down", a.elector.Leading(), a.stops.Load(), active.Load())
+	}
+	b.elector.step(ctx, now.Add(15*time.Second))
+	if !b.elector.Leading() || active.Load() != 1 {
+		t.Fatalf("b leading=%v active=%d, want b alone leading", b.elector.Leading(), active.Load())
+	}
+}
+
+func TestStopReleasesLeaseForStandby(t *testing.T) {
+	ctx := context.Background()
+	dbA, dbB := openSharedDB(t)
+	var active atomic.Int32
+	a := newInstance(dbA, "a", &active)
+	b := newInstance(dbB, "b", &active)
+	now := time.Now()
+
+	a.elector.step(ctx, now)
+	b.elector.step(ctx, now)
+	if err := a.elector.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if a.stops.Load() != 1 || active.Load() != 0 {
+		t.Fatalf("after Stop: a stops=%d active=%d", a.stops.Load(), active.Load())
+	}
+
+	// No waiting for expiry: the lease was released on shutdown.
+	b.elector.step(ctx, now.Add(time.Second))
+	if !b.elector.Leading() || b.starts.Load() != 1 {
+		t.Fatalf("b leading=%v starts=%d, want b to take over at once", b.elector.Leading(), b.starts.Load())
+	}
+}
+
+func TestElectorsNeverLeadTogether(t *testing.T) {
+	dbA, dbB := openSharedDB(t)
+	var active, overlap atomic.Int32
+	a := newInstance(dbA, "a", &active)
+	b := newInstance(dbB, "b", &active)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	done := make(chan struct{})
+	for _, in := range []*instance{a, b} {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for time.Now().Before(deadline) {
+				in.elector.step(ctx, time.Now())
+				if active.Load() > 1 {
+					overlap.Add(1)
+				}
+			}
+		}()
+	}
+	<-done
+	<-done
+	if overlap.Load() != 0 || active.Load() != 1 {
+		t.Fatalf("overlap=%d active=%d, want exactly one leader throughout", overlap.Load(), active.Load())
+	}
+}
@@ -0,0 +1,145 @@
+// Package lifecycle lets background work be stopped on shutdown without
+// cutting off a cycle or reply that is already running.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Stopper is a component that stops starting new work and waits for the
+// work in flight, giving up when ctx ends.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// StopFunc adapts a function, such as a method value, to Stopper.
+type StopFunc func(ctx context.Context) error
+
+func (f StopFunc) Stop(ctx context.Context) error { return f(ctx) }
+
+// StopAll stops every component concurrently under a shared deadline and
+// returns their errors joined.
+func StopAll(ctx context.Context, stoppers ...Stopper) error {
+	errs := make([]error, len(stoppers))
+	var wg sync.WaitGroup
+	for idx, s := range stoppers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[idx] = s.Stop(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Loop runs a function on a ticker until its context ends or Stop is
+// called. The zero value is ready to use.
+type Loop struct {
+	mu       sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Start calls tick every interval on a new goroutine. Calling Start again
+// has no effect.
+func (l *Loop) Start(ctx context.Context, every time.Duration, tick func(ctx context.Context)) {
+	l.mu.Lock()
+	if l.done != nil {
+		l.mu.Unlock()
+		return
+	}
+	l.stop, l.done = make(chan struct{}), make(chan struct{})
+	stop, done := l.stop, l.done
+	l.mu.Unlock()
+
+	ticker := time.NewTicker(every)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+				// A tick and Stop can be ready together; don't start a
+				// new cycle once stopping.
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				tick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop prevents new cycles and waits for the current one to return. It
+// returns ctx.Err() if ctx ends first; the cycle keeps running until its
+// own context is cancelled.
+func (l *Loop) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	if l.done == nil {
+		l.mu.Unlock()
+		return nil
+	}
+	l.stopOnce.Do(func() { close(l.stop) })
+	done := l.done
+	l.mu.Unlock()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Group tracks in-flight tasks that aren't driven by a Loop, such as
+// replies to Discord messages. The zero value is ready to use.
+type Group struct {
+	mu       sync.Mutex
+	stopping bool
+	wg       sync.WaitGroup
+}
+
+// Begin registers a task. It returns false once Stop has been called, in
+// which case the task must not run. Every successful Begin needs a Done.
+func (g *Group) Begin() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopping {
+		return false
+	}
+	g.wg.Add(1)
+	return true
+}
+
+func (g *Group) Done() {
+	g.wg.Done()
+}
+
+// Stop refuses new tasks and waits for the running ones, returning
+// ctx.Err() if ctx ends first.
+func (g *Group) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopping = true
+	g.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoopStopWaitsForCurrentCycle(t *testing.T) {
+	var loop Loop
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var finished, cycles atomic.Int32
+	loop.Start(context.Background(), time.Millisecond, func(context.Context) {
+		cycles.Add(1)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		finished.Add(1)
+	})
+	<-started
+
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := loop.Stop(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop during a blocked cycle = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	if err := loop.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if finished.Load() != cycles.Load() {
+		t.Fatalf("finished %d of %d cycles before Stop returned", finished.Load(), cycles.Load())
+	}
+	after := cycles.Load()
+	time.Sleep(10 * time.Millisecond)
+	if cycles.Load() != after {
+		t.Fatalf("loop ran %d cycles after Stop", cycles.Load()-after)
+	}
+}
+
+func TestLoopStopBeforeStart(t *testing.T) {
+	var loop Loop
+	if err := loop.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop on an unstarted loop = %v", err)
+	}
+}
+
+func TestGroupRefusesNewTasksWhileDraining(t *testing.T) {
+	var group Group
+	if !group.Begin() {
+		t.Fatal("Begin before Stop = false")
+	}
+	stopped := make(chan error, 1)
+	go func() { stopped <- group.Stop(context.Background()) }()
+
+	deadline := time.Now().Add(time.Second)
+	for group.Begin() {
+		group.Done()
+		if time.Now().After(deadline) {
+			t.Fatal("Begin still succeeds after Stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned %v with a task in flight", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	group.Done()
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
+
+func TestStopAllSharesDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	slow := StopFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	start := time.Now()
+	err := StopAll(ctx, slow, slow, StopFunc(func(context.Context) error { return nil }))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("StopAll = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("StopAll took %s; stoppers should run concurrently", elapsed)
+	}
+}
@@ -0,0 +1,189 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultContextWindow is assumed for models without a configured
+	// window. Most models the bot runs on accept at least this much.
+	DefaultContextWindow = 8192
+	// DefaultCompletionReserve is kept free for the reply and, on the
+	// tool path, the tool results fed back to the model.
+	DefaultCompletionReserve = 1024
+
+	// messageTokenOverhead approximates the role and separator tokens
+	// every message costs on top of its text.
+	messageTokenOverhead = 4
+)
+
+// PromptBudget is how much of a model's context window a prompt may fill.
+type PromptBudget struct {
+	// ContextWindow is the window, in tokens, of models not listed in
+	// ModelContextWindows.
+	ContextWindow       int
+	ModelContextWindows map[string]int
+	// CompletionReserve is subtracted from the window before the prompt
+	// is fitted into it.
+	CompletionReserve int
+}
+
+// Window is the context window, in tokens, of model.
+func (b PromptBudget) Window(model string) int {
+	if window, ok := b.ModelContextWindows[model]; ok && window > 0 {
+		return window
+	}
+	if b.ContextWindow > 0 {
+		return b.ContextWindow
+	}
+	return DefaultContextWindow
+}
+
+// PromptFit reports how a message's history was fitted into the budget.
+type PromptFit struct {
+	Window        int              // the model's context window
+	FixedTokens   int              // system prompt, guild instructions, memories, tools and the message itself
+	HistoryTokens int              // history kept, including any summary
+	Dropped       []HistoryMessage // oldest first
+	Summarized    bool             // the dropped turns were folded into a summary entry
+}
+
+// EstimateTokens approximates how many tokens text costs. Tokenizers differ
+// per model, so it uses the common rule of thumb of four characters per
+// token, which overestimates slightly for English text.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// SetPromptBudget trims each message's history to what fits the routed
+// model's context window. Without a budget history is sent as given.
+func (s *Service) SetPromptBudget(budget PromptBudget) {
+	s.budget = &budget
+}
+
+// historySummaries holds, for one message, the summary of each cut of its
+// history, keyed by how many of the oldest entries were dropped, so fallback
+// attempts that drop the same turns reuse it instead of summarizing again.
+type historySummaries map[int]HistoryMessage
+
+// ContextWindow is the window of the first model in message's route, for
+// callers sizing the history they gather. It is 0 without a prompt budget.
+func (s *Service) ContextWindow(ctx context.Context, message Message) int {
+	if s == nil || s.budget == nil {
+		return 0
+	}
+	model := ""
+	if s.router != nil {
+		if models, err := s.router.modelsFor(ctx, message); err == nil && len(models) > 0 {
+			model = models[0]
+		}
+	}
+	return s.budget.Window(model)
+}
+
+// fitHistory drops the oldest history until the prompt fits the attempt's
+// model window less the completion reserve. Dropped turns are folded, with
+// any summary entry they include, into a new summary entry when it fits
+// too; the summary is written by the attempt's model under ctx, which
+// should not carry the attempt's own fallback timeout.
+func (s *Service) fitHistory(ctx context.Context, attempt routedCompleter, message Message, tools map[string]Tool, summaries historySummaries) (Message, PromptFit) {
+	if s.budget == nil {
+		return message, PromptFit{}
+	}
+	fit := PromptFit{Window: s.budget.Window(attempt.model)}
+	systemPrompt, err := s.buildSystemPrompt(ctx, message)
+	if err != nil {
+		// Generation fails on the same error; leave the message as is.
+		return message, fit
+	}
+	fit.FixedTokens = EstimateTokens(systemPrompt) + EstimateTokens(buildUserPrompt(message)) + 2*messageTokenOverhead
+	if len(tools) > 0 {
+		fit.FixedTokens += EstimateTokens(buildToolResponseStylePrompt()) + toolSchemaTokens(tools)
+	}
+	available := fit.Window - s.budget.CompletionReserve - fit.FixedTokens
+
+	kept := len(message.History)
+	used := 0
+	for kept > 0 {
+		cost := historyTokens(message.History[kept-1])
+		if used+cost > available {
+			break
+		}
+		used += cost
+		kept--
+	}
+	if kept == 0 {
+		fit.HistoryTokens = used
+		return message, fit
+	}
+
+	fit.Dropped = message.History[:kept]
+	history := message.History[kept:]
+	summary, ok := summaries[kept]
+	if !ok {
+		// A failed summary is left out of the cache, so the next model in
+		// the chain gets to try.
+		if summary, ok = s.summarizeDropped(ctx, attempt.completer, message.ChannelID, fit.Dropped); ok {
+			summaries[kept] = summary
+		}
+	}
+	if ok {
+		if cost := historyTokens(summary); used+cost <= available {
+			history = append([]HistoryMessage{summary}, history...)
+			used += cost
+			fit.Summarized = true
+		}
+	}
+	fit.HistoryTokens = used
+	message.History = history
+	return message, fit
+}
+
+// summarizeDropped condenses turns cut from the history, carrying over an
+// earlier summary entry among them.
+func (s *Service) summarizeDropped(ctx context.Context, completer Completer, channelID string, dropped []HistoryMessage) (HistoryMessage, bool) {
+	previous := ""
+	turns := make([]HistoryMessage, 0, len(dropped))
+	for _, h := range dropped {
+		if h.IsSummary {
+			previous = h.Content
+			continue
+		}
+		turns = append(turns, h)
+	}
+	if len(turns) == 0 {
+		return HistoryMessage{}, false
+	}
+	summary, err := summarizeHistory(ctx, completer, previous, turns)
+	if err != nil {
+		log.Printf("llm history summary failed, dropping turns: channel_id=%s dropped=%d error=%v", channelID, len(dropped), err)
+		return HistoryMessage{}, false
+	}
+	if summary == "" {
+		return HistoryMessage{}, false
+	}
+	return HistoryMessage{Content: summary, IsSummary: true}, true
+}
+
+func historyTokens(h HistoryMessage) int {
+	label := h.Author
+	if h.IsSummary {
+		label = historySummaryLabel
+	}
+	return EstimateTokens(label+": "+h.Content) + messageTokenOverhead
+}
+
+func toolSchemaTokens(tools map[string]Tool) int {
+	total := 0
+	for _, tool := range chatTools(tools) {
+		schema, err := json.Marshal(tool)
+		if err != nil {
+			continue
+		}
+		total += EstimateTokens(string(schema))
+	}
+	return total
+}
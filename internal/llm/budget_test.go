@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokensRoundsUpPerFourCharacters(t *testing.T) {
+	for text, want := range map[string]int{"": 0, "hi": 1, "abcd": 1, "abcde": 2, "日本語です": 2} {
+		if got := EstimateTokens(text); got != want {
+			t.Fatalf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestPromptBudgetWindowPerModel(t *testing.T) {
+	budget := PromptBudget{ContextWindow: 4096, ModelContextWindows: map[string]int{"big": 131072}}
+	if budget.Window("big") != 131072 || budget.Window("small") != 4096 || (PromptBudget{}).Window("") != DefaultContextWindow {
+		t.Fatalf("windows = %d %d %d", budget.Window("big"), budget.Window("small"), (PromptBudget{}).Window(""))
+	}
+}
+
+func TestServiceContextWindowFollowsRoute(t *testing.T) {
+	router := testRouter(nil, RouterConfig{Models: []string{"small"}, Routes: staticModelRoutes{"guild-1": {"big"}}})
+	service := NewService(router.Default())
+	service.SetRouter(router)
+	if got := service.ContextWindow(context.Background(), Message{GuildID: "guild-1"}); got != 0 {
+		t.Fatalf("ContextWindow without a budget = %d, want 0", got)
+	}
+	service.SetPromptBudget(PromptBudget{ContextWindow: 4096, ModelContextWindows: map[string]int{"big": 131072}})
+	if got := service.ContextWindow(context.Background(), Message{GuildID: "guild-1"}); got != 131072 {
+		t.Fatalf("routed ContextWindow = %d, want 131072", got)
+	}
+	if got := service.ContextWindow(context.Background(), Message{GuildID: "guild-2"}); got != 4096 {
+		t.Fatalf("default ContextWindow = %d, want 4096", got)
+	}
+}
+
+func budgetTestHistory() []HistoryMessage {
+	return []HistoryMessage{
+		{Content: "Alice and Bob are planning a trip.", IsSummary: true},
+		{Author: "Alice", Content: strings.Repeat("a", 80)},
+		{Author: "Bob", Content: strings.Repeat("b", 80)},
+		{Author: "Alice", Content: strings.Repeat("c", 80)},
+		{Author: "Mizu", Content: strings.Repeat("d", 80), IsBot: true},
+	}
+}
+
+// windowKeeping returns a window that leaves room for exactly the newest
+// keep history entries of message plus extra tokens.
+func windowKeeping(t *testing.T, message Message, keep, reserve, extra int) int {
+	t.Helper()
+	service := NewService(&fakeCompleter{})
+	service.SetPromptBudget(PromptBudget{ContextWindow: 1 << 20})
+	_, fit := service.fitHistory(context.Background(), routedCompleter{}, message, nil, historySummaries{})
+	window := fit.FixedTokens + reserve + extra
+	for _, h := range message.History[len(message.History)-keep:] {
+		window += historyTokens(h)
+	}
+	return window
+}
+
+func TestServiceDropsOldestHistoryAndSummarizesIt(t *testing.T) {
+	completer := &fakeCompleter{responses: []string{"Trip, budget agreed.", "answer"}}
+	service := NewService(completer)
+	message := Message{BotName: "Mizu", Content: "so where do we stay?", History: budgetTestHistory()}
+	summaryCost := historyTokens(HistoryMessage{Content: "Trip, budget agreed.", IsSummary: true})
+	service.SetPromptBudget(PromptBudget{ContextWindow: windowKeeping(t, message, 2, 100, summaryCost), CompletionReserve: 100})
+
+	response, err := service.GenerateResponseWithMetrics(context.Background(), message)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if len(response.Prompt.Dropped) != 3 || !response.Prompt.Dropped[0].IsSummary || !response.Prompt.Summarized {
+		t.Fatalf("fit = %+v, want the summary and two oldest turns dropped and summarized", response.Prompt)
+	}
+	summarize := completer.requests[0].UserPrompt
+	if !strings.Contains(summarize, "Summary so far:\nAlice and Bob are planning a trip.") || !strings.Contains(summarize, "Bob: bbbb") {
+		t.Fatalf("summarize prompt = %q", summarize)
+	}
+	prompt := completer.requests[1].UserPrompt
+	if !strings.Contains(prompt, historySummaryLabel+": Trip, budget agreed.") || strings.Contains(prompt, "aaaa") || !strings.Contains(prompt, "cccc") || !strings.Contains(prompt, "Mizu: dddd") {
+		t.Fatalf("prompt = %q", prompt)
+	}
+}
+
+func TestServiceDropsHistoryWithoutSummaryWhenItDoesNotFit(t *testing.T) {
+	completer := &fakeCompleter{responses: []string{strings.Repeat("long summary ", 40), "answer"}}
+	service := NewService(completer)
+	message := Message{Content: "so where do we stay?", History: budgetTestHistory()}
+	service.SetPromptBudget(PromptBudget{ContextWindow: windowKeeping(t, message, 1, 0, 0)})
+
+	response, err := service.GenerateResponseWithMetrics(context.Background(), message)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if len(response.Prompt.Dropped) != 4 || response.Prompt.Summarized {
+		t.Fatalf("fit = %+v, want four dropped and no summary", response.Prompt)
+	}
+	prompt := completer.requests[1].UserPrompt
+	if strings.Contains(prompt, "long summary") || strings.Contains(prompt, "cccc") || !strings.Contains(prompt, "dddd") {
+		t.Fatalf("prompt = %q", prompt)
+	}
+}
+
+func TestServiceKeepsHistoryThatFitsTheBudget(t *testing.T) {
+	completer := &fakeCompleter{responses: []string{"answer"}}
+	service := NewService(completer)
+	service.SetPromptBudget(PromptBudget{})
+
+	response, err := service.GenerateResponseWithMetrics(context.Background(), Message{Content: "hello", History: budgetTestHistory()})
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if len(completer.requests) != 1 || len(response.Prompt.Dropped) != 0 || response.Prompt.Window != DefaultContextWindow || response.Prompt.HistoryTokens == 0 {
+		t.Fatalf("requests = %d fit = %+v", len(completer.requests), response.Prompt)
+	}
+}
+
+// crashingCompleter answers its first ok requests and fails the rest.
+type crashingCompleter struct {
+	fakeCompleter
+	ok int
+}
+
+func (c *crashingCompleter) Complete(ctx context.Context, request CompletionRequest) (string, error) {
+	if len(c.requests) >= c.ok {
+		c.requests = append(c.requests, request)
+		return "", errors.New("model crashed")
+	}
+	return c.fakeCompleter.Complete(ctx, request)
+}
+
+func TestServiceSummarizesOnceWithRoutedModelAcrossFallbacks(t *testing.T) {
+	primary := &crashingCompleter{fakeCompleter: fakeCompleter{responses: []string{"Trip, budget agreed."}}, ok: 1}
+	fallback := &fakeCompleter{responses: []string{"answer"}}
+	defaultModel := &fakeCompleter{}
+	router := NewRouter(RouterConfig{
+		Models: []string{"big", "small"},
+		NewCompleter: func(model string) Completer {
+			if model == "big" {
+				return primary
+			}
+			return fallback
+		},
+	})
+	service := NewService(defaultModel)
+	service.SetRouter(router)
+	message := Message{BotName: "Mizu", Content: "so where do we stay?", History: budgetTestHistory()}
+	summaryCost := historyTokens(HistoryMessage{Content: "Trip, budget agreed.", IsSummary: true})
+	service.SetPromptBudget(PromptBudget{ContextWindow: windowKeeping(t, message, 2, 100, summaryCost), CompletionReserve: 100})
+
+	response, err := service.GenerateResponseWithMetrics(context.Background(), message)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if response.Model != "small" || !response.Prompt.Summarized {
+		t.Fatalf("response = %+v, want small to answer with the summary", response)
+	}
+	if len(primary.requests) != 2 || len(fallback.requests) != 1 || len(defaultModel.requests) != 0 {
+		t.Fatalf("requests: big=%d small=%d default=%d, want one summary by big and one answer each", len(primary.requests), len(fallback.requests), len(defaultModel.requests))
+	}
+	if prompt := fallback.requests[0].UserPrompt; !strings.Contains(prompt, historySummaryLabel+": Trip, budget agreed.") {
+		t.Fatalf("fallback prompt = %q", prompt)
+	}
+}
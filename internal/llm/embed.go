@@ -0,0 +1,189 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultOllamaEmbedModel = "nomic-embed-text"
+	// DefaultToolSimilarity is the cosine similarity between a message and
+	// a tool's description above which the tool is offered to the model.
+	DefaultToolSimilarity = 0.5
+)
+
+// Embedder turns texts into vectors whose cosine similarity reflects how
+// close their meanings are.
+type Embedder interface {
+	Embed(ctx context.Context, inputs []string) ([][]float64, error)
+}
+
+type OllamaEmbedder struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func NewOllamaEmbedder(cfg OllamaConfig) *OllamaEmbedder {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = DefaultOllamaEmbedModel
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &OllamaEmbedder{
+		baseURL: baseURL,
+		model:   model,
+		client:  httpClientWithTimeout(cfg.HTTPClient, timeout),
+	}
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+	Error      string      `json:"error"`
+}
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	body, err := json.Marshal(ollamaEmbedRequest{Model: e.model, Input: inputs})
+	if err != nil {
+		return nil, fmt.Errorf("marshal ollama embed request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create ollama embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call ollama embed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read ollama embed response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("ollama embed returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var out ollamaEmbedResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("decode ollama embed response: %w", err)
+	}
+	if out.Error != "" {
+		return nil, fmt.Errorf("ollama embed error: %s", out.Error)
+	}
+	if len(out.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("ollama embed returned %d embeddings for %d inputs", len(out.Embeddings), len(inputs))
+	}
+	return out.Embeddings, nil
+}
+
+// toolIndex selects tools by embedding similarity to the user's message.
+// Tool descriptions are embedded on first use and kept; a failed attempt
+// is retried on the next message.
+type toolIndex struct {
+	embedder  Embedder
+	threshold float64
+
+	mu      sync.Mutex
+	vectors map[string][]float64
+}
+
+func newToolIndex(embedder Embedder, threshold float64) *toolIndex {
+	if threshold <= 0 {
+		threshold = DefaultToolSimilarity
+	}
+	return &toolIndex{embedder: embedder, threshold: threshold}
+}
+
+// similar returns the names of the tools whose descriptions are at least
+// threshold-similar to content.
+func (x *toolIndex) similar(ctx context.Context, tools map[string]Tool, content string) (map[string]bool, error) {
+	vectors, err := x.toolVectors(ctx, tools)
+	if err != nil {
+		return nil, err
+	}
+	embedded, err := x.embedder.Embed(ctx, []string{content})
+	if err != nil {
+		return nil, fmt.Errorf("embed message: %w", err)
+	}
+	out := make(map[string]bool)
+	for name, vector := range vectors {
+		if cosineSimilarity(embedded[0], vector) >= x.threshold {
+			out[name] = true
+		}
+	}
+	return out, nil
+}
+
+func (x *toolIndex) toolVectors(ctx context.Context, tools map[string]Tool) (map[string][]float64, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.vectors != nil {
+		return x.vectors, nil
+	}
+	names := make([]string, 0, len(tools))
+	texts := make([]string, 0, len(tools))
+	for name, tool := range tools {
+		names = append(names, name)
+		texts = append(texts, toolEmbeddingText(tool))
+	}
+	embedded, err := x.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed tool descriptions: %w", err)
+	}
+	vectors := make(map[string][]float64, len(names))
+	for idx, name := range names {
+		vectors[name] = embedded[idx]
+	}
+	x.vectors = vectors
+	return vectors, nil
+}
+
+// toolEmbeddingText describes a tool the way a user might ask for it: its
+// name in words, its description and its keywords.
+func toolEmbeddingText(tool Tool) string {
+	text := strings.ReplaceAll(tool.Name, "_", " ") + ": " + tool.Description
+	if len(tool.Keywords) > 0 {
+		text += " Related: " + strings.Join(tool.Keywords, ", ") + "."
+	}
+	return text
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOllamaEmbedderEmbed(t *testing.T) {
+	var got ollamaEmbedRequest
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/embed" {
+			t.Fatalf("path = %s, want /api/embed", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		var body bytes.Buffer
+		_ = json.NewEncoder(&body).Encode(ollamaEmbedResponse{Embeddings: [][]float64{{1, 0}, {0, 1}}})
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(&body), Header: make(http.Header)}, nil
+	})
+	embedder := NewOllamaEmbedder(OllamaConfig{
+		BaseURL:    "http://ollama.test",
+		Timeout:    time.Second,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if got.Model != DefaultOllamaEmbedModel || len(got.Input) != 2 {
+		t.Fatalf("request = %+v", got)
+	}
+	if len(vectors) != 2 || vectors[1][1] != 1 {
+		t.Fatalf("vectors = %v", vectors)
+	}
+}
+
+// axisEmbedder maps each text to a fixed vector, or fails with err.
+type axisEmbedder struct {
+	vectors map[string][]float64
+	err     error
+	calls   int
+}
+
+func (e *axisEmbedder) Embed(_ context.Context, inputs []string) ([][]float64, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([][]float64, 0, len(inputs))
+	for _, input := range inputs {
+		vector, ok := e.vectors[input]
+		if !ok {
+			vector = []float64{0, 0, 1}
+		}
+		out = append(out, vector)
+	}
+	return out, nil
+}
+
+func selectionTools() []Tool {
+	noop := func(context.Context, ToolContext, json.RawMessage) (ToolResult, error) { return ToolResult{}, nil }
+	return []Tool{
+		{Name: "reminder_create", Description: "Create a reminder.", Keywords: []string{"remind"}, Execute: noop},
+		{Name: "user_timezone_set", Description: "Set the timezone.", Keywords: []string{"timezone"}, Execute: noop},
+	}
+}
+
+func TestServiceSelectsToolsBySimilarity(t *testing.T) {
+	tools := selectionTools()
+	embedder := &axisEmbedder{vectors: map[string][]float64{
+		toolEmbeddingText(tools[0]): {1, 0, 0},
+		toolEmbeddingText(tools[1]): {0, 1, 0},
+		"ping me at 5":              {0.9, 0.1, 0.1},
+	}}
+	service := NewService(&fakeCompleter{}, tools...)
+	service.SetEmbedder(embedder, 0)
+
+	got := service.toolsForMessage(context.Background(), Message{Content: "ping me at 5"})
+	if len(got) != 1 || got["reminder_create"].Name == "" {
+		t.Fatalf("tools = %v, want only reminder_create", got)
+	}
+	got = service.toolsForMessage(context.Background(), Message{Content: "set my timezone"})
+	if len(got) != 1 || got["user_timezone_set"].Name == "" {
+		t.Fatalf("tools = %v, want the keyword match", got)
+	}
+	if embedder.calls != 3 {
+		t.Fatalf("embed calls = %d, want tools embedded once plus one per message", embedder.calls)
+	}
+}
+
+func TestServiceFallsBackToKeywordsWhenEmbeddingFails(t *testing.T) {
+	service := NewService(&fakeCompleter{}, selectionTools()...)
+	service.SetEmbedder(&axisEmbedder{err: errors.New("model not found")}, 0)
+
+	got := service.toolsForMessage(context.Background(), Message{Content: "remind me at 5"})
+	if len(got) != 1 || got["reminder_create"].Name == "" {
+		t.Fatalf("tools = %v, want keyword fallback", got)
+	}
+}
@@ -0,0 +1,8 @@
+package llm
+
+import "context"
+
+// ToolsForMessage exposes tool selection to the external eval tests.
+func (s *Service) ToolsForMessage(ctx context.Context, message Message) map[string]Tool {
+	return s.toolsForMessage(ctx, message)
+}
@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -17,15 +18,20 @@ const (
 )
 
 type OllamaConfig struct {
-	BaseURL    string
-	Model      string
-	Timeout    time.Duration
-	HTTPClient *http.Client
+	BaseURL string
+	Model   string
+	// ContextWindow, when set, is sent as num_ctx so Ollama loads the model
+	// with the window the prompt was fitted to rather than its own smaller
+	// default, which would silently cut the prompt from the front.
+	ContextWindow int
+	Timeout       time.Duration
+	HTTPClient    *http.Client
 }
 
 type OllamaClient struct {
 	baseURL string
 	model   string
+	options *ollamaOptions
 	client  *http.Client
 }
 
@@ -42,14 +48,19 @@ func NewOllamaClient(cfg OllamaConfig) *OllamaClient {
 	if timeout <= 0 {
 		timeout = 60 * time.Second
 	}
+	var options *ollamaOptions
+	if cfg.ContextWindow > 0 {
+		options = &ollamaOptions{NumCtx: cfg.ContextWindow}
+	}
 	return &OllamaClient{
 		baseURL: baseURL,
 		model:   model,
-		client:  ollamaHTTPClient(cfg.HTTPClient, timeout),
+		options: options,
+		client:  httpClientWithTimeout(cfg.HTTPClient, timeout),
 	}
 }
 
-func ollamaHTTPClient(client *http.Client, timeout time.Duration) *http.Client {
+func httpClientWithTimeout(client *http.Client, timeout time.Duration) *http.Client {
 	if client != nil {
 		return client
 	}
@@ -57,14 +68,20 @@ func ollamaHTTPClient(client *http.Client, timeout time.Duration) *http.Client {
 }
 
 type ollamaGenerateRequest struct {
-	Model  string `json:"model"`
-	System string `json:"system"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string         `json:"model"`
+	System  string         `json:"system"`
+	Prompt  string         `json:"prompt"`
+	Stream  bool           `json:"stream"`
+	Options *ollamaOptions `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	NumCtx int `json:"num_ctx,omitempty"`
 }
 
 type ollamaGenerateResponse struct {
 	Response        string `json:"response"`
+	Done            bool   `json:"done"`
 	Error           string `json:"error"`
 	PromptEvalCount int64  `json:"prompt_eval_count"`
 	EvalCount       int64  `json:"eval_count"`
@@ -75,6 +92,7 @@ type ollamaChatRequest struct {
 	Messages []ollamaChatMessage `json:"messages"`
 	Tools    []ollamaTool        `json:"tools,omitempty"`
 	Stream   bool                `json:"stream"`
+	Options  *ollamaOptions      `json:"options,omitempty"`
 }
 
 type ollamaChatMessage struct {
@@ -106,6 +124,7 @@ type ollamaToolCallFunction struct {
 
 type ollamaChatResponse struct {
 	Message         ollamaChatMessage `json:"message"`
+	Done            bool              `json:"done"`
 	Error           string            `json:"error"`
 	PromptEvalCount int64             `json:"prompt_eval_count"`
 	EvalCount       int64             `json:"eval_count"`
@@ -121,10 +140,11 @@ func (c *OllamaClient) CompleteWithMetrics(ctx context.Context, request Completi
 		return CompletionResponse{}, nil
 	}
 	reqBody := ollamaGenerateRequest{
-		Model:  c.model,
-		System: request.SystemPrompt,
-		Prompt: request.UserPrompt,
-		Stream: false,
+		Model:   c.model,
+		System:  request.SystemPrompt,
+		Prompt:  request.UserPrompt,
+		Stream:  false,
+		Options: c.options,
 	}
 	body, err := json.Marshal(reqBody)
 	if err != nil {
@@ -183,6 +203,7 @@ func (c *OllamaClient) Chat(ctx context.Context, request ChatRequest) (ChatRespo
 		Messages: ollamaMessages(request.Messages),
 		Tools:    ollamaTools(request.Tools),
 		Stream:   false,
+		Options:  c.options,
 	}
 	body, err := json.Marshal(reqBody)
 	if err != nil {
@@ -226,6 +247,147 @@ func (c *OllamaClient) Chat(ctx context.Context, request ChatRequest) (ChatRespo
 	}, nil
 }
 
+// CompleteStream is CompleteWithMetrics with Ollama's streamed output:
+// onDelta receives each piece of the response as it is generated.
+func (c *OllamaClient) CompleteStream(ctx context.Context, request CompletionRequest, onDelta func(string)) (CompletionResponse, error) {
+	if c == nil {
+		return CompletionResponse{}, nil
+	}
+	resp, err := c.openStream(ctx, "/api/generate", "ollama", ollamaGenerateRequest{
+		Model:   c.model,
+		System:  request.SystemPrompt,
+		Prompt:  request.UserPrompt,
+		Stream:  true,
+		Options: c.options,
+	})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	var usage Usage
+	err = readOllamaStream(resp.Body, func(line []byte) error {
+		var chunk ollamaGenerateResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return fmt.Errorf("decode ollama stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+		if chunk.Response != "" {
+			content.WriteString(chunk.Response)
+			if onDelta != nil {
+				onDelta(chunk.Response)
+			}
+		}
+		if chunk.Done {
+			usage = Usage{PromptTokens: chunk.PromptEvalCount, CompletionTokens: chunk.EvalCount}
+		}
+		return nil
+	})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	return CompletionResponse{Content: strings.TrimSpace(content.String()), Usage: usage}, nil
+}
+
+// ChatStream is Chat with Ollama's streamed output. Tool calls arrive in
+// their own chunks and are collected into the response.
+func (c *OllamaClient) ChatStream(ctx context.Context, request ChatRequest, onDelta func(string)) (ChatResponse, error) {
+	if c == nil {
+		return ChatResponse{}, nil
+	}
+	resp, err := c.openStream(ctx, "/api/chat", "ollama chat", ollamaChatRequest{
+		Model:    c.model,
+		Messages: ollamaMessages(request.Messages),
+		Tools:    ollamaTools(request.Tools),
+		Stream:   true,
+		Options:  c.options,
+	})
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	var calls []ollamaToolCall
+	var usage Usage
+	err = readOllamaStream(resp.Body, func(line []byte) error {
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return fmt.Errorf("decode ollama chat stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama chat error: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			if onDelta != nil {
+				onDelta(chunk.Message.Content)
+			}
+		}
+		calls = append(calls, chunk.Message.ToolCalls...)
+		if chunk.Done {
+			usage = Usage{PromptTokens: chunk.PromptEvalCount, CompletionTokens: chunk.EvalCount}
+		}
+		return nil
+	})
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	return ChatResponse{
+		Content:   strings.TrimSpace(content.String()),
+		ToolCalls: chatToolCalls(calls),
+		Usage:     usage,
+	}, nil
+}
+
+// openStream posts a streaming request and returns the response once
+// Ollama has accepted it. The caller closes the body.
+func (c *OllamaClient) openStream(ctx context.Context, path, label string, payload any) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s request: %w", label, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create %s request: %w", label, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", label, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("%s returned %s: %s", label, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return resp, nil
+}
+
+// readOllamaStream calls handle for each newline-delimited JSON object in a
+// streamed response.
+func readOllamaStream(body io.Reader, handle func(line []byte) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := handle(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read ollama stream: %w", err)
+	}
+	return nil
+}
+
 func ollamaMessages(messages []ChatMessage) []ollamaChatMessage {
 	out := make([]ollamaChatMessage, 0, len(messages))
 	for _, message := range messages {
@@ -259,7 +421,7 @@ func ollamaToolCalls(calls []ChatToolCall) []ollamaToolCall {
 	}
 	out := make([]ollamaToolCall, 0, len(calls))
 	for _, call := range calls {
-		out = append(out, ollamaToolCall{Function: ollamaToolCallFunction(call)})
+		out = append(out, ollamaToolCall{Function: ollamaToolCallFunction{Name: call.Name, Arguments: call.Arguments}})
 	}
 	return out
 }
@@ -316,6 +478,9 @@ func buildHistoryBlock(history []HistoryMessage, botName string) string {
 		if h.IsBot {
 			speaker = botName
 		}
+		if h.IsSummary {
+			speaker = historySummaryLabel
+		}
 		fmt.Fprintf(&b, "%s: %s\n", speaker, h.Content)
 	}
 	return b.String()
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -63,6 +64,9 @@ func TestOllamaClientGenerateResponse(t *testing.T) {
 	if got.Stream {
 		t.Fatalf("stream = true, want false")
 	}
+	if got.Options != nil {
+		t.Fatalf("options = %+v, want none without a context window", got.Options)
+	}
 }
 
 func TestOllamaClientChatWithTools(t *testing.T) {
@@ -94,8 +98,9 @@ func TestOllamaClientChatWithTools(t *testing.T) {
 	})
 
 	client := NewOllamaClient(OllamaConfig{
-		BaseURL: "http://ollama.test",
-		Model:   "test-model",
+		BaseURL:       "http://ollama.test",
+		Model:         "test-model",
+		ContextWindow: 32768,
 		HTTPClient: &http.Client{
 			Transport: transport,
 		},
@@ -114,6 +119,9 @@ func TestOllamaClientChatWithTools(t *testing.T) {
 	if got.Model != "test-model" {
 		t.Fatalf("model = %q, want test-model", got.Model)
 	}
+	if got.Options == nil || got.Options.NumCtx != 32768 {
+		t.Fatalf("options = %+v, want num_ctx 32768", got.Options)
+	}
 	if len(got.Tools) != 1 || got.Tools[0].Type != "function" || got.Tools[0].Function.Name != "reminder_list_active" {
 		t.Fatalf("tool request mismatch: %#v", got.Tools)
 	}
@@ -130,3 +138,61 @@ type roundTripFunc func(*http.Request) (*http.Response, error)
 func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 	return f(r)
 }
+
+func TestOllamaClientChatStream(t *testing.T) {
+	var got ollamaChatRequest
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		body := strings.Join([]string{
+			`{"message":{"role":"assistant","content":"Hel"},"done":false}`,
+			`{"message":{"role":"assistant","content":"lo "},"done":false}`,
+			`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"reminder_list","arguments":{}}}]},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":9,"eval_count":4}`,
+		}, "\n")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	client := NewOllamaClient(OllamaConfig{BaseURL: "http://ollama.test", HTTPClient: &http.Client{Transport: transport}})
+
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}}, func(d string) {
+		deltas = append(deltas, d)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if !got.Stream {
+		t.Fatalf("request did not ask for a stream")
+	}
+	if strings.Join(deltas, "|") != "Hel|lo " || resp.Content != "Hello" {
+		t.Fatalf("deltas=%q content=%q", deltas, resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "reminder_list" {
+		t.Fatalf("tool calls = %+v", resp.ToolCalls)
+	}
+	if resp.Usage.PromptTokens != 9 || resp.Usage.CompletionTokens != 4 {
+		t.Fatalf("usage = %+v, want prompt=9 completion=4", resp.Usage)
+	}
+}
+
+func TestOllamaClientCompleteStreamError(t *testing.T) {
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := `{"response":"partial","done":false}` + "\n" + `{"error":"model unloaded"}` + "\n"
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	client := NewOllamaClient(OllamaConfig{BaseURL: "http://ollama.test", HTTPClient: &http.Client{Transport: transport}})
+	if _, err := client.CompleteStream(context.Background(), CompletionRequest{UserPrompt: "hi"}, nil); err == nil || !strings.Contains(err.Error(), "model unloaded") {
+		t.Fatalf("CompleteStream error = %v, want the streamed error", err)
+	}
+}
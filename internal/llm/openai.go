@@ -0,0 +1,248 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIConfig configures a backend that speaks the OpenAI chat completions
+// API, such as llama.cpp server, vLLM, LM Studio or a hosted provider.
+// BaseURL includes the version prefix, e.g. "http://localhost:8080/v1".
+type OpenAIConfig struct {
+	BaseURL    string
+	APIKey     string
+	Model      string
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+type OpenAIClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewOpenAIClient(cfg OpenAIConfig) *OpenAIClient {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAIClient{
+		baseURL: baseURL,
+		apiKey:  strings.TrimSpace(cfg.APIKey),
+		model:   strings.TrimSpace(cfg.Model),
+		client:  httpClientWithTimeout(cfg.HTTPClient, timeout),
+	}
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+	Tools    []openAITool        `json:"tools,omitempty"`
+}
+
+type openAIChatMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+}
+
+type openAITool struct {
+	Type     string             `json:"type"`
+	Function openAIToolFunction `json:"function"`
+}
+
+type openAIToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string                 `json:"id"`
+	Type     string                 `json:"type"`
+	Function openAIToolCallFunction `json:"function"`
+}
+
+// openAIToolCallFunction carries arguments as a JSON-encoded string, unlike
+// Ollama's inline object.
+type openAIToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *openAIError `json:"error"`
+}
+
+type openAIError struct {
+	Message string `json:"message"`
+}
+
+func (c *OpenAIClient) Complete(ctx context.Context, request CompletionRequest) (string, error) {
+	response, err := c.CompleteWithMetrics(ctx, request)
+	return response.Content, err
+}
+
+func (c *OpenAIClient) CompleteWithMetrics(ctx context.Context, request CompletionRequest) (CompletionResponse, error) {
+	var messages []ChatMessage
+	if request.SystemPrompt != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: request.SystemPrompt})
+	}
+	messages = append(messages, ChatMessage{Role: "user", Content: request.UserPrompt})
+	response, err := c.Chat(ctx, ChatRequest{Messages: messages})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	return CompletionResponse{Content: response.Content, Usage: response.Usage}, nil
+}
+
+func (c *OpenAIClient) Chat(ctx context.Context, request ChatRequest) (ChatResponse, error) {
+	if c == nil {
+		return ChatResponse{}, nil
+	}
+	reqBody := openAIChatRequest{
+		Model:    c.model,
+		Messages: openAIMessages(request.Messages),
+		Tools:    openAITools(request.Tools),
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("marshal openai chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("create openai chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("call openai chat: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("read openai chat response: %w", err)
+	}
+	var out openAIChatResponse
+	decodeErr := json.Unmarshal(respBody, &out)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := strings.TrimSpace(string(respBody))
+		if decodeErr == nil && out.Error != nil && out.Error.Message != "" {
+			detail = out.Error.Message
+		}
+		return ChatResponse{}, fmt.Errorf("openai chat returned %s: %s", resp.Status, detail)
+	}
+	if decodeErr != nil {
+		return ChatResponse{}, fmt.Errorf("decode openai chat response: %w", decodeErr)
+	}
+	if out.Error != nil {
+		return ChatResponse{}, fmt.Errorf("openai chat error: %s", out.Error.Message)
+	}
+	if len(out.Choices) == 0 {
+		return ChatResponse{}, fmt.Errorf("openai chat returned no choices")
+	}
+	message := out.Choices[0].Message
+	return ChatResponse{
+		Content:   strings.TrimSpace(message.Content),
+		ToolCalls: openAIChatToolCalls(message.ToolCalls),
+		Usage: Usage{
+			PromptTokens:     out.Usage.PromptTokens,
+			CompletionTokens: out.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+func openAIMessages(messages []ChatMessage) []openAIChatMessage {
+	out := make([]openAIChatMessage, 0, len(messages))
+	for _, message := range messages {
+		out = append(out, openAIChatMessage{
+			Role:       message.Role,
+			Content:    message.Content,
+			ToolCallID: message.ToolCallID,
+			ToolCalls:  openAIToolCalls(message.ToolCalls),
+		})
+	}
+	return out
+}
+
+func openAITools(tools []ChatTool) []openAITool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]openAITool, 0, len(tools))
+	for _, tool := range tools {
+		out = append(out, openAITool{
+			Type:     "function",
+			Function: openAIToolFunction(tool),
+		})
+	}
+	return out
+}
+
+func openAIToolCalls(calls []ChatToolCall) []openAIToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]openAIToolCall, 0, len(calls))
+	for _, call := range calls {
+		args := strings.TrimSpace(string(call.Arguments))
+		if args == "" {
+			args = "{}"
+		}
+		out = append(out, openAIToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: openAIToolCallFunction{Name: call.Name, Arguments: args},
+		})
+	}
+	return out
+}
+
+func openAIChatToolCalls(calls []openAIToolCall) []ChatToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]ChatToolCall, 0, len(calls))
+	for _, call := range calls {
+		args := json.RawMessage(strings.TrimSpace(call.Function.Arguments))
+		if len(args) == 0 || !json.Valid(args) {
+			// Tools validate their own arguments; an unparseable string
+			// becomes an empty object so the tool reports what's missing.
+			args = json.RawMessage(`{}`)
+		}
+		out = append(out, ChatToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: args,
+		})
+	}
+	return out
+}
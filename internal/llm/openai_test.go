@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIClientChatWithTools(t *testing.T) {
+	var got openAIChatRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s, want /v1/chat/completions", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "reminder_list_active", "arguments": "{\"limit\":5}"}}
+			]}}],
+			"usage": {"prompt_tokens": 11, "completion_tokens": 2, "total_tokens": 13}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL + "/v1/", APIKey: "sk-test", Model: "test-model"})
+	resp, err := client.Chat(context.Background(), ChatRequest{
+		Messages: []ChatMessage{
+			{Role: "user", Content: "list reminders"},
+			{Role: "assistant", ToolCalls: []ChatToolCall{{ID: "call_0", Name: "reminder_list_active"}}},
+			{Role: "tool", ToolName: "reminder_list_active", ToolCallID: "call_0", Content: "none"},
+		},
+		Tools: []ChatTool{{
+			Name:        "reminder_list_active",
+			Description: "List reminders.",
+			Parameters:  json.RawMessage(`{"type":"object"}`),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if auth != "Bearer sk-test" || got.Model != "test-model" {
+		t.Fatalf("auth=%q model=%q", auth, got.Model)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Type != "function" || got.Tools[0].Function.Name != "reminder_list_active" {
+		t.Fatalf("tool request mismatch: %#v", got.Tools)
+	}
+	assistant, tool := got.Messages[1], got.Messages[2]
+	if len(assistant.ToolCalls) != 1 || assistant.ToolCalls[0].ID != "call_0" || assistant.ToolCalls[0].Function.Arguments != "{}" {
+		t.Fatalf("assistant tool calls = %#v", assistant.ToolCalls)
+	}
+	if tool.ToolCallID != "call_0" {
+		t.Fatalf("tool message id = %q, want call_0", tool.ToolCallID)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_1" || string(resp.ToolCalls[0].Arguments) != `{"limit":5}` {
+		t.Fatalf("tool calls mismatch: %#v", resp.ToolCalls)
+	}
+	if resp.Usage.PromptTokens != 11 || resp.Usage.CompletionTokens != 2 {
+		t.Fatalf("usage = %+v, want prompt=11 completion=2", resp.Usage)
+	}
+}
+
+func TestOpenAIClientCompleteWithMetrics(t *testing.T) {
+	var got openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":" hi there "}}],"usage":{"prompt_tokens":7,"completion_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Model: "m"})
+	resp, err := client.CompleteWithMetrics(context.Background(), CompletionRequest{SystemPrompt: "system", UserPrompt: "hello"})
+	if err != nil {
+		t.Fatalf("CompleteWithMetrics: %v", err)
+	}
+	if resp.Content != "hi there" || resp.Usage.TotalTokens() != 10 {
+		t.Fatalf("response = %+v", resp)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Content != "hello" {
+		t.Fatalf("messages = %#v", got.Messages)
+	}
+}
+
+func TestOpenAIClientReportsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Model: "m"})
+	_, err := client.Chat(context.Background(), ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}})
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "Incorrect API key provided") {
+		t.Fatalf("error = %v, want the status and API message", err)
+	}
+}
+
+func TestServiceRunsToolLoopOverOpenAIClient(t *testing.T) {
+	var requests []openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_9","type":"function","function":{"name":"test_tool","arguments":"{}"}}]}}],"usage":{"prompt_tokens":5,"completion_tokens":1}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"done"}}],"usage":{"prompt_tokens":8,"completion_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	tool := Tool{
+		Name:       "test_tool",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Execute: func(context.Context, ToolContext, json.RawMessage) (ToolResult, error) {
+			return ToolResult{Content: "tool output"}, nil
+		},
+	}
+	service := NewService(NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Model: "m"}), tool)
+	got, err := service.GenerateResponseWithMetrics(context.Background(), Message{Content: "use the tool"})
+	if err != nil {
+		t.Fatalf("GenerateResponseWithMetrics: %v", err)
+	}
+	if got.Content != "done" || got.ToolCalls != 1 || got.Usage.TotalTokens() != 16 {
+		t.Fatalf("response = %+v", got)
+	}
+	last := requests[1].Messages[len(requests[1].Messages)-1]
+	if last.Role != "tool" || last.ToolCallID != "call_9" || last.Content != "tool output" {
+		t.Fatalf("tool result message = %#v", last)
+	}
+}
@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFallbackAfter bounds each attempt but the last in a model chain,
+// leaving the fallback model time to answer inside the reply timeout.
+const DefaultFallbackAfter = 25 * time.Second
+
+// ModelRouteProvider looks up the model chain configured for a guild or
+// channel ID, primary first.
+type ModelRouteProvider interface {
+	GetModelRoute(ctx context.Context, scopeID string) ([]string, bool, error)
+}
+
+type RouterConfig struct {
+	// Models is the chain used where no route matches, primary first.
+	Models []string
+	// NewCompleter builds the client for one model. Every model in a chain
+	// should come from the same backend so they support the same calls.
+	NewCompleter func(model string) Completer
+	Routes       ModelRouteProvider
+	// FallbackAfter is how long a model may take before the next one in
+	// the chain is tried.
+	FallbackAfter time.Duration
+}
+
+// Router picks the models that answer a message: a channel route wins over
+// a guild route, which wins over the default chain. The default models
+// after the primary are appended to routed chains as fallbacks.
+type Router struct {
+	models        []string
+	newCompleter  func(model string) Completer
+	routes        ModelRouteProvider
+	fallbackAfter time.Duration
+
+	mu         sync.Mutex
+	completers map[string]Completer
+}
+
+type routedCompleter struct {
+	model     string
+	completer Completer
+}
+
+func NewRouter(cfg RouterConfig) *Router {
+	fallbackAfter := cfg.FallbackAfter
+	if fallbackAfter <= 0 {
+		fallbackAfter = DefaultFallbackAfter
+	}
+	return &Router{
+		models:        uniqueModels(cfg.Models),
+		newCompleter:  cfg.NewCompleter,
+		routes:        cfg.Routes,
+		fallbackAfter: fallbackAfter,
+		completers:    make(map[string]Completer),
+	}
+}
+
+// Default returns the completer for the primary default model, for callers
+// that need a completer before any message is routed.
+func (r *Router) Default() Completer {
+	if len(r.models) == 0 {
+		return nil
+	}
+	return r.completer(r.models[0])
+}
+
+func (r *Router) chain(ctx context.Context, message Message) ([]routedCompleter, error) {
+	models, err := r.modelsFor(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]routedCompleter, 0, len(models))
+	for _, model := range models {
+		if completer := r.completer(model); completer != nil {
+			out = append(out, routedCompleter{model: model, completer: completer})
+		}
+	}
+	return out, nil
+}
+
+func (r *Router) modelsFor(ctx context.Context, message Message) ([]string, error) {
+	if r.routes != nil {
+		for _, scopeID := range []string{message.ChannelID, message.GuildID} {
+			if strings.TrimSpace(scopeID) == "" {
+				continue
+			}
+			models, ok, err := r.routes.GetModelRoute(ctx, scopeID)
+			if err != nil {
+				return nil, fmt.Errorf("load model route: %w", err)
+			}
+			if ok && len(models) > 0 {
+				var fallbacks []string
+				if len(r.models) > 1 {
+					fallbacks = r.models[1:]
+				}
+				return uniqueModels(append(append([]string(nil), models...), fallbacks...)), nil
+			}
+		}
+	}
+	return r.models, nil
+}
+
+func (r *Router) completer(model string) Completer {
+	if r.newCompleter == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	completer, ok := r.completers[model]
+	if !ok {
+		completer = r.newCompleter(model)
+		r.completers[model] = completer
+	}
+	return completer
+}
+
+func uniqueModels(models []string) []string {
+	seen := make(map[string]bool, len(models))
+	out := make([]string, 0, len(models))
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model == "" || seen[model] {
+			continue
+		}
+		seen[model] = true
+		out = append(out, model)
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// modelCompleter answers with its model name, or fails with err.
+type modelCompleter struct {
+	model string
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (m *modelCompleter) Complete(ctx context.Context, _ CompletionRequest) (string, error) {
+	m.calls++
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if m.err != nil {
+		return "", m.err
+	}
+	return "from " + m.model, nil
+}
+
+type staticModelRoutes map[string][]string
+
+func (r staticModelRoutes) GetModelRoute(_ context.Context, scopeID string) ([]string, bool, error) {
+	models, ok := r[scopeID]
+	return models, ok, nil
+}
+
+func testRouter(completers map[string]*modelCompleter, cfg RouterConfig) *Router {
+	cfg.NewCompleter = func(model string) Completer {
+		if completer, ok := completers[model]; ok {
+			return completer
+		}
+		return &modelCompleter{model: model}
+	}
+	return NewRouter(cfg)
+}
+
+func TestRouterPrefersChannelThenGuildRoute(t *testing.T) {
+	router := testRouter(nil, RouterConfig{
+		Models: []string{"fast", "tiny"},
+		Routes: staticModelRoutes{"guild-1": {"heavy"}, "channel-1": {"medium", "fast"}},
+	})
+	cases := []struct {
+		message Message
+		want    []string
+	}{
+		{Message{GuildID: "guild-1", ChannelID: "channel-1"}, []string{"medium", "fast", "tiny"}},
+		{Message{GuildID: "guild-1", ChannelID: "channel-2"}, []string{"heavy", "tiny"}},
+		{Message{GuildID: "guild-2", ChannelID: "channel-3"}, []string{"fast", "tiny"}},
+	}
+	for _, tc := range cases {
+		got, err := router.modelsFor(context.Background(), tc.message)
+		if err != nil {
+			t.Fatalf("modelsFor: %v", err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("modelsFor(%+v) = %v, want %v", tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestServiceFallsBackWhenPrimaryModelFails(t *testing.T) {
+	heavy := &modelCompleter{model: "heavy", err: errors.New("model not loaded")}
+	router := testRouter(map[string]*modelCompleter{"heavy": heavy}, RouterConfig{
+		Models: []string{"fast", "tiny"},
+		Routes: staticModelRoutes{"guild-1": {"heavy"}},
+	})
+	service := NewService(router.Default())
+	service.SetRouter(router)
+
+	got, err := service.GenerateResponseWithMetrics(context.Background(), Message{GuildID: "guild-1", Content: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateResponseWithMetrics: %v", err)
+	}
+	if got.Content != "from tiny" || got.Model != "tiny" || heavy.calls != 1 {
+		t.Fatalf("response = %+v heavy calls = %d, want tiny to answer", got, heavy.calls)
+	}
+}
+
+func TestServiceFallsBackWhenPrimaryModelTimesOut(t *testing.T) {
+	slow := &modelCompleter{model: "slow", delay: time.Second}
+	router := testRouter(map[string]*modelCompleter{"slow": slow}, RouterConfig{
+		Models:        []string{"slow", "fast"},
+		FallbackAfter: 10 * time.Millisecond,
+	})
+	service := NewService(router.Default())
+	service.SetRouter(router)
+
+	got, err := service.GenerateResponseWithMetrics(context.Background(), Message{Content: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateResponseWithMetrics: %v", err)
+	}
+	if got.Model != "fast" {
+		t.Fatalf("Model = %q, want fast", got.Model)
+	}
+}
+
+func TestServiceReportsLastModelWhenChainFails(t *testing.T) {
+	failing := errors.New("down")
+	router := testRouter(map[string]*modelCompleter{
+		"fast": {model: "fast", err: failing},
+		"tiny": {model: "tiny", err: failing},
+	}, RouterConfig{Models: []string{"fast", "tiny"}})
+	service := NewService(router.Default())
+	service.SetRouter(router)
+
+	got, err := service.GenerateResponseWithMetrics(context.Background(), Message{Content: "hi"})
+	if !errors.Is(err, failing) || got.Model != "tiny" {
+		t.Fatalf("response = %+v err = %v, want tiny to fail last", got, err)
+	}
+}
+
+// failingChat runs one tool round and then fails.
+type failingChat struct {
+	modelCompleter
+	chats int
+}
+
+func (f *failingChat) Chat(context.Context, ChatRequest) (ChatResponse, error) {
+	f.chats++
+	if f.chats == 1 {
+		return ChatResponse{ToolCalls: []ChatToolCall{{Name: "test_tool", Arguments: json.RawMessage(`{}`)}}}, nil
+	}
+	return ChatResponse{}, errors.New("connection reset")
+}
+
+func TestServiceDoesNotFallBackAfterToolRan(t *testing.T) {
+	primary := &failingChat{modelCompleter: modelCompleter{model: "fast"}}
+	fallback := &modelCompleter{model: "tiny"}
+	router := NewRouter(RouterConfig{
+		Models: []string{"fast", "tiny"},
+		NewCompleter: func(model string) Completer {
+			if model == "fast" {
+				return primary
+			}
+			return fallback
+		},
+	})
+	toolRuns := 0
+	service := NewService(router.Default(), Tool{
+		Name:       "test_tool",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Execute: func(context.Context, ToolContext, json.RawMessage) (ToolResult, error) {
+			toolRuns++
+			return ToolResult{Content: "ok"}, nil
+		},
+	})
+	service.SetRouter(router)
+
+	got, err := service.GenerateResponseWithMetrics(context.Background(), Message{Content: "use a tool"})
+	if err == nil || got.Model != "fast" || toolRuns != 1 || fallback.calls != 0 {
+		t.Fatalf("response = %+v err = %v tool runs = %d fallback calls = %d", got, err, toolRuns, fallback.calls)
+	}
+}
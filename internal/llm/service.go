@@ -7,6 +7,8 @@ import (
 	"log"
 	"strings"
 	"time"
+
+	"mizubot-go/internal/metrics"
 )
 
 const maxToolIterations = 4
@@ -26,9 +28,10 @@ type Message struct {
 // HistoryMessage is a prior message in the conversation, provided as
 // additional context ahead of the current user message.
 type HistoryMessage struct {
-	Author  string
-	Content string
-	IsBot   bool
+	Author    string
+	Content   string
+	IsBot     bool
+	IsSummary bool // a condensed account of turns older than the rest
 }
 
 type CompletionRequest struct {
@@ -62,16 +65,25 @@ type ChatCompleter interface {
 	Chat(ctx context.Context, request ChatRequest) (ChatResponse, error)
 }
 
+// StreamingCompleter delivers its answer while it is generated. onDelta
+// receives each new piece of content; the returned response is the same
+// as the non-streaming call's.
+type StreamingCompleter interface {
+	CompleteStream(ctx context.Context, request CompletionRequest, onDelta func(string)) (CompletionResponse, error)
+	ChatStream(ctx context.Context, request ChatRequest, onDelta func(string)) (ChatResponse, error)
+}
+
 type ChatRequest struct {
 	Messages []ChatMessage
 	Tools    []ChatTool
 }
 
 type ChatMessage struct {
-	Role      string
-	Content   string
-	ToolName  string
-	ToolCalls []ChatToolCall
+	Role       string
+	Content    string
+	ToolName   string
+	ToolCallID string // the call a "tool" message answers, for backends that match by ID
+	ToolCalls  []ChatToolCall
 }
 
 type ChatTool struct {
@@ -81,6 +93,7 @@ type ChatTool struct {
 }
 
 type ChatToolCall struct {
+	ID        string // set by backends that identify calls, e.g. OpenAI-compatible ones
 	Name      string
 	Arguments json.RawMessage
 }
@@ -116,10 +129,20 @@ type GuildInstructionProvider interface {
 	GetGuildInstruction(ctx context.Context, guildID string) (string, bool, error)
 }
 
+// MemoryProvider returns what has been remembered about a user in a guild,
+// most relevant to text first.
+type MemoryProvider interface {
+	RelevantMemories(ctx context.Context, userID, guildID, text string) ([]string, error)
+}
+
 type Service struct {
 	completer                Completer
+	router                   *Router
 	tools                    map[string]Tool
 	guildInstructionProvider GuildInstructionProvider
+	memoryProvider           MemoryProvider
+	toolIndex                *toolIndex
+	budget                   *PromptBudget
 }
 
 type Response struct {
@@ -127,6 +150,8 @@ type Response struct {
 	Usage     Usage
 	LLMTurns  int64
 	ToolCalls int64
+	Model     string    // the routed model that answered, or the last one tried on error
+	Prompt    PromptFit // how the history was fitted for that model
 }
 
 func NewService(completer Completer, tools ...Tool) *Service {
@@ -152,12 +177,49 @@ func NewServiceWithGuildInstructionProvider(completer Completer, guildInstructio
 	}
 }
 
+// SetRouter routes each message to a model chain picked per guild or
+// channel. Without a router every message goes to the service's completer.
+func (s *Service) SetRouter(router *Router) {
+	s.router = router
+}
+
+// SetMemoryProvider adds the user's saved memories to every system prompt.
+func (s *Service) SetMemoryProvider(provider MemoryProvider) {
+	s.memoryProvider = provider
+}
+
+// SetEmbedder also offers a tool when the message is at least threshold
+// similar to its description, so requests that use none of its keywords
+// still get it. Keyword matching stays as the fallback, including when
+// embedding fails.
+func (s *Service) SetEmbedder(embedder Embedder, threshold float64) {
+	s.toolIndex = newToolIndex(embedder, threshold)
+}
+
 func (s *Service) GenerateResponse(ctx context.Context, message Message) (string, error) {
 	response, err := s.GenerateResponseWithMetrics(ctx, message)
 	return response.Content, err
 }
 
 func (s *Service) GenerateResponseWithMetrics(ctx context.Context, message Message) (Response, error) {
+	return s.GenerateResponseStream(ctx, message, nil)
+}
+
+// Streams reports whether GenerateResponseStream can report partial text.
+func (s *Service) Streams() bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.completer.(StreamingCompleter)
+	return ok
+}
+
+// GenerateResponseStream is GenerateResponseWithMetrics that also calls
+// onText with the reply written so far while it streams in, if the
+// completer supports streaming. onText gets "" when text already reported
+// turns out to precede a tool call rather than be the reply, or came from a
+// model that failed before the next one in the route takes over.
+func (s *Service) GenerateResponseStream(ctx context.Context, message Message, onText func(string)) (Response, error) {
 	if s == nil || s.completer == nil {
 		return Response{}, nil
 	}
@@ -165,13 +227,62 @@ func (s *Service) GenerateResponseWithMetrics(ctx context.Context, message Messa
 	if message.Content == "" {
 		return Response{}, nil
 	}
-	tools := s.toolsForMessage(message)
+	start := time.Now()
+	response, err := s.generateWithFallback(ctx, message, newTextStream(s.completer, onText))
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.LLMResponseSeconds.ObserveSince(start, outcome)
+	metrics.LLMTokens.Add(float64(response.Usage.PromptTokens), "prompt")
+	metrics.LLMTokens.Add(float64(response.Usage.CompletionTokens), "completion")
+	return response, err
+}
+
+// generateWithFallback answers with the first model in the message's route
+// that succeeds. It stops falling back once a tool has run, since a retry
+// would repeat the tool's side effects, or once ctx itself is done.
+func (s *Service) generateWithFallback(ctx context.Context, message Message, stream *textStream) (Response, error) {
+	attempts := []routedCompleter{{completer: s.completer}}
+	if s.router != nil {
+		chain, err := s.router.chain(ctx, message)
+		if err != nil {
+			return Response{}, err
+		}
+		if len(chain) > 0 {
+			attempts = chain
+		}
+	}
+	tools := s.toolsForMessage(ctx, message)
+	summaries := historySummaries{}
+	var response Response
+	var err error
+	for i, attempt := range attempts {
+		last := i == len(attempts)-1
+		fitted, fit := s.fitHistory(ctx, attempt, message, tools, summaries)
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if !last {
+			attemptCtx, cancel = context.WithTimeout(ctx, s.router.fallbackAfter)
+		}
+		response, err = s.generateResponse(attemptCtx, attempt.completer, fitted, tools, stream)
+		cancel()
+		response.Model = attempt.model
+		response.Prompt = fit
+		if err == nil || last || ctx.Err() != nil || response.ToolCalls > 0 {
+			return response, err
+		}
+		log.Printf("llm model failed, falling back: model=%s next=%s channel_id=%s error=%v", attempt.model, attempts[i+1].model, message.ChannelID, err)
+	}
+	return response, err
+}
+
+func (s *Service) generateResponse(ctx context.Context, completer Completer, message Message, tools map[string]Tool, stream *textStream) (Response, error) {
 	if len(tools) == 0 {
 		systemPrompt, err := s.buildSystemPrompt(ctx, message)
 		if err != nil {
 			return Response{}, err
 		}
-		result, err := completeWithMetrics(ctx, s.completer, CompletionRequest{
+		result, err := complete(ctx, completer, stream, CompletionRequest{
 			SystemPrompt: systemPrompt,
 			UserPrompt:   buildUserPromptWithHistory(message),
 		})
@@ -181,7 +292,7 @@ func (s *Service) GenerateResponseWithMetrics(ctx context.Context, message Messa
 		result.LLMTurns = 1
 		return result, nil
 	}
-	return s.generateWithTools(ctx, message, tools)
+	return s.generateWithTools(ctx, completer, message, tools, stream)
 }
 
 type toolDecision struct {
@@ -200,16 +311,16 @@ type toolExecutionResult struct {
 	Error  string `json:"error,omitempty"`
 }
 
-func (s *Service) generateWithTools(ctx context.Context, message Message, tools map[string]Tool) (Response, error) {
-	if chatCompleter, ok := s.completer.(ChatCompleter); ok {
-		return s.generateWithNativeTools(ctx, chatCompleter, message, tools)
+func (s *Service) generateWithTools(ctx context.Context, completer Completer, message Message, tools map[string]Tool, stream *textStream) (Response, error) {
+	if chatCompleter, ok := completer.(ChatCompleter); ok {
+		return s.generateWithNativeTools(ctx, chatCompleter, message, tools, stream)
 	}
 
 	systemPrompt, err := s.buildToolSystemPrompt(ctx, message, tools)
 	if err != nil {
 		return Response{}, err
 	}
-	decisionResponse, err := completeWithMetrics(ctx, s.completer, CompletionRequest{
+	decisionResponse, err := completeWithMetrics(ctx, completer, CompletionRequest{
 		SystemPrompt: systemPrompt,
 		UserPrompt:   buildToolDecisionPrompt(message),
 	})
@@ -258,12 +369,12 @@ func (s *Service) generateWithTools(ctx context.Context, message Message, tools
 	if err != nil {
 		return Response{}, err
 	}
-	finalResponse, err := completeWithMetrics(ctx, s.completer, CompletionRequest{
+	finalResponse, err := complete(ctx, completer, stream, CompletionRequest{
 		SystemPrompt: systemPrompt,
 		UserPrompt:   buildToolResultPrompt(message, string(resultJSON)),
 	})
 	if err != nil {
-		return Response{}, err
+		return Response{ToolCalls: toolCalls}, err
 	}
 	finalResponse.Usage = addUsage(usage, finalResponse.Usage)
 	finalResponse.LLMTurns = decisionResponse.LLMTurns + 1
@@ -271,7 +382,7 @@ func (s *Service) generateWithTools(ctx context.Context, message Message, tools
 	return finalResponse, nil
 }
 
-func (s *Service) generateWithNativeTools(ctx context.Context, chatCompleter ChatCompleter, message Message, tools map[string]Tool) (Response, error) {
+func (s *Service) generateWithNativeTools(ctx context.Context, chatCompleter ChatCompleter, message Message, tools map[string]Tool, stream *textStream) (Response, error) {
 	selectedChatTools := chatTools(tools)
 	systemPrompt, err := s.buildSystemPrompt(ctx, message)
 	if err != nil {
@@ -293,9 +404,9 @@ func (s *Service) generateWithNativeTools(ctx context.Context, chatCompleter Cha
 		GuildID:   message.GuildID,
 	}
 	for range maxToolIterations {
-		response, err := chatCompleter.Chat(ctx, ChatRequest{Messages: messages, Tools: selectedChatTools})
+		response, err := chat(ctx, chatCompleter, stream, ChatRequest{Messages: messages, Tools: selectedChatTools})
 		if err != nil {
-			return Response{}, err
+			return Response{ToolCalls: toolCalls}, err
 		}
 		llmTurns++
 		usage = addUsage(usage, response.Usage)
@@ -312,9 +423,10 @@ func (s *Service) generateWithNativeTools(ctx context.Context, chatCompleter Cha
 		for _, call := range response.ToolCalls {
 			result := executeToolCall(ctx, tools, toolCtx, call)
 			messages = append(messages, ChatMessage{
-				Role:     "tool",
-				ToolName: call.Name,
-				Content:  result,
+				Role:       "tool",
+				ToolName:   call.Name,
+				ToolCallID: call.ID,
+				Content:    result,
 			})
 		}
 	}
@@ -323,9 +435,9 @@ func (s *Service) generateWithNativeTools(ctx context.Context, chatCompleter Cha
 		Role:    "system",
 		Content: "Tool call limit reached. Write the final response using the tool results already available. Do not call more tools.",
 	})
-	final, err := chatCompleter.Chat(ctx, ChatRequest{Messages: messages})
+	final, err := chat(ctx, chatCompleter, stream, ChatRequest{Messages: messages})
 	if err != nil {
-		return Response{}, err
+		return Response{ToolCalls: toolCalls}, err
 	}
 	llmTurns++
 	usage = addUsage(usage, final.Usage)
@@ -345,6 +457,58 @@ func executeToolCall(ctx context.Context, tools map[string]Tool, toolCtx ToolCon
 	return result.Content
 }
 
+// textStream reports the text of the model turn in progress as deltas
+// arrive. A nil *textStream means the reply isn't streamed.
+type textStream struct {
+	onText func(string)
+	text   strings.Builder
+}
+
+func newTextStream(completer Completer, onText func(string)) *textStream {
+	if _, ok := completer.(StreamingCompleter); !ok || onText == nil {
+		return nil
+	}
+	return &textStream{onText: onText}
+}
+
+func (t *textStream) delta(d string) {
+	t.text.WriteString(d)
+	t.onText(t.text.String())
+}
+
+// restart begins a new model turn, withdrawing text the previous turn
+// streamed before it turned into tool calls or its model failed.
+func (t *textStream) restart() {
+	if t.text.Len() > 0 {
+		t.text.Reset()
+		t.onText("")
+	}
+}
+
+// complete runs a plain completion, streaming it when stream is set.
+func complete(ctx context.Context, completer Completer, stream *textStream, request CompletionRequest) (Response, error) {
+	streaming, ok := completer.(StreamingCompleter)
+	if stream == nil || !ok {
+		return completeWithMetrics(ctx, completer, request)
+	}
+	stream.restart()
+	response, err := streaming.CompleteStream(ctx, request, stream.delta)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Content: strings.TrimSpace(response.Content), Usage: response.Usage}, nil
+}
+
+// chat runs one chat turn, streaming it when stream is set.
+func chat(ctx context.Context, chatCompleter ChatCompleter, stream *textStream, request ChatRequest) (ChatResponse, error) {
+	streaming, ok := chatCompleter.(StreamingCompleter)
+	if stream == nil || !ok {
+		return chatCompleter.Chat(ctx, request)
+	}
+	stream.restart()
+	return streaming.ChatStream(ctx, request, stream.delta)
+}
+
 func completeWithMetrics(ctx context.Context, completer Completer, request CompletionRequest) (Response, error) {
 	if metricsCompleter, ok := completer.(MetricsCompleter); ok {
 		response, err := metricsCompleter.CompleteWithMetrics(ctx, request)
@@ -370,6 +534,10 @@ func historyChatMessages(history []HistoryMessage) []ChatMessage {
 	}
 	out := make([]ChatMessage, 0, len(history))
 	for _, h := range history {
+		if h.IsSummary {
+			out = append(out, ChatMessage{Role: "system", Content: historySummaryLabel + ": " + h.Content})
+			continue
+		}
 		if h.IsBot {
 			out = append(out, ChatMessage{Role: "assistant", Content: h.Content})
 			continue
@@ -379,6 +547,41 @@ func historyChatMessages(history []HistoryMessage) []ChatMessage {
 	return out
 }
 
+const historySummaryLabel = "Summary of earlier messages in this thread"
+
+// SummarizeHistory condenses turns into a short summary for use as history
+// in later requests. A previous summary of even older turns is folded in,
+// so a long thread can be summarized a few turns at a time.
+func (s *Service) SummarizeHistory(ctx context.Context, previous string, turns []HistoryMessage) (string, error) {
+	if s == nil || s.completer == nil {
+		return "", nil
+	}
+	return summarizeHistory(ctx, s.completer, previous, turns)
+}
+
+func summarizeHistory(ctx context.Context, completer Completer, previous string, turns []HistoryMessage) (string, error) {
+	var b strings.Builder
+	if previous = strings.TrimSpace(previous); previous != "" {
+		b.WriteString("Summary so far:\n")
+		b.WriteString(previous)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(buildHistoryBlock(turns, ""))
+	b.WriteString("\nWrite the updated summary.")
+	response, err := completeWithMetrics(ctx, completer, CompletionRequest{
+		SystemPrompt: `You condense Discord conversations. Summarize the conversation below in at most 120 words of plain prose.
+Keep names, decisions, open questions, numbers, dates and anything the participants asked the bot to do. Drop greetings and small talk.
+If a summary so far is given, merge the new messages into it; the result replaces it. Reply with the summary only.`,
+		UserPrompt: b.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+	metrics.LLMTokens.Add(float64(response.Usage.PromptTokens), "prompt")
+	metrics.LLMTokens.Add(float64(response.Usage.CompletionTokens), "completion")
+	return response.Content, nil
+}
+
 func historySpeakerLabel(author string) string {
 	author = strings.TrimSpace(author)
 	if author == "" {
@@ -394,14 +597,22 @@ func addUsage(a, b Usage) Usage {
 	}
 }
 
-func (s *Service) toolsForMessage(message Message) map[string]Tool {
+func (s *Service) toolsForMessage(ctx context.Context, message Message) map[string]Tool {
 	if len(s.tools) == 0 {
 		return nil
 	}
+	var similar map[string]bool
+	if s.toolIndex != nil {
+		var err error
+		similar, err = s.toolIndex.similar(ctx, s.tools, message.Content)
+		if err != nil {
+			log.Printf("semantic tool selection failed, using keywords: channel_id=%s error=%v", message.ChannelID, err)
+		}
+	}
 	normalized := normalizeToolMatchText(message.Content)
 	out := make(map[string]Tool)
 	for name, tool := range s.tools {
-		if len(tool.Keywords) == 0 || matchesAnyKeyword(normalized, tool.Keywords) {
+		if len(tool.Keywords) == 0 || similar[name] || matchesAnyKeyword(normalized, tool.Keywords) {
 			out[name] = tool
 		}
 	}
@@ -453,21 +664,25 @@ func parseToolDecision(raw string) (toolDecision, bool) {
 
 func (s *Service) buildSystemPrompt(ctx context.Context, message Message) (string, error) {
 	prompt := buildSystemPrompt(message.BotName)
-	if s.guildInstructionProvider == nil {
-		return prompt, nil
-	}
-	instruction, ok, err := s.guildInstructionProvider.GetGuildInstruction(ctx, message.GuildID)
-	if err != nil {
-		return "", fmt.Errorf("load guild instructions: %w", err)
-	}
-	if !ok {
-		return prompt, nil
+	if s.guildInstructionProvider != nil {
+		instruction, ok, err := s.guildInstructionProvider.GetGuildInstruction(ctx, message.GuildID)
+		if err != nil {
+			return "", fmt.Errorf("load guild instructions: %w", err)
+		}
+		if instruction = strings.TrimSpace(instruction); ok && instruction != "" {
+			prompt += "\n\nServer-specific instructions:\n" + instruction
+		}
 	}
-	instruction = strings.TrimSpace(instruction)
-	if instruction == "" {
-		return prompt, nil
+	if s.memoryProvider != nil && message.UserID != "" {
+		memories, err := s.memoryProvider.RelevantMemories(ctx, message.UserID, message.GuildID, message.Content)
+		if err != nil {
+			return "", fmt.Errorf("load memories: %w", err)
+		}
+		if len(memories) > 0 {
+			prompt += "\n\nThings you remember about the user you are replying to (saved earlier with memory_save):\n- " + strings.Join(memories, "\n- ")
+		}
 	}
-	return prompt + "\n\nServer-specific instructions:\n" + instruction, nil
+	return prompt, nil
 }
 
 func (s *Service) buildToolSystemPrompt(ctx context.Context, message Message, tools map[string]Tool) (string, error) {
@@ -543,5 +758,6 @@ func buildToolResponseStylePrompt() string {
 - For created reminders, include the reminder ID, message, next run, channel, and timezone.
 - When creating reminders, infer a concise reminder message from the user's intent instead of copying the whole command. For example, "remind me to take meds tomorrow" should create message "take meds". Preserve exact text only when the user quotes it or explicitly asks for that exact wording.
 - For reminder_create, use once=true with run_at for one-time reminders. Use once=false with cron_expr for repeated reminders. Do not pass slash-command style schedule/at fields.
+- To change an existing reminder, use reminder_update with its ID instead of deleting and recreating it. Pass only the fields that change.
 - Prefer clear Discord-friendly formatting with short bullets for multiple reminders.`
 }
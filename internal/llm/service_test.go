@@ -256,6 +256,30 @@ func TestServiceGenerateResponseAddsMatchingGuildInstructions(t *testing.T) {
 	}
 }
 
+type staticMemories map[string][]string
+
+func (m staticMemories) RelevantMemories(_ context.Context, userID, _ string, _ string) ([]string, error) {
+	return m[userID], nil
+}
+
+func TestServiceGenerateResponseAddsUserMemories(t *testing.T) {
+	completer := &fakeCompleter{responses: []string{"answer", "answer"}}
+	service := NewService(completer)
+	service.SetMemoryProvider(staticMemories{"user-1": {"prefers metric units", "working on project X"}})
+
+	for _, userID := range []string{"user-1", "user-2"} {
+		if _, err := service.GenerateResponse(context.Background(), Message{UserID: userID, Content: "hello"}); err != nil {
+			t.Fatalf("GenerateResponse: %v", err)
+		}
+	}
+	if !strings.Contains(completer.requests[0].SystemPrompt, "- prefers metric units\n- working on project X") {
+		t.Fatalf("system prompt missing memories: %q", completer.requests[0].SystemPrompt)
+	}
+	if strings.Contains(completer.requests[1].SystemPrompt, "remember about the user") {
+		t.Fatalf("system prompt for a user without memories has a memory section: %q", completer.requests[1].SystemPrompt)
+	}
+}
+
 func TestServiceUsesServerBotNameInSystemPrompt(t *testing.T) {
 	completer := &fakeCompleter{responses: []string{"answer"}}
 	service := NewService(completer)
@@ -317,6 +341,40 @@ func TestServiceGenerateResponseWithoutHistoryOmitsHistorySection(t *testing.T)
 	}
 }
 
+func TestHistorySummaryIsLabelledInPromptsAndChat(t *testing.T) {
+	history := []HistoryMessage{
+		{Content: "Alice and Bob planned a trip to Kyoto in May.", IsSummary: true},
+		{Author: "Alice", Content: "which hotel again?"},
+	}
+	block := buildHistoryBlock(history, "Mizu")
+	if !strings.Contains(block, historySummaryLabel+": Alice and Bob planned") {
+		t.Fatalf("history block missing summary: %q", block)
+	}
+	chat := historyChatMessages(history)
+	if chat[0].Role != "system" || !strings.HasPrefix(chat[0].Content, historySummaryLabel) {
+		t.Fatalf("summary chat message = %+v, want a system message", chat[0])
+	}
+}
+
+func TestServiceSummarizeHistoryFoldsInPreviousSummary(t *testing.T) {
+	completer := &fakeCompleter{responses: []string{" Trip to Kyoto in May; hotel undecided. "}}
+	service := NewService(completer)
+
+	got, err := service.SummarizeHistory(context.Background(), "Trip to Kyoto planned.", []HistoryMessage{
+		{Author: "Alice", Content: "let's go in May"},
+	})
+	if err != nil {
+		t.Fatalf("SummarizeHistory: %v", err)
+	}
+	if got != "Trip to Kyoto in May; hotel undecided." {
+		t.Fatalf("summary = %q", got)
+	}
+	prompt := completer.requests[0].UserPrompt
+	if !strings.Contains(prompt, "Summary so far:\nTrip to Kyoto planned.") || !strings.Contains(prompt, "Alice: let's go in May") {
+		t.Fatalf("summarize prompt = %q", prompt)
+	}
+}
+
 func TestServiceGenerateWithNativeToolsIncludesHistoryAsChatMessages(t *testing.T) {
 	completer := &fakeCompleter{chat: []ChatResponse{{Content: "final answer"}}}
 	service := NewService(completer, Tool{
@@ -382,3 +440,76 @@ func TestServiceGenerateResponseSkipsOtherGuildInstructions(t *testing.T) {
 		t.Fatalf("system prompt included instruction for another guild: %q", completer.requests[0].SystemPrompt)
 	}
 }
+
+// fakeStreamingCompleter streams each canned response word by word.
+type fakeStreamingCompleter struct {
+	fakeCompleter
+}
+
+func (f *fakeStreamingCompleter) CompleteStream(ctx context.Context, request CompletionRequest, onDelta func(string)) (CompletionResponse, error) {
+	content, err := f.Complete(ctx, request)
+	streamWords(content, onDelta)
+	return CompletionResponse{Content: content}, err
+}
+
+func (f *fakeStreamingCompleter) ChatStream(ctx context.Context, request ChatRequest, onDelta func(string)) (ChatResponse, error) {
+	response, err := f.Chat(ctx, request)
+	streamWords(response.Content, onDelta)
+	return response, err
+}
+
+func streamWords(content string, onDelta func(string)) {
+	for idx, word := range strings.Fields(content) {
+		if idx > 0 {
+			word = " " + word
+		}
+		onDelta(word)
+	}
+}
+
+func TestServiceGenerateResponseStreamReportsTextSoFar(t *testing.T) {
+	completer := &fakeStreamingCompleter{fakeCompleter{responses: []string{"hello there friend"}}}
+	service := NewService(completer)
+	if !service.Streams() || NewService(&fakeCompleter{}).Streams() {
+		t.Fatalf("Streams should only be true for a streaming completer")
+	}
+
+	var seen []string
+	got, err := service.GenerateResponseStream(context.Background(), Message{Content: "hi"}, func(text string) {
+		seen = append(seen, text)
+	})
+	if err != nil {
+		t.Fatalf("GenerateResponseStream: %v", err)
+	}
+	want := []string{"hello", "hello there", "hello there friend"}
+	if got.Content != "hello there friend" || strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Fatalf("content=%q seen=%q, want %q", got.Content, seen, want)
+	}
+}
+
+func TestServiceGenerateResponseStreamWithdrawsTextBeforeToolCall(t *testing.T) {
+	completer := &fakeStreamingCompleter{fakeCompleter{chat: []ChatResponse{
+		{Content: "let me check", ToolCalls: []ChatToolCall{{Name: "test_tool", Arguments: json.RawMessage(`{}`)}}},
+		{Content: "all done"},
+	}}}
+	tool := Tool{
+		Name:       "test_tool",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Execute: func(context.Context, ToolContext, json.RawMessage) (ToolResult, error) {
+			return ToolResult{Content: "ok"}, nil
+		},
+	}
+	service := NewService(completer, tool)
+
+	var seen []string
+	got, err := service.GenerateResponseStream(context.Background(), Message{Content: "use a tool"}, func(text string) {
+		seen = append(seen, text)
+	})
+	if err != nil {
+		t.Fatalf("GenerateResponseStream: %v", err)
+	}
+	want := []string{"let", "let me", "let me check", "", "all", "all done"}
+	if got.Content != "all done" || strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Fatalf("content=%q seen=%q, want %q", got.Content, seen, want)
+	}
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mizubot-go/internal/llm"
+	"mizubot-go/internal/memories"
+)
+
+var memoryToolKeywords = []string{"remember", "memory", "memories", "forget", "recall", "prefer", "about me", "my name", "i'm"}
+
+func NewMemoryTools(store *memories.Store) []llm.Tool {
+	if store == nil {
+		return nil
+	}
+	return []llm.Tool{
+		{
+			Name:        "memory_save",
+			Description: "Remember a lasting fact about the current Discord user, such as a preference or an ongoing project, so it is available in later conversations. Save short third-person facts like 'prefers metric units'. Do not save secrets, passwords, or one-off requests.",
+			Parameters:  json.RawMessage(`{"type":"object","required":["content"],"properties":{"content":{"type":"string","description":"The fact to remember, in a short third-person sentence."},"scope":{"type":"string","enum":["user","server"],"description":"user (default) remembers it everywhere; server only in the current server."}},"additionalProperties":false}`),
+			Keywords:    memoryToolKeywords,
+			Execute:     saveMemory(store),
+		},
+		{
+			Name:        "memory_search",
+			Description: "Search what has been remembered about the current Discord user. Returns matching memories with their IDs.",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"query":{"type":"string","description":"Words to look for. Leave empty to list the newest memories."}},"additionalProperties":false}`),
+			Keywords:    memoryToolKeywords,
+			Execute:     searchMemories(store),
+		},
+		{
+			Name:        "memory_forget",
+			Description: "Forget one of the current Discord user's memories by ID. Use memory_search first to find the ID.",
+			Parameters:  json.RawMessage(`{"type":"object","required":["id"],"properties":{"id":{"type":"integer","description":"Memory ID to forget."}},"additionalProperties":false}`),
+			Keywords:    memoryToolKeywords,
+			Execute:     forgetMemory(store),
+		},
+	}
+}
+
+type memorySaveArgs struct {
+	Content string `json:"content"`
+	Scope   string `json:"scope"`
+}
+
+func saveMemory(store *memories.Store) llm.ToolHandler {
+	return func(ctx context.Context, toolCtx llm.ToolContext, raw json.RawMessage) (llm.ToolResult, error) {
+		var args memorySaveArgs
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return llm.ToolResult{}, fmt.Errorf("invalid memory arguments: %w", err)
+		}
+		guildID := ""
+		if strings.TrimSpace(args.Scope) == "server" {
+			guildID = toolCtx.GuildID
+		}
+		memory, err := store.Save(ctx, toolCtx.UserID, guildID, args.Content)
+		if err != nil {
+			return llm.ToolResult{}, err
+		}
+		return llm.ToolResult{Content: fmt.Sprintf("Saved memory ID %d: %s", memory.ID, memory.Content)}, nil
+	}
+}
+
+type memorySearchArgs struct {
+	Query string `json:"query"`
+}
+
+func searchMemories(store *memories.Store) llm.ToolHandler {
+	return func(ctx context.Context, toolCtx llm.ToolContext, raw json.RawMessage) (llm.ToolResult, error) {
+		var args memorySearchArgs
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return llm.ToolResult{}, fmt.Errorf("invalid memory search arguments: %w", err)
+			}
+		}
+		list, err := store.Search(ctx, toolCtx.UserID, toolCtx.GuildID, args.Query, 10)
+		if err != nil {
+			return llm.ToolResult{}, err
+		}
+		if len(list) == 0 {
+			return llm.ToolResult{Content: "No matching memories."}, nil
+		}
+		var b strings.Builder
+		for _, memory := range list {
+			fmt.Fprintf(&b, "Memory ID %d: %s\n", memory.ID, memory.Content)
+		}
+		return llm.ToolResult{Content: strings.TrimSpace(b.String())}, nil
+	}
+}
+
+type memoryForgetArgs struct {
+	ID int64 `json:"id"`
+}
+
+func forgetMemory(store *memories.Store) llm.ToolHandler {
+	return func(ctx context.Context, toolCtx llm.ToolContext, raw json.RawMessage) (llm.ToolResult, error) {
+		var args memoryForgetArgs
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return llm.ToolResult{}, fmt.Errorf("invalid memory forget arguments: %w", err)
+		}
+		if args.ID <= 0 {
+			return llm.ToolResult{}, fmt.Errorf("id must be positive")
+		}
+		ok, err := store.Forget(ctx, toolCtx.UserID, args.ID)
+		if err != nil {
+			return llm.ToolResult{}, err
+		}
+		if !ok {
+			return llm.ToolResult{Content: fmt.Sprintf("Memory ID %d was not found for this user.", args.ID)}, nil
+		}
+		return llm.ToolResult{Content: fmt.Sprintf("Forgot memory ID %d.", args.ID)}, nil
+	}
+}
@@ -33,10 +33,17 @@ func NewReminderTools(service *reminders.Service, settingsService ...*usersettin
 		{
 			Name:        "reminder_create",
 			Description: "Create a reminder for the current Discord user. Infer a concise reminder message from the user's request unless they explicitly provide exact reminder text. LLM callers must provide normalized scheduling: cron_expr for repeated reminders, or once=true plus run_at for one-time reminders.",
-			Parameters:  json.RawMessage(`{"type":"object","required":["message","once"],"properties":{"message":{"type":"string","description":"Concise reminder text to send later. Infer the actual thing to remember, not the full user command. For example, 'remind me to take meds tomorrow' should use 'take meds'. If the user quotes or explicitly states exact reminder text, preserve it."},"once":{"type":"boolean","description":"true for a one-time reminder; false for a repeated reminder."},"run_at":{"type":"string","description":"Required when once=true. Use a duration like 10m, 2h, 3d, RFC3339, or YYYY-MM-DD HH:MM in the selected timezone."},"cron_expr":{"type":"string","description":"Required when once=false. Five-field cron expression in the selected timezone."},"timezone":{"type":"string","description":"Optional IANA timezone name. Defaults to the user's configured timezone, then UTC."},"channel_id":{"type":"string","description":"Discord channel ID. Optional; defaults to the current channel."}},"additionalProperties":false}`),
+			Parameters:  json.RawMessage(`{"type":"object","required":["message","once"],"properties":{"message":{"type":"string","description":"Concise reminder text to send later. Infer the actual thing to remember, not the full user command. For example, 'remind me to take meds tomorrow' should use 'take meds'. If the user quotes or explicitly states exact reminder text, preserve it. May use {{date}}, {{weekday}}, {{count}} (occurrence number), {{days_until \"YYYY-MM-DD\"}}, and {{user}}, filled in at delivery time."},"once":{"type":"boolean","description":"true for a one-time reminder; false for a repeated reminder."},"run_at":{"type":"string","description":"Required when once=true. Use a duration like 10m, 2h, 3d, RFC3339, or YYYY-MM-DD HH:MM in the selected timezone."},"cron_expr":{"type":"string","description":"Required when once=false. Five-field cron expression in the selected timezone."},"timezone":{"type":"string","description":"Optional IANA timezone name. Defaults to the user's configured timezone, then UTC."},"channel_id":{"type":"string","description":"Discord channel ID. Optional; defaults to the current channel."},"catch_up":{"type":"string","enum":["once","each","skip"],"description":"Optional, repeated reminders only. What to do with runs missed while the bot was offline: send once (default), send each missed run, or skip them."},"ends_at":{"type":"string","description":"Optional, repeated reminders only. Stop after this time: a duration like 30d, a date like 2026-12-31 (inclusive), or YYYY-MM-DD HH:MM in the selected timezone."},"remaining_runs":{"type":"integer","minimum":1,"description":"Optional, repeated reminders only. Stop after this many deliveries."},"delivery":{"type":"string","enum":["channel","dm","fallback"],"description":"Optional. Where to send the reminder: the channel, a DM, or the channel with a DM if posting fails. Defaults to the user's configured choice."}},"additionalProperties":false}`),
 			Keywords:    reminderToolKeywords,
 			Execute:     createReminder(service, settings),
 		},
+		{
+			Name:        "reminder_update",
+			Description: "Change one of the current Discord user's reminders by ID, keeping the ID. Only pass the fields that should change. To reschedule, pass either run_at for a one-time reminder or cron_expr for a repeated one.",
+			Parameters:  json.RawMessage(`{"type":"object","required":["id"],"properties":{"id":{"type":"integer","description":"Reminder ID to change."},"message":{"type":"string","description":"New reminder text."},"run_at":{"type":"string","description":"Makes the reminder one-time at this time. Use a duration like 10m, 2h, 3d, RFC3339, or YYYY-MM-DD HH:MM in the selected timezone."},"cron_expr":{"type":"string","description":"Makes the reminder repeat on this five-field cron expression in the selected timezone."},"timezone":{"type":"string","description":"New IANA timezone name for the schedule."},"channel_id":{"type":"string","description":"New Discord channel ID in the server the reminder was made in."}},"additionalProperties":false}`),
+			Keywords:    reminderToolKeywords,
+			Execute:     updateReminder(service),
+		},
 		{
 			Name:        "reminder_delete",
 			Description: "Delete one of the current Discord user's reminders by ID.",
@@ -67,6 +74,12 @@ func listReminders(service *reminders.Service) llm.ToolHandler {
 				reminder.Timezone,
 				humanSchedule(reminder),
 			)
+			if reminder.HasEndCondition() {
+				fmt.Fprintf(&b, "Ends: %s\n\n", humanEndCondition(reminder))
+			}
+			if reminder.Dead() {
+				fmt.Fprintf(&b, "Status: delivery failed, no longer retrying: %s\n\n", reminder.LastError)
+			}
 		}
 		return llm.ToolResult{Content: strings.TrimSpace(b.String())}, nil
 	}
@@ -79,6 +92,11 @@ type reminderCreateArgs struct {
 	CronExpr  string `json:"cron_expr"`
 	Timezone  string `json:"timezone"`
 	ChannelID string `json:"channel_id"`
+	CatchUp   string `json:"catch_up"`
+
+	EndsAt        string `json:"ends_at"`
+	RemainingRuns int64  `json:"remaining_runs"`
+	Delivery      string `json:"delivery"`
 }
 
 func createReminder(service *reminders.Service, settingsService *usersettings.Service) llm.ToolHandler {
@@ -95,6 +113,10 @@ func createReminder(service *reminders.Service, settingsService *usersettings.Se
 		if timezone == "" {
 			timezone = userTimezone(ctx, settingsService, toolCtx.UserID)
 		}
+		delivery := strings.TrimSpace(args.Delivery)
+		if delivery == "" {
+			delivery = string(userDeliveryMode(ctx, settingsService, toolCtx.UserID))
+		}
 		schedule := string(reminders.ScheduleCron)
 		at := strings.TrimSpace(args.CronExpr)
 		if args.Once {
@@ -115,18 +137,30 @@ func createReminder(service *reminders.Service, settingsService *usersettings.Se
 			At:        at,
 			CronExpr:  args.CronExpr,
 			Timezone:  timezone,
+			CatchUp:   args.CatchUp,
+
+			EndsAt:        args.EndsAt,
+			RemainingRuns: args.RemainingRuns,
+			DeliveryMode:  delivery,
 		})
 		if err != nil {
 			return llm.ToolResult{}, err
 		}
-		return llm.ToolResult{Content: fmt.Sprintf("Created reminder ID %d.\nMessage: %s\nNext run: %s\nChannel: %s\nTimezone: %s\nRepeat: %s",
+		content := fmt.Sprintf("Created reminder ID %d.\nMessage: %s\nNext run: %s\nChannel: %s\nTimezone: %s\nRepeat: %s",
 			created.ID,
 			created.Message,
 			discordTimestamp(created.NextRun),
 			created.ChannelID,
 			created.Timezone,
 			humanSchedule(*created),
-		)}, nil
+		)
+		if created.DeliveryMode != reminders.DeliveryChannel {
+			content += "\nDelivery: " + string(created.DeliveryMode)
+		}
+		if created.HasEndCondition() {
+			content += "\nEnds: " + humanEndCondition(*created)
+		}
+		return llm.ToolResult{Content: content}, nil
 	}
 }
 
@@ -139,6 +173,12 @@ func humanSchedule(reminder reminders.Reminder) string {
 		return "hourly"
 	case reminders.ScheduleDaily:
 		return "daily"
+	case reminders.ScheduleWeekly:
+		return "weekly"
+	case reminders.ScheduleMonthly:
+		return "monthly"
+	case reminders.ScheduleInterval:
+		return "every " + reminder.AtTime.String
 	case reminders.ScheduleCron:
 		return "custom recurring schedule"
 	default:
@@ -146,6 +186,17 @@ func humanSchedule(reminder reminders.Reminder) string {
 	}
 }
 
+func humanEndCondition(reminder reminders.Reminder) string {
+	var parts []string
+	if !reminder.EndsAt.IsZero() {
+		parts = append(parts, "after "+discordTimestamp(reminder.EndsAt))
+	}
+	if reminder.RemainingRuns.Valid {
+		parts = append(parts, fmt.Sprintf("after %d more runs", reminder.RemainingRuns.Int64))
+	}
+	return strings.Join(parts, " or ")
+}
+
 func userTimezone(ctx context.Context, settingsService *usersettings.Service, userID string) string {
 	if settingsService == nil {
 		return usersettings.DefaultTimezone
@@ -162,6 +213,67 @@ func discordTimestamp(t time.Time) string {
 	return fmt.Sprintf("<t:%d:F> (<t:%d:R>)", unix, unix)
 }
 
+type reminderUpdateArgs struct {
+	ID        int64   `json:"id"`
+	Message   *string `json:"message"`
+	RunAt     *string `json:"run_at"`
+	CronExpr  *string `json:"cron_expr"`
+	Timezone  *string `json:"timezone"`
+	ChannelID *string `json:"channel_id"`
+}
+
+func updateReminder(service *reminders.Service) llm.ToolHandler {
+	return func(ctx context.Context, toolCtx llm.ToolContext, raw json.RawMessage) (llm.ToolResult, error) {
+		var args reminderUpdateArgs
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return llm.ToolResult{}, fmt.Errorf("invalid update reminder arguments: %w", err)
+		}
+		if args.ID <= 0 {
+			return llm.ToolResult{}, fmt.Errorf("id must be positive")
+		}
+		if args.RunAt != nil && args.CronExpr != nil {
+			return llm.ToolResult{}, fmt.Errorf("pass either run_at or cron_expr, not both")
+		}
+		// UpdateReminder checks the new channel against the reminder's own
+		// server.
+		if args.ChannelID != nil && toolCtx.GuildID == "" {
+			return llm.ToolResult{}, fmt.Errorf("channel_id can only be changed from a server channel")
+		}
+		input := reminders.UpdateReminderInput{
+			ID:        args.ID,
+			UserID:    toolCtx.UserID,
+			Message:   args.Message,
+			ChannelID: args.ChannelID,
+			Timezone:  args.Timezone,
+		}
+		if args.RunAt != nil {
+			schedule := string(reminders.ScheduleOnce)
+			input.Schedule = &schedule
+			input.At = args.RunAt
+		}
+		if args.CronExpr != nil {
+			schedule := string(reminders.ScheduleCron)
+			input.Schedule = &schedule
+			input.CronExpr = args.CronExpr
+		}
+		updated, ok, err := service.UpdateReminder(ctx, input)
+		if err != nil {
+			return llm.ToolResult{}, err
+		}
+		if !ok {
+			return llm.ToolResult{Content: fmt.Sprintf("Reminder ID %d was not found for this user.", args.ID)}, nil
+		}
+		return llm.ToolResult{Content: fmt.Sprintf("Updated reminder ID %d.\nMessage: %s\nNext run: %s\nChannel: %s\nTimezone: %s\nRepeat: %s",
+			updated.ID,
+			updated.Message,
+			discordTimestamp(updated.NextRun),
+			updated.ChannelID,
+			updated.Timezone,
+			humanSchedule(updated),
+		)}, nil
+	}
+}
+
 type reminderDeleteArgs struct {
 	ID int64 `json:"id"`
 }
@@ -192,3 +304,14 @@ func deleteReminder(service *reminders.Service) llm.ToolHandler {
 		)}, nil
 	}
 }
+
+func userDeliveryMode(ctx context.Context, settingsService *usersettings.Service, userID string) reminders.DeliveryMode {
+	if settingsService == nil {
+		return reminders.DeliveryChannel
+	}
+	mode, _, err := settingsService.GetDeliveryMode(ctx, userID)
+	if err != nil {
+		return reminders.DeliveryChannel
+	}
+	return mode
+}
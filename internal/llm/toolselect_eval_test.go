@@ -0,0 +1,122 @@
+package llm_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"mizubot-go/internal/llm"
+	llmtools "mizubot-go/internal/llm/tools"
+	"mizubot-go/internal/memories"
+	"mizubot-go/internal/reminders"
+	"mizubot-go/internal/usersettings"
+)
+
+// toolSelectionCases pairs messages with the tools the model needs to
+// answer them. About half avoid every keyword on purpose.
+var toolSelectionCases = []struct {
+	message string
+	want    []string
+}{
+	{"remind me to take meds at 9", []string{"reminder_create"}},
+	{"ping me at 5 to call mom", []string{"reminder_create"}},
+	{"nudge me tomorrow morning about the dentist", []string{"reminder_create"}},
+	{"don't let me forget to water the plants on friday", []string{"reminder_create"}},
+	{"what reminders do I have", []string{"reminder_list_active"}},
+	{"what's on my plate this week", []string{"reminder_list_active"}},
+	{"show me everything I've scheduled", []string{"reminder_list_active"}},
+	{"delete reminder 3", []string{"reminder_delete"}},
+	{"cancel the dentist thing, I already went", []string{"reminder_delete", "reminder_list_active"}},
+	{"move my reminder 4 to 6pm", []string{"reminder_update"}},
+	{"push the standup ping back an hour", []string{"reminder_update", "reminder_list_active"}},
+	{"set my timezone to India", []string{"user_timezone_set"}},
+	{"I live in New York now", []string{"user_timezone_set"}},
+	{"I moved to Berlin last month", []string{"user_timezone_set"}},
+	{"remember that I prefer metric units", []string{"memory_save"}},
+	{"fyi I'm vegetarian", []string{"memory_save"}},
+	{"keep in mind that my sister's name is Ana", []string{"memory_save"}},
+	{"what do you know about me", []string{"memory_search"}},
+	{"forget what I told you about my job", []string{"memory_forget", "memory_search"}},
+}
+
+func evalTools() []llm.Tool {
+	settings := usersettings.NewService(usersettings.NewStore(nil))
+	tools := llmtools.NewReminderTools(reminders.NewService(reminders.NewStore(nil)), settings)
+	tools = append(tools, llmtools.NewUserSettingsTools(settings)...)
+	return append(tools, llmtools.NewMemoryTools(memories.NewStore(nil))...)
+}
+
+// toolSelectionScore measures the tools a service offers for the eval
+// cases. Recall alone rewards offering every tool, so precision and the
+// average number of tools offered per case are kept too, to show what a
+// lower similarity threshold costs.
+type toolSelectionScore struct {
+	recall      float64 // share of wanted tools offered
+	precision   float64 // share of offered tools that were wanted
+	avgSelected float64 // tools offered per case
+}
+
+func (s toolSelectionScore) String() string {
+	return fmt.Sprintf("recall %.2f, precision %.2f, %.1f tools per case", s.recall, s.precision, s.avgSelected)
+}
+
+func scoreToolSelection(t *testing.T, service *llm.Service) toolSelectionScore {
+	t.Helper()
+	var wanted, selected, found int
+	for _, tc := range toolSelectionCases {
+		offered := service.ToolsForMessage(context.Background(), llm.Message{Content: tc.message})
+		selected += len(offered)
+		for _, name := range tc.want {
+			wanted++
+			if _, ok := offered[name]; ok {
+				found++
+			} else {
+				t.Logf("missed %s for %q", name, tc.message)
+			}
+		}
+	}
+	score := toolSelectionScore{
+		recall:      float64(found) / float64(wanted),
+		avgSelected: float64(selected) / float64(len(toolSelectionCases)),
+	}
+	if selected > 0 {
+		score.precision = float64(found) / float64(selected)
+	}
+	return score
+}
+
+// TestLLMEvalToolSelectionRecall compares keyword tool selection with
+// embedding selection. The embedding half needs a running Ollama; set
+// MIZUBOT_EVAL_EMBED_MODEL (and OLLAMA_BASE_URL if not local) to run it,
+// and MIZUBOT_EVAL_TOOL_SIMILARITY to try a threshold other than the
+// default.
+func TestLLMEvalToolSelectionRecall(t *testing.T) {
+	keyword := scoreToolSelection(t, llm.NewService(nil, evalTools()...))
+	t.Logf("keyword: %s", keyword)
+
+	model := os.Getenv("MIZUBOT_EVAL_EMBED_MODEL")
+	if model == "" {
+		t.Skip("set MIZUBOT_EVAL_EMBED_MODEL to measure embedding recall")
+	}
+	var threshold float64
+	if raw := os.Getenv("MIZUBOT_EVAL_TOOL_SIMILARITY"); raw != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(raw, 64); err != nil {
+			t.Fatalf("MIZUBOT_EVAL_TOOL_SIMILARITY: %v", err)
+		}
+	}
+	service := llm.NewService(nil, evalTools()...)
+	service.SetEmbedder(llm.NewOllamaEmbedder(llm.OllamaConfig{
+		BaseURL: os.Getenv("OLLAMA_BASE_URL"),
+		Model:   model,
+		Timeout: 30 * time.Second,
+	}), threshold)
+	semantic := scoreToolSelection(t, service)
+	t.Logf("embedding + keyword: %s", semantic)
+	if semantic.recall <= keyword.recall {
+		t.Fatalf("embedding recall %.2f does not improve on keyword recall %.2f", semantic.recall, keyword.recall)
+	}
+}
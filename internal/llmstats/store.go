@@ -28,6 +28,7 @@ type MessageLog struct {
 	Latency          time.Duration
 	Status           string
 	Error            string
+	Model            string // the model that answered, or the last one tried on error
 	CreatedAt        time.Time
 }
 
@@ -44,6 +45,7 @@ type CreateMessageLogParams struct {
 	Latency          time.Duration
 	Status           string
 	Error            string
+	Model            string
 }
 
 type Store struct {
@@ -74,6 +76,7 @@ func (s *Store) Create(ctx context.Context, params CreateMessageLogParams) (Mess
 		LatencyMs:        params.Latency.Milliseconds(),
 		Status:           status,
 		Error:            strings.TrimSpace(params.Error),
+		Model:            strings.TrimSpace(params.Model),
 		CreatedAt:        time.Now().UTC().Unix(),
 	})
 	if err != nil {
@@ -124,6 +127,7 @@ func convertMessageLog(row data.LlmMessageLog) MessageLog {
 		Latency:          time.Duration(row.LatencyMs) * time.Millisecond,
 		Status:           row.Status,
 		Error:            row.Error,
+		Model:            row.Model,
 		CreatedAt:        time.Unix(row.CreatedAt, 0).UTC(),
 	}
 }
@@ -29,7 +29,8 @@ func testDB(t *testing.T) *sql.DB {
 		latency_ms INTEGER NOT NULL DEFAULT 0,
 		status TEXT NOT NULL,
 		error TEXT NOT NULL DEFAULT '',
-		created_at INTEGER NOT NULL
+		created_at INTEGER NOT NULL,
+		model TEXT NOT NULL DEFAULT ''
 	)`)
 	if err != nil {
 		t.Fatal(err)
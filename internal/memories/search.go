@@ -0,0 +1,95 @@
+package memories
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// PromptLimit is how many memories go into the system prompt.
+const PromptLimit = 10
+
+// Search returns the user's memories in guildID that share words with
+// query, best match first. An empty query matches everything, newest first.
+func (s *Store) Search(ctx context.Context, userID, guildID, query string, limit int) ([]Memory, error) {
+	list, err := s.ListForGuild(ctx, userID, guildID)
+	if err != nil {
+		return nil, err
+	}
+	return rank(list, query, limit, false), nil
+}
+
+// RelevantMemories implements llm.MemoryProvider. When the user has more
+// than PromptLimit memories, the ones closest to text win and the rest are
+// filled with the newest.
+func (s *Store) RelevantMemories(ctx context.Context, userID, guildID, text string) ([]string, error) {
+	list, err := s.ListForGuild(ctx, userID, guildID)
+	if err != nil {
+		return nil, err
+	}
+	ranked := rank(list, text, PromptLimit, true)
+	out := make([]string, 0, len(ranked))
+	for _, memory := range ranked {
+		out = append(out, memory.Content)
+	}
+	return out, nil
+}
+
+// rank orders memories by how many of query's words they contain, newer
+// first on ties. Without keepUnmatched, memories sharing no words are
+// dropped unless query has no words at all.
+func rank(list []Memory, query string, limit int, keepUnmatched bool) []Memory {
+	queryWords := words(query)
+	type scored struct {
+		memory Memory
+		score  int
+	}
+	candidates := make([]scored, 0, len(list))
+	for _, memory := range list {
+		score := 0
+		for word := range words(memory.Content) {
+			if queryWords[word] {
+				score++
+			}
+		}
+		if score == 0 && len(queryWords) > 0 && !keepUnmatched {
+			continue
+		}
+		candidates = append(candidates, scored{memory: memory, score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].memory.ID > candidates[j].memory.ID
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	out := make([]Memory, 0, len(candidates))
+	for _, candidate := range candidates {
+		out = append(out, candidate.memory)
+	}
+	return out
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "you": true,
+	"what": true, "that": true, "this": true, "with": true, "have": true, "about": true,
+	"my": true, "me": true, "is": true, "am": true, "to": true, "of": true, "in": true,
+	"on": true, "it": true, "do": true, "an": true, "or": true,
+}
+
+func words(text string) map[string]bool {
+	out := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 2 || stopWords[word] {
+			continue
+		}
+		out[word] = true
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package memories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mizubot-go/internal/data"
+)
+
+const (
+	// MaxPerUser caps how many memories one user can keep, so the prompt
+	// and /memory list stay small.
+	MaxPerUser = 50
+	// MaxLength is the longest memory, in characters.
+	MaxLength = 300
+)
+
+// Memory is a fact the LLM saved about a user. GuildID is empty for
+// memories that apply everywhere, or the server they were saved for.
+type Memory struct {
+	ID        int64
+	UserID    string
+	GuildID   string
+	Content   string
+	CreatedAt time.Time
+}
+
+type Store struct {
+	db *sql.DB
+	q  *data.Queries
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, q: data.New()}
+}
+
+func (s *Store) Save(ctx context.Context, userID, guildID, content string) (Memory, error) {
+	userID = strings.TrimSpace(userID)
+	content = strings.Join(strings.Fields(content), " ")
+	if userID == "" {
+		return Memory{}, errors.New("missing user id")
+	}
+	if content == "" {
+		return Memory{}, errors.New("memory content is required")
+	}
+	if utf8.RuneCountInString(content) > MaxLength {
+		return Memory{}, fmt.Errorf("memory is too long; keep it under %d characters", MaxLength)
+	}
+	existing, err := s.List(ctx, userID)
+	if err != nil {
+		return Memory{}, err
+	}
+	if len(existing) >= MaxPerUser {
+		return Memory{}, fmt.Errorf("memory is full (%d saved); forget something first", MaxPerUser)
+	}
+	for _, memory := range existing {
+		if memory.GuildID == guildID && strings.EqualFold(memory.Content, content) {
+			return memory, nil
+		}
+	}
+
+	row, err := s.q.CreateLLMMemory(ctx, s.db, data.CreateLLMMemoryParams{
+		UserID:    userID,
+		GuildID:   strings.TrimSpace(guildID),
+		Content:   content,
+		CreatedAt: time.Now().UTC().Unix(),
+	})
+	if err != nil {
+		return Memory{}, err
+	}
+	return convertMemory(row), nil
+}
+
+// List returns all of a user's memories, oldest first.
+func (s *Store) List(ctx context.Context, userID string) ([]Memory, error) {
+	rows, err := s.q.ListLLMMemoriesByUser(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return convertMemories(rows), nil
+}
+
+// ListForGuild returns the user's memories that apply in guildID: those
+// saved for every server plus those saved for that one.
+func (s *Store) ListForGuild(ctx context.Context, userID, guildID string) ([]Memory, error) {
+	rows, err := s.q.ListLLMMemoriesForGuild(ctx, s.db, userID, guildID)
+	if err != nil {
+		return nil, err
+	}
+	return convertMemories(rows), nil
+}
+
+func (s *Store) Forget(ctx context.Context, userID string, id int64) (bool, error) {
+	n, err := s.q.DeleteLLMMemory(ctx, s.db, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Clear deletes every memory of the user and returns how many there were.
+func (s *Store) Clear(ctx context.Context, userID string) (int64, error) {
+	return s.q.DeleteLLMMemoriesByUser(ctx, s.db, userID)
+}
+
+func convertMemories(rows []data.LlmMemory) []Memory {
+	out := make([]Memory, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, convertMemory(row))
+	}
+	return out
+}
+
+func convertMemory(row data.LlmMemory) Memory {
+	return Memory{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		GuildID:   row.GuildID,
+		Content:   row.Content,
+		CreatedAt: time.Unix(row.CreatedAt, 0).UTC(),
+	}
+}
@@ -0,0 +1,130 @@
+package memories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE llm_memories (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL DEFAULT '',
+		content TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestStoreScopesMemoriesToGuild(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	for _, m := range []struct{ guild, content string }{
+		{"", "prefers metric units"},
+		{"guild-1", "moderates the art channel"},
+		{"guild-2", "plays bass in the server band"},
+	} {
+		if _, err := store.Save(ctx, "user-1", m.guild, m.content); err != nil {
+			t.Fatalf("Save %q: %v", m.content, err)
+		}
+	}
+
+	got, err := store.ListForGuild(ctx, "user-1", "guild-1")
+	if err != nil {
+		t.Fatalf("ListForGuild: %v", err)
+	}
+	if len(got) != 2 || got[0].Content != "prefers metric units" || got[1].Content != "moderates the art channel" {
+		t.Fatalf("ListForGuild = %+v", got)
+	}
+	if all, _ := store.List(ctx, "user-1"); len(all) != 3 {
+		t.Fatalf("List = %d memories, want 3", len(all))
+	}
+}
+
+func TestStoreSaveSkipsDuplicatesAndValidates(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	first, err := store.Save(ctx, "user-1", "", "working on  project X")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	again, err := store.Save(ctx, "user-1", "", "Working on project X")
+	if err != nil || again.ID != first.ID {
+		t.Fatalf("duplicate Save = %+v, %v; want existing memory %d", again, err, first.ID)
+	}
+	if _, err := store.Save(ctx, "user-1", "", strings.Repeat("x", MaxLength+1)); err == nil {
+		t.Fatal("Save accepted an over-long memory")
+	}
+	if _, err := store.Save(ctx, "user-1", "", "  "); err == nil {
+		t.Fatal("Save accepted an empty memory")
+	}
+}
+
+func TestStoreForgetAndClearOnlyTouchOwner(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	mine, _ := store.Save(ctx, "user-1", "", "likes tea")
+	if _, err := store.Save(ctx, "user-2", "", "likes coffee"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := store.Forget(ctx, "user-2", mine.ID); err != nil || ok {
+		t.Fatalf("Forget by another user = %v, %v; want no match", ok, err)
+	}
+	if ok, err := store.Forget(ctx, "user-1", mine.ID); err != nil || !ok {
+		t.Fatalf("Forget = %v, %v", ok, err)
+	}
+	if n, err := store.Clear(ctx, "user-2"); err != nil || n != 1 {
+		t.Fatalf("Clear = %d, %v; want 1", n, err)
+	}
+}
+
+func TestSearchRanksByMatchingWords(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	for _, content := range []string{"prefers metric units", "working on project X in Go", "has a cat named Miso"} {
+		if _, err := store.Save(ctx, "user-1", "", content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Search(ctx, "user-1", "", "what's my cat called?", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(got) != 1 || got[0].Content != "has a cat named Miso" {
+		t.Fatalf("Search = %+v", got)
+	}
+
+	relevant, err := store.RelevantMemories(ctx, "user-1", "", "how is the project going")
+	if err != nil {
+		t.Fatalf("RelevantMemories: %v", err)
+	}
+	if len(relevant) != 3 || relevant[0] != "working on project X in Go" {
+		t.Fatalf("RelevantMemories = %q", relevant)
+	}
+}
@@ -0,0 +1,19 @@
+package metrics
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+var (
+	// ReminderSends counts reminder delivery attempts by result: sent,
+	// failed, or dead once retries are exhausted.
+	ReminderSends = Default.NewCounterVec("mizubot_reminder_sends_total", "Reminder delivery attempts by result.", "result")
+	// PollerTickSeconds times one cycle of each background poller.
+	PollerTickSeconds = Default.NewHistogramVec("mizubot_poller_tick_duration_seconds", "Time spent in one poller cycle.", DefaultBuckets, "poller")
+	// LLMResponseSeconds times a whole LLM reply, including tool calls.
+	LLMResponseSeconds = Default.NewHistogramVec("mizubot_llm_response_duration_seconds", "Time to generate an LLM reply, including tool calls.", DefaultBuckets, "outcome")
+	// LLMTokens counts prompt and completion tokens reported by the model.
+	LLMTokens = Default.NewCounterVec("mizubot_llm_tokens_total", "LLM tokens used, by kind.", "kind")
+	// DiscordAPIErrors counts failed Discord REST calls by route and HTTP
+	// status, or "error" when no response arrived.
+	DiscordAPIErrors = Default.NewCounterVec("mizubot_discord_api_errors_total", "Failed Discord REST calls by route and status.", "route", "status")
+)
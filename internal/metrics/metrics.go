@@ -0,0 +1,249 @@
+// Package metrics keeps the bot's process-wide counters and histograms and
+// writes them in the Prometheus text exposition format. It covers only what
+// the bot needs, so the binary doesn't depend on the Prometheus client.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds, suited to ticks,
+// Discord calls and LLM replies alike.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+type collector interface {
+	write(w io.Writer) error
+}
+
+// Registry holds metrics in registration order.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText writes every metric in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry for Prometheus to scrape.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// series holds one value set per distinct combination of label values.
+type series[T any] struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*T
+	keys   map[string][]string
+}
+
+func newSeries[T any](name, help, kind string, labels []string) *series[T] {
+	return &series[T]{name: name, help: help, kind: kind, labels: labels, values: map[string]*T{}, keys: map[string][]string{}}
+}
+
+// with returns the value for labelValues, creating it with init. It must
+// be called with s.mu held.
+func (s *series[T]) with(labelValues []string, init func() *T) *T {
+	if len(labelValues) != len(s.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", s.name, len(s.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	v, ok := s.values[key]
+	if !ok {
+		v = init()
+		s.values[key] = v
+		s.keys[key] = append([]string(nil), labelValues...)
+	}
+	return v
+}
+
+func (s *series[T]) sortedKeys() []string {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *series[T]) header(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", s.name, s.help, s.name, s.kind)
+	return err
+}
+
+// CounterVec is a monotonically increasing value per label set.
+type CounterVec struct {
+	s *series[float64]
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{s: newSeries[float64](name, help, "counter", labels)}
+	r.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	*c.s.with(labelValues, func() *float64 { return new(float64) }) += v
+}
+
+// Value returns the current count for labelValues.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	return *c.s.with(labelValues, func() *float64 { return new(float64) })
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	if err := c.s.header(w); err != nil {
+		return err
+	}
+	for _, key := range c.s.sortedKeys() {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.s.name, labelText(c.s.labels, c.s.keys[key], "", ""), formatFloat(*c.s.values[key])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type histogramValue struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec counts observations into cumulative buckets per label set.
+type HistogramVec struct {
+	s       *series[histogramValue]
+	buckets []float64
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{s: newSeries[histogramValue](name, help, "histogram", labels), buckets: buckets}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	value := h.s.with(labelValues, func() *histogramValue {
+		return &histogramValue{counts: make([]uint64, len(h.buckets))}
+	})
+	for idx, bound := range h.buckets {
+		if v <= bound {
+			value.counts[idx]++
+		}
+	}
+	value.count++
+	value.sum += v
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *HistogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Count returns how many observations were made for labelValues.
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.with(labelValues, func() *histogramValue {
+		return &histogramValue{counts: make([]uint64, len(h.buckets))}
+	}).count
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	if err := h.s.header(w); err != nil {
+		return err
+	}
+	for _, key := range h.s.sortedKeys() {
+		value, labels := h.s.values[key], h.s.keys[key]
+		for idx, bound := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.s.name, labelText(h.s.labels, labels, "le", formatFloat(bound)), value.counts[idx]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.s.name, labelText(h.s.labels, labels, "le", "+Inf"), value.count,
+			h.s.name, labelText(h.s.labels, labels, "", ""), formatFloat(value.sum),
+			h.s.name, labelText(h.s.labels, labels, "", ""), value.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func labelText(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	parts := make([]string, 0, len(names)+1)
+	for idx, name := range names {
+		parts = append(parts, name+`="`+escapeLabel(values[idx])+`"`)
+	}
+	if extraName != "" {
+		parts = append(parts, extraName+`="`+escapeLabel(extraValue)+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	sends := registry.NewCounterVec("test_sends_total", "Sends.", "result")
+	latency := registry.NewHistogramVec("test_seconds", "Latency.", []float64{0.1, 1}, "op")
+	sends.Inc("sent")
+	sends.Add(2, "sent")
+	sends.Inc(`fa"il`)
+	latency.Observe(0.05, "tick")
+	latency.Observe(0.5, "tick")
+	latency.Observe(5, "tick")
+
+	var b strings.Builder
+	if err := registry.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_sends_total Sends.
+# TYPE test_sends_total counter
+test_sends_total{result="fa\"il"} 1
+test_sends_total{result="sent"} 3
+# HELP test_seconds Latency.
+# TYPE test_seconds histogram
+test_seconds_bucket{op="tick",le="0.1"} 1
+test_seconds_bucket{op="tick",le="1"} 2
+test_seconds_bucket{op="tick",le="+Inf"} 3
+test_seconds_sum{op="tick"} 5.55
+test_seconds_count{op="tick"} 3
+`
+	if b.String() != want {
+		t.Fatalf("WriteText =\n%s\nwant\n%s", b.String(), want)
+	}
+	if sends.Value("sent") != 3 || latency.Count("tick") != 3 {
+		t.Fatalf("Value=%v Count=%d", sends.Value("sent"), latency.Count("tick"))
+	}
+}
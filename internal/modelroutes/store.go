@@ -0,0 +1,131 @@
+package modelroutes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"mizubot-go/internal/data"
+)
+
+// Route is the model chain for one guild or channel, primary first.
+type Route struct {
+	ScopeID   string
+	Models    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Store struct {
+	db *sql.DB
+	q  *data.Queries
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, q: data.New()}
+}
+
+func (s *Store) Get(ctx context.Context, scopeID string) (Route, bool, error) {
+	scopeID = strings.TrimSpace(scopeID)
+	if scopeID == "" {
+		return Route{}, false, nil
+	}
+
+	row, err := s.q.GetLLMModelRoute(ctx, s.db, scopeID)
+	if err == sql.ErrNoRows {
+		return Route{}, false, nil
+	}
+	if err != nil {
+		return Route{}, false, err
+	}
+	return convertRoute(row), true, nil
+}
+
+func (s *Store) List(ctx context.Context) ([]Route, error) {
+	rows, err := s.q.ListLLMModelRoutes(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Route, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, convertRoute(row))
+	}
+	return out, nil
+}
+
+func (s *Store) Upsert(ctx context.Context, scopeID string, models []string) (Route, error) {
+	scopeID = strings.TrimSpace(scopeID)
+	models = normalizeModels(models)
+	if scopeID == "" {
+		return Route{}, errors.New("missing guild or channel id")
+	}
+	if len(models) == 0 {
+		return Route{}, errors.New("at least one model is required")
+	}
+
+	now := time.Now().UTC().Unix()
+	err := s.q.UpsertLLMModelRoute(ctx, s.db, data.UpsertLLMModelRouteParams{
+		ScopeID:   scopeID,
+		Models:    strings.Join(models, ","),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return Route{}, err
+	}
+	route, _, err := s.Get(ctx, scopeID)
+	return route, err
+}
+
+func (s *Store) Delete(ctx context.Context, scopeID string) (bool, error) {
+	n, err := s.q.DeleteLLMModelRoute(ctx, s.db, strings.TrimSpace(scopeID))
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetModelRoute implements llm.ModelRouteProvider.
+func (s *Store) GetModelRoute(ctx context.Context, scopeID string) ([]string, bool, error) {
+	route, ok, err := s.Get(ctx, scopeID)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return route.Models, true, nil
+}
+
+// Seed writes the routes from config, keyed by guild or channel ID.
+// Routes added to the table by other means are left alone.
+func Seed(ctx context.Context, store *Store, routes map[string][]string) error {
+	for scopeID, models := range routes {
+		if strings.TrimSpace(scopeID) == "" || len(normalizeModels(models)) == 0 {
+			continue
+		}
+		if _, err := store.Upsert(ctx, scopeID, models); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeModels(models []string) []string {
+	out := make([]string, 0, len(models))
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model != "" {
+			out = append(out, model)
+		}
+	}
+	return out
+}
+
+func convertRoute(row data.LlmModelRoute) Route {
+	return Route{
+		ScopeID:   row.ScopeID,
+		Models:    normalizeModels(strings.Split(row.Models, ",")),
+		CreatedAt: time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt: time.Unix(row.UpdatedAt, 0).UTC(),
+	}
+}
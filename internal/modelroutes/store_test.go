@@ -0,0 +1,82 @@
+package modelroutes
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE llm_model_routes (
+		scope_id TEXT NOT NULL PRIMARY KEY,
+		models TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestStoreGetMissingRoute(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	store := NewStore(db)
+	models, ok, err := store.GetModelRoute(context.Background(), "guild-1")
+	if err != nil {
+		t.Fatalf("GetModelRoute: %v", err)
+	}
+	if ok || models != nil {
+		t.Fatalf("GetModelRoute = %v, %v, want nothing", models, ok)
+	}
+}
+
+func TestStoreUpsertAndDeleteRoute(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	if _, err := store.Upsert(ctx, "guild-1", []string{"llama3.1:70b", " ", "llama3.2"}); err != nil {
+		t.Fatalf("Upsert first: %v", err)
+	}
+	route, err := store.Upsert(ctx, "guild-1", []string{" qwen2.5:32b ", "llama3.2"})
+	if err != nil {
+		t.Fatalf("Upsert second: %v", err)
+	}
+	if want := []string{"qwen2.5:32b", "llama3.2"}; !reflect.DeepEqual(route.Models, want) {
+		t.Fatalf("Models = %v, want %v", route.Models, want)
+	}
+
+	routes, err := store.List(ctx)
+	if err != nil || len(routes) != 1 {
+		t.Fatalf("List = %v, %v", routes, err)
+	}
+
+	deleted, err := store.Delete(ctx, "guild-1")
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if _, ok, _ := store.Get(ctx, "guild-1"); ok {
+		t.Fatal("route still present after Delete")
+	}
+}
+
+func TestStoreUpsertRequiresModels(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	if _, err := NewStore(db).Upsert(context.Background(), "guild-1", []string{" "}); err == nil {
+		t.Fatal("Upsert with no models succeeded")
+	}
+}
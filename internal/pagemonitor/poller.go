@@ -4,6 +4,9 @@ import (
 	"context"
 	"log"
 	"time"
+
+	"mizubot-go/internal/lifecycle"
+	"mizubot-go/internal/metrics"
 )
 
 type Notifier interface {
@@ -14,6 +17,7 @@ type Poller struct {
 	service  *Service
 	notifier Notifier
 	interval time.Duration
+	loop     lifecycle.Loop
 }
 
 func NewPoller(service *Service, notifier Notifier, interval time.Duration) *Poller {
@@ -21,23 +25,21 @@ func NewPoller(service *Service, notifier Notifier, interval time.Duration) *Pol
 }
 
 func (p *Poller) Start(ctx context.Context) {
-	go p.run(ctx)
+	p.loop.Start(ctx, p.interval, p.tick)
 }
 
-func (p *Poller) run(ctx context.Context) {
-	ticker := time.NewTicker(p.interval)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			p.tick(ctx)
-		}
-	}
+// Stop stops polling and waits for the monitors being checked.
+func (p *Poller) Stop(ctx context.Context) error {
+	return p.loop.Stop(ctx)
 }
 
 func (p *Poller) tick(ctx context.Context) {
+	defer metrics.PollerTickSeconds.ObserveSince(time.Now(), "pagemonitor")
+	if n, err := p.service.ResumeExpired(ctx, time.Now()); err != nil {
+		log.Printf("pagemonitor: resume paused monitors error: %v", err)
+	} else if n > 0 {
+		log.Printf("pagemonitor: resumed %d paused monitors", n)
+	}
 	monitors, err := p.service.DueMonitors(ctx, time.Now())
 	if err != nil {
 		log.Printf("pagemonitor: fetch due monitors error: %v", err)
@@ -66,6 +66,25 @@ func (s *Service) RemoveMonitor(ctx context.Context, id int64, userID string) (b
 	return s.store.Delete(ctx, id, userID)
 }
 
+// PauseMonitor stops checking an owned monitor until it is resumed, or
+// until `until` if it is non-zero.
+func (s *Service) PauseMonitor(ctx context.Context, id int64, userID string, until time.Time) (bool, error) {
+	if !until.IsZero() && !until.After(time.Now()) {
+		return false, errors.New("Pause-until time must be in the future.")
+	}
+	return s.store.Pause(ctx, id, userID, until)
+}
+
+// ResumeMonitor unpauses an owned monitor and checks it right away.
+func (s *Service) ResumeMonitor(ctx context.Context, id int64, userID string) (bool, error) {
+	return s.store.Resume(ctx, id, userID, time.Now().UTC())
+}
+
+// ResumeExpired unpauses monitors whose paused-until time has passed.
+func (s *Service) ResumeExpired(ctx context.Context, now time.Time) (int64, error) {
+	return s.store.ResumeExpired(ctx, now)
+}
+
 func (s *Service) DueMonitors(ctx context.Context, now time.Time) ([]Monitor, error) {
 	return s.store.Due(ctx, now, 50)
 }
@@ -23,6 +23,8 @@ type Monitor struct {
 	NextCheck     time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+	Paused        bool
+	PausedUntil   time.Time // zero when paused indefinitely
 }
 
 type Store struct {
@@ -103,6 +105,49 @@ func (s *Store) Delete(ctx context.Context, id int64, userID string) (bool, erro
 	return n > 0, nil
 }
 
+// Pause stops an owned monitor from being checked. A non-zero until lets the
+// poller resume it automatically.
+func (s *Store) Pause(ctx context.Context, id int64, userID string, until time.Time) (bool, error) {
+	var untilPtr *int64
+	if !until.IsZero() {
+		v := until.UTC().Unix()
+		untilPtr = &v
+	}
+	n, err := s.q.PausePageMonitor(ctx, s.db, data.PausePageMonitorParams{
+		PausedUntil: untilPtr,
+		UpdatedAt:   time.Now().UTC().Unix(),
+		ID:          id,
+		UserID:      userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Resume unpauses an owned monitor and schedules its next check. It returns
+// false if the monitor does not exist, is not owned by userID, or is not
+// paused.
+func (s *Store) Resume(ctx context.Context, id int64, userID string, nextCheck time.Time) (bool, error) {
+	n, err := s.q.ResumePageMonitor(ctx, s.db, data.ResumePageMonitorParams{
+		NextCheck: nextCheck.UTC().Unix(),
+		UpdatedAt: time.Now().UTC().Unix(),
+		ID:        id,
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ResumeExpired unpauses monitors whose paused-until time has passed. Their
+// next check is already overdue, so they are checked on the next tick.
+func (s *Store) ResumeExpired(ctx context.Context, now time.Time) (int64, error) {
+	cutoff := now.UTC().Unix()
+	return s.q.ResumeExpiredPageMonitors(ctx, s.db, time.Now().UTC().Unix(), &cutoff)
+}
+
 func monitorFromListRow(r data.ListPageMonitorsByUserRow) Monitor {
 	return Monitor{
 		ID:            r.ID,
@@ -119,6 +164,8 @@ func monitorFromListRow(r data.ListPageMonitorsByUserRow) Monitor {
 		NextCheck:     time.Unix(r.NextCheck, 0).UTC(),
 		CreatedAt:     time.Unix(r.CreatedAt, 0).UTC(),
 		UpdatedAt:     time.Unix(r.UpdatedAt, 0).UTC(),
+		Paused:        r.Paused != 0,
+		PausedUntil:   timeFromPtr(r.PausedUntil),
 	}
 }
 
@@ -138,5 +185,14 @@ func monitorFromDueRow(r data.ListDuePageMonitorsRow) Monitor {
 		NextCheck:     time.Unix(r.NextCheck, 0).UTC(),
 		CreatedAt:     time.Unix(r.CreatedAt, 0).UTC(),
 		UpdatedAt:     time.Unix(r.UpdatedAt, 0).UTC(),
+		Paused:        r.Paused != 0,
+		PausedUntil:   timeFromPtr(r.PausedUntil),
+	}
+}
+
+func timeFromPtr(v *int64) time.Time {
+	if v == nil {
+		return time.Time{}
 	}
+	return time.Unix(*v, 0).UTC()
 }
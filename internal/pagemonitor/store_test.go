@@ -0,0 +1,107 @@
+package pagemonitor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`CREATE TABLE page_monitors (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id TEXT NOT NULL,
+        channel_id TEXT NOT NULL,
+        guild_id TEXT,
+        url TEXT NOT NULL,
+        label TEXT NOT NULL,
+        last_status TEXT NOT NULL DEFAULT 'unknown',
+        check_interval INTEGER NOT NULL DEFAULT 300,
+        next_check INTEGER NOT NULL,
+        created_at INTEGER NOT NULL,
+        updated_at INTEGER NOT NULL,
+        content_hash TEXT NOT NULL DEFAULT '',
+        last_content TEXT NOT NULL DEFAULT '',
+        selector TEXT NOT NULL DEFAULT '',
+        paused INTEGER NOT NULL DEFAULT 0,
+        paused_until INTEGER
+    );`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestPauseAndResumeMonitor(t *testing.T) {
+	db := openTestDB(t)
+	service := NewService(NewStore(db))
+	ctx := context.Background()
+
+	mon, err := service.AddMonitor(ctx, AddMonitorInput{UserID: "u", ChannelID: "c", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	now := time.Now().UTC()
+
+	if ok, err := service.PauseMonitor(ctx, mon.ID, "other", time.Time{}); err != nil || ok {
+		t.Fatalf("pause by non-owner: ok=%v err=%v", ok, err)
+	}
+	if ok, err := service.PauseMonitor(ctx, mon.ID, "u", time.Time{}); err != nil || !ok {
+		t.Fatalf("pause: ok=%v err=%v", ok, err)
+	}
+	due, err := service.DueMonitors(ctx, now.Add(time.Minute))
+	if err != nil || len(due) != 0 {
+		t.Fatalf("paused monitor should not be due: %+v err=%v", due, err)
+	}
+
+	if ok, err := service.ResumeMonitor(ctx, mon.ID, "u"); err != nil || !ok {
+		t.Fatalf("resume: ok=%v err=%v", ok, err)
+	}
+	if ok, err := service.ResumeMonitor(ctx, mon.ID, "u"); err != nil || ok {
+		t.Fatalf("resume of an active monitor: ok=%v err=%v", ok, err)
+	}
+	due, err = service.DueMonitors(ctx, now.Add(time.Minute))
+	if err != nil || len(due) != 1 {
+		t.Fatalf("resumed monitor should be due: %+v err=%v", due, err)
+	}
+}
+
+func TestResumeExpiredMonitors(t *testing.T) {
+	db := openTestDB(t)
+	service := NewService(NewStore(db))
+	ctx := context.Background()
+
+	mon, err := service.AddMonitor(ctx, AddMonitorInput{UserID: "u", ChannelID: "c", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	until := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+	if ok, err := service.PauseMonitor(ctx, mon.ID, "u", until); err != nil || !ok {
+		t.Fatalf("pause: ok=%v err=%v", ok, err)
+	}
+
+	if n, err := service.ResumeExpired(ctx, until.Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("resumed %d monitors before paused_until (err=%v)", n, err)
+	}
+	list, err := service.ListMonitors(ctx, "u")
+	if err != nil || len(list) != 1 || !list[0].Paused || !list[0].PausedUntil.Equal(until) {
+		t.Fatalf("paused monitor = %+v err=%v", list, err)
+	}
+
+	if n, err := service.ResumeExpired(ctx, until); err != nil || n != 1 {
+		t.Fatalf("resumed %d monitors at paused_until (err=%v), want 1", n, err)
+	}
+	due, err := service.DueMonitors(ctx, until)
+	if err != nil || len(due) != 1 || due[0].Paused {
+		t.Fatalf("auto-resumed monitor should be due: %+v err=%v", due, err)
+	}
+}
@@ -0,0 +1,110 @@
+package reminders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CatchUpPolicy decides what a recurring reminder does with the runs it
+// missed while the bot was offline.
+type CatchUpPolicy string
+
+const (
+	CatchUpOnce CatchUpPolicy = "once" // send one reminder for all missed runs
+	CatchUpEach CatchUpPolicy = "each" // send every missed run, up to CatchUpEachLimit
+	CatchUpSkip CatchUpPolicy = "skip" // drop missed runs and wait for the next one
+)
+
+// CatchUpEachLimit caps how many overdue runs a CatchUpEach reminder sends
+// after downtime. Older runs beyond the cap are counted as missed.
+const CatchUpEachLimit = 5
+
+// missedScanLimit bounds how many overdue runs are walked for one reminder,
+// so a minutely schedule after a long outage can't stall a tick.
+const missedScanLimit = 10000
+
+// ParseCatchUpPolicy validates a policy name. An empty value means
+// CatchUpOnce.
+func ParseCatchUpPolicy(v string) (CatchUpPolicy, error) {
+	policy := CatchUpPolicy(strings.ToLower(strings.TrimSpace(v)))
+	switch policy {
+	case "":
+		return CatchUpOnce, nil
+	case CatchUpOnce, CatchUpEach, CatchUpSkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("Invalid catch-up policy %q. Use once, each, or skip.", v)
+	}
+}
+
+func catchUpOrDefault(policy CatchUpPolicy) CatchUpPolicy {
+	if policy == "" {
+		return CatchUpOnce
+	}
+	return policy
+}
+
+// CatchUpPlan is how an overdue reminder should proceed after downtime.
+type CatchUpPlan struct {
+	NextRun time.Time // the run to send now, or to wait for when Send is false
+	Missed  int64     // total runs that will not be sent, including earlier catch-ups
+	Send    bool
+}
+
+// PlanCatchUp checks whether a due reminder missed runs before now and, if
+// so, applies its catch-up policy. It returns false when the reminder is
+// simply due and should be sent as usual.
+func PlanCatchUp(r Reminder, now time.Time) (CatchUpPlan, bool, error) {
+	runs := []time.Time{r.NextRun}
+	for len(runs) < missedScanLimit {
+		next, repeat, err := NextAfter(r, runs[len(runs)-1])
+		if err != nil {
+			return CatchUpPlan{}, false, err
+		}
+		if !repeat || next.After(now) {
+			break
+		}
+		runs = append(runs, next)
+	}
+	if len(runs) == 1 {
+		return CatchUpPlan{}, false, nil
+	}
+
+	switch catchUpOrDefault(r.CatchUp) {
+	case CatchUpEach:
+		if len(runs) <= CatchUpEachLimit {
+			return CatchUpPlan{}, false, nil
+		}
+		dropped := len(runs) - CatchUpEachLimit
+		return CatchUpPlan{NextRun: runs[dropped], Missed: r.MissedRuns + int64(dropped), Send: true}, true, nil
+	case CatchUpSkip:
+		next, _, err := NextAfter(r, now)
+		if err != nil {
+			return CatchUpPlan{}, false, err
+		}
+		return CatchUpPlan{NextRun: next, Missed: r.MissedRuns + int64(len(runs))}, true, nil
+	default:
+		last := len(runs) - 1
+		return CatchUpPlan{NextRun: runs[last], Missed: r.MissedRuns + int64(last), Send: true}, true, nil
+	}
+}
+
+// MissedRunsNote describes skipped runs for a delivered message, e.g.
+// "missed 3 runs while offline".
+func MissedRunsNote(missed int64) string {
+	if missed == 1 {
+		return "missed 1 run while offline"
+	}
+	return fmt.Sprintf("missed %d runs while offline", missed)
+}
+
+// RescheduleFrom returns the time the following run should be computed
+// from once r has been sent at now. CatchUpEach reminders step through
+// their overdue runs one at a time; everything else resumes after now.
+func RescheduleFrom(r Reminder, now time.Time) time.Time {
+	if catchUpOrDefault(r.CatchUp) == CatchUpEach && r.NextRun.Before(now) {
+		return r.NextRun
+	}
+	return now
+}
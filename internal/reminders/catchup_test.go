@@ -0,0 +1,102 @@
+package reminders
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestPlanCatchUp(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	hourly := Reminder{Schedule: ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: base}
+
+	tests := []struct {
+		name     string
+		reminder Reminder
+		now      time.Time
+		wantLate bool
+		want     CatchUpPlan
+	}{
+		{
+			name:     "on time",
+			reminder: hourly,
+			now:      base.Add(10 * time.Second),
+		},
+		{
+			name:     "one time reminders never catch up",
+			reminder: Reminder{Schedule: ScheduleOnce, Once: true, NextRun: base},
+			now:      base.Add(48 * time.Hour),
+		},
+		{
+			name:     "once sends the latest missed run",
+			reminder: withCatchUp(hourly, CatchUpOnce),
+			now:      base.Add(3*time.Hour + 5*time.Minute),
+			wantLate: true,
+			want:     CatchUpPlan{NextRun: base.Add(3 * time.Hour), Missed: 3, Send: true},
+		},
+		{
+			name:     "empty policy behaves like once",
+			reminder: hourly,
+			now:      base.Add(time.Hour),
+			wantLate: true,
+			want:     CatchUpPlan{NextRun: base.Add(time.Hour), Missed: 1, Send: true},
+		},
+		{
+			name:     "each within the cap sends every run in turn",
+			reminder: withCatchUp(hourly, CatchUpEach),
+			now:      base.Add(3 * time.Hour),
+		},
+		{
+			name:     "each drops runs beyond the cap",
+			reminder: withCatchUp(hourly, CatchUpEach),
+			now:      base.Add(7*time.Hour + time.Minute),
+			wantLate: true,
+			want:     CatchUpPlan{NextRun: base.Add(3 * time.Hour), Missed: 3, Send: true},
+		},
+		{
+			name:     "skip waits for the next future run",
+			reminder: withCatchUp(hourly, CatchUpSkip),
+			now:      base.Add(2*time.Hour + time.Minute),
+			wantLate: true,
+			want:     CatchUpPlan{NextRun: base.Add(3 * time.Hour), Missed: 3},
+		},
+		{
+			name:     "missed runs accumulate across catch-ups",
+			reminder: Reminder{Schedule: ScheduleDaily, AtTime: sql.NullString{String: "09:00", Valid: true}, NextRun: base, MissedRuns: 2, CatchUp: CatchUpOnce},
+			now:      base.Add(49 * time.Hour),
+			wantLate: true,
+			want:     CatchUpPlan{NextRun: base.Add(48 * time.Hour), Missed: 4, Send: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, late, err := PlanCatchUp(tt.reminder, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if late != tt.wantLate {
+				t.Fatalf("late = %v, want %v", late, tt.wantLate)
+			}
+			if !got.NextRun.Equal(tt.want.NextRun) || got.Missed != tt.want.Missed || got.Send != tt.want.Send {
+				t.Fatalf("plan = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCatchUpPolicy(t *testing.T) {
+	if got, err := ParseCatchUpPolicy(""); err != nil || got != CatchUpOnce {
+		t.Fatalf("empty policy = %q, %v", got, err)
+	}
+	if got, err := ParseCatchUpPolicy(" Skip "); err != nil || got != CatchUpSkip {
+		t.Fatalf("skip policy = %q, %v", got, err)
+	}
+	if _, err := ParseCatchUpPolicy("twice"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+func withCatchUp(r Reminder, policy CatchUpPolicy) Reminder {
+	r.CatchUp = policy
+	return r
+}
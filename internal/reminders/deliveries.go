@@ -0,0 +1,195 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mizubot-go/internal/data"
+)
+
+const (
+	DeliveryClaimed = "claimed"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// DeliveryClaimTimeout is how long a claimed run may go without being sent
+// or failed before another attempt may claim it again. Until then the claim
+// is taken to be in flight, so two instances never send the same run.
+const DeliveryClaimTimeout = 5 * time.Minute
+
+// Delivery is one row of the reminder delivery ledger. Each scheduled run of
+// a reminder has exactly one row, keyed by reminder ID and ScheduledFor, that
+// is updated as the run is claimed, sent, or fails.
+type Delivery struct {
+	ID             int64
+	ReminderID     int64
+	ScheduledFor   time.Time
+	Status         string
+	Attempts       int64
+	Error          string
+	MissedRuns     int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Via            DeliveryMode // where a sent run was delivered
+	FallbackReason string       // why a fallback reminder went to DM instead
+}
+
+// ClaimDelivery records an attempt to send the run of r scheduled at
+// r.NextRun. It returns false when there is nothing to send: either the run
+// was already delivered (the returned Delivery has status "sent"), another
+// attempt claimed it less than DeliveryClaimTimeout ago (status "claimed"),
+// or the reminder changed since it was loaded (the returned Delivery is
+// empty).
+func (s *Store) ClaimDelivery(ctx context.Context, r Reminder) (Delivery, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Delivery{}, false, err
+	}
+	defer tx.Rollback()
+
+	scheduledFor := r.NextRun.UTC().Unix()
+	nextRun, err := s.q.GetDueNextRun(ctx, tx, r.ID)
+	if err == sql.ErrNoRows {
+		return Delivery{}, false, nil
+	}
+	if err != nil {
+		return Delivery{}, false, err
+	}
+	if nextRun != scheduledFor {
+		return Delivery{}, false, nil
+	}
+
+	now := time.Now().UTC()
+	row, err := s.q.ClaimReminderDelivery(ctx, tx, data.ClaimReminderDeliveryParams{
+		ReminderID:   r.ID,
+		ScheduledFor: scheduledFor,
+		CreatedAt:    now.Unix(),
+		UpdatedAt:    now.Unix(),
+		MissedRuns:   r.MissedRuns,
+		StaleBefore:  now.Add(-DeliveryClaimTimeout).Unix(),
+	})
+	if err == sql.ErrNoRows {
+		existing, err := s.q.GetReminderDelivery(ctx, tx, r.ID, scheduledFor)
+		if err != nil {
+			return Delivery{}, false, err
+		}
+		return convertDelivery(existing), false, tx.Commit()
+	}
+	if err != nil {
+		return Delivery{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Delivery{}, false, err
+	}
+	return convertDelivery(row), true, nil
+}
+
+// FinishDelivery marks a delivery as sent, recording where it went, and
+// advances its reminder in one transaction: recurring reminders move to
+// next, others are completed.
+func (s *Store) FinishDelivery(ctx context.Context, d Delivery, receipt Receipt, next time.Time, repeat bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if err := s.q.FinishReminderDelivery(ctx, tx, data.FinishReminderDeliveryParams{
+		DeliveredVia:   string(receipt.Via),
+		FallbackReason: receipt.FallbackReason,
+		UpdatedAt:      now.Unix(),
+		ID:             d.ID,
+	}); err != nil {
+		return err
+	}
+	if err := s.q.CountReminderRun(ctx, tx, d.ReminderID); err != nil {
+		return err
+	}
+	if repeat {
+		err = s.q.SetNextRun(ctx, tx, data.SetNextRunParams{NextRun: next.UTC().Unix(), UpdatedAt: now.Unix(), ID: d.ReminderID})
+	} else {
+		completedAt := now.Unix()
+		err = s.q.CompleteReminder(ctx, tx, data.CompleteReminderParams{CompletedAt: &completedAt, UpdatedAt: now.Unix(), ID: d.ReminderID})
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeliveryFailure describes how a reminder backs off after a failed send.
+// RetryAt is when the next attempt may happen; DeadAt is set instead once
+// the reminder has failed too many times and should stop retrying.
+type DeliveryFailure struct {
+	Error        string
+	FailureCount int64
+	RetryAt      time.Time
+	DeadAt       time.Time
+}
+
+// FailDelivery records a failed send on both the ledger row and the
+// reminder. The reminder keeps its next run so the retry reuses the same
+// ledger row.
+func (s *Store) FailDelivery(ctx context.Context, d Delivery, f DeliveryFailure) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Unix()
+	if err := s.q.SetReminderDeliveryStatus(ctx, tx, data.SetReminderDeliveryStatusParams{
+		Status:    DeliveryFailed,
+		Error:     f.Error,
+		UpdatedAt: now,
+		ID:        d.ID,
+	}); err != nil {
+		return err
+	}
+	if err := s.q.RecordReminderFailure(ctx, tx, data.RecordReminderFailureParams{
+		FailureCount: f.FailureCount,
+		RetryAt:      timePtr(f.RetryAt),
+		LastError:    f.Error,
+		DeadAt:       timePtr(f.DeadAt),
+		UpdatedAt:    now,
+		ID:           d.ReminderID,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, reminderID int64, limit int) ([]Delivery, error) {
+	rows, err := s.q.ListReminderDeliveries(ctx, s.db, reminderID, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Delivery, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, convertDelivery(row))
+	}
+	return out, nil
+}
+
+func (s *Store) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	return s.q.DeleteReminderDeliveriesBefore(ctx, s.db, before.UTC().Unix())
+}
+
+func convertDelivery(row data.ReminderDelivery) Delivery {
+	return Delivery{
+		ID:             row.ID,
+		ReminderID:     row.ReminderID,
+		ScheduledFor:   time.Unix(row.ScheduledFor, 0).UTC(),
+		Status:         row.Status,
+		Attempts:       row.Attempts,
+		Error:          row.Error,
+		MissedRuns:     row.MissedRuns,
+		CreatedAt:      time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:      time.Unix(row.UpdatedAt, 0).UTC(),
+		Via:            DeliveryMode(row.DeliveredVia),
+		FallbackReason: row.FallbackReason,
+	}
+}
@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeliveryMode is where a reminder is sent.
+type DeliveryMode string
+
+const (
+	DeliveryChannel  DeliveryMode = "channel"  // post in ChannelID
+	DeliveryDM       DeliveryMode = "dm"       // DM the owner
+	DeliveryFallback DeliveryMode = "fallback" // post in ChannelID, DM the owner if that fails
+)
+
+// ParseDeliveryMode validates a delivery mode name. An empty value means
+// DeliveryChannel.
+func ParseDeliveryMode(v string) (DeliveryMode, error) {
+	mode := DeliveryMode(strings.ToLower(strings.TrimSpace(v)))
+	switch mode {
+	case "":
+		return DeliveryChannel, nil
+	case DeliveryChannel, DeliveryDM, DeliveryFallback:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("Invalid delivery mode %q. Use channel, dm, or fallback.", v)
+	}
+}
+
+func deliveryModeOrDefault(mode DeliveryMode) DeliveryMode {
+	if mode == "" {
+		return DeliveryChannel
+	}
+	return mode
+}
+
+// Receipt says where a sent reminder ended up. FallbackReason is set when a
+// DeliveryFallback reminder could not be posted in its channel and was sent
+// by DM instead.
+type Receipt struct {
+	Via            DeliveryMode // DeliveryChannel or DeliveryDM
+	FallbackReason string
+}
@@ -0,0 +1,91 @@
+package reminders
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HasEndCondition reports whether the reminder retires on its own, by end
+// date or by run count.
+func (r Reminder) HasEndCondition() bool {
+	return !r.EndsAt.IsZero() || r.RemainingRuns.Valid
+}
+
+// EndReached reports whether the reminder's current run falls past its end
+// date or it has no runs left, so it should retire without sending.
+func EndReached(r Reminder) bool {
+	if r.RemainingRuns.Valid && r.RemainingRuns.Int64 <= 0 {
+		return true
+	}
+	return !r.EndsAt.IsZero() && r.NextRun.After(r.EndsAt)
+}
+
+// checkNotEnded explains why a rescheduled reminder would be retired at its
+// next run without being sent, or returns nil when it can still run.
+func checkNotEnded(r Reminder) error {
+	if r.RemainingRuns.Valid && r.RemainingRuns.Int64 <= 0 {
+		return errors.New("This reminder has ended: it already ran as many times as it was set to. Create a new reminder instead.")
+	}
+	if !r.EndsAt.IsZero() && r.NextRun.After(r.EndsAt) {
+		loc, err := time.LoadLocation(timezoneOrUTC(r.Timezone))
+		if err != nil {
+			loc = time.UTC
+		}
+		return fmt.Errorf("This reminder ends on %s, before the new schedule's next run. Create a new reminder instead.", r.EndsAt.In(loc).Format("2006-01-02 15:04 MST"))
+	}
+	return nil
+}
+
+// ContinuesAfter reports whether the reminder should be rescheduled to next
+// once its current run is delivered, given its end conditions.
+func ContinuesAfter(r Reminder, next time.Time) bool {
+	if r.RemainingRuns.Valid && r.RemainingRuns.Int64 <= 1 {
+		return false
+	}
+	return r.EndsAt.IsZero() || !next.After(r.EndsAt)
+}
+
+// ParseEndsAt parses an end date the same way as ParseUntil, except that a
+// bare date includes that whole day.
+func ParseEndsAt(now time.Time, s string, timezone string) (time.Time, error) {
+	endsAt, err := ParseUntil(now, s, timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := time.Parse("2006-01-02", strings.TrimSpace(s)); err == nil {
+		endsAt = endsAt.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return endsAt, nil
+}
+
+// applyEndConditions validates and sets the end conditions of a reminder
+// that is about to be created.
+func applyEndConditions(now time.Time, r *Reminder, endsAt string, remainingRuns int64) error {
+	endsAt = strings.TrimSpace(endsAt)
+	if endsAt == "" && remainingRuns == 0 {
+		return nil
+	}
+	if r.Once {
+		return errors.New("End dates and run limits only apply to recurring reminders.")
+	}
+	if remainingRuns < 0 {
+		return errors.New("The number of runs must be at least 1.")
+	}
+	if remainingRuns > 0 {
+		r.RemainingRuns.Int64 = remainingRuns
+		r.RemainingRuns.Valid = true
+	}
+	if endsAt != "" {
+		parsed, err := ParseEndsAt(now, endsAt, r.Timezone)
+		if err != nil {
+			return err
+		}
+		if parsed.Before(r.NextRun) {
+			return errors.New("The end date is before the reminder's first run.")
+		}
+		r.EndsAt = parsed
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package reminders
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"mizubot-go/internal/data"
+	"mizubot-go/internal/lifecycle"
+	"mizubot-go/internal/metrics"
+)
+
+const (
+	// feedHorizon is how far ahead a calendar feed lists occurrences.
+	feedHorizon = 60 * 24 * time.Hour
+	// maxFeedOccurrences caps the occurrences listed per reminder so
+	// minute-level schedules don't flood a calendar.
+	maxFeedOccurrences = 100
+	// feedObjectDir is where feeds are published, relative to the
+	// publisher's prefix.
+	feedObjectDir = "reminder-feeds"
+)
+
+// CalendarPublisher stores a rendered calendar feed and returns its public
+// URL. animefeed.S3Publisher implements it alongside the anime RSS feeds.
+type CalendarPublisher interface {
+	PublishObject(ctx context.Context, name, contentType string, body []byte) (string, error)
+	DeleteObject(ctx context.Context, name string) error
+}
+
+// FeedService publishes each opted-in user's upcoming reminder occurrences
+// as a read-only iCalendar feed at an unguessable URL.
+type FeedService struct {
+	store     *Store
+	publisher CalendarPublisher
+}
+
+func NewFeedService(store *Store, publisher CalendarPublisher) *FeedService {
+	return &FeedService{store: store, publisher: publisher}
+}
+
+// Available reports whether feeds can be published at all.
+func (f *FeedService) Available() bool {
+	return f != nil && f.publisher != nil
+}
+
+// Enable publishes the user's feed and returns its URL, creating a secret
+// token on first use. With rotate, the old token and its feed are
+// replaced, so previously shared URLs stop updating.
+func (f *FeedService) Enable(ctx context.Context, userID string, rotate bool) (string, error) {
+	if !f.Available() {
+		return "", errors.New("Calendar feeds aren't configured on this bot.")
+	}
+	token, ok, err := f.store.FeedToken(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if ok && rotate {
+		if err := f.publisher.DeleteObject(ctx, feedObjectName(token)); err != nil {
+			return "", err
+		}
+	}
+	if !ok || rotate {
+		if token, err = newFeedToken(); err != nil {
+			return "", err
+		}
+		if err := f.store.SetFeedToken(ctx, userID, token); err != nil {
+			return "", err
+		}
+	}
+	return f.publish(ctx, userID, token)
+}
+
+// Disable removes the user's feed. It returns false when no feed existed.
+func (f *FeedService) Disable(ctx context.Context, userID string) (bool, error) {
+	token, ok, err := f.store.FeedToken(ctx, userID)
+	if err != nil || !ok {
+		return false, err
+	}
+	if f.Available() {
+		if err := f.publisher.DeleteObject(ctx, feedObjectName(token)); err != nil {
+			return false, err
+		}
+	}
+	return f.store.DeleteFeedToken(ctx, userID)
+}
+
+// PublishAll refreshes every enabled feed so the listed occurrences follow
+// reminder edits and the moving horizon.
+func (f *FeedService) PublishAll(ctx context.Context) error {
+	if !f.Available() {
+		return nil
+	}
+	feeds, err := f.store.FeedTokens(ctx)
+	if err != nil {
+		return err
+	}
+	for _, feed := range feeds {
+		if _, err := f.publish(ctx, feed.UserID, feed.Token); err != nil {
+			log.Printf("reminder feed publish error: user_id=%s error=%v", feed.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (f *FeedService) publish(ctx context.Context, userID, token string) (string, error) {
+	list, err := f.store.ListByUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	body := EncodeFeedICS(time.Now().UTC(), list)
+	return f.publisher.PublishObject(ctx, feedObjectName(token), "text/calendar; charset=utf-8", body)
+}
+
+// EncodeFeedICS lists each reminder's occurrences within the feed horizon
+// as separate VEVENTs, expanded from cron_expr and honouring pauses and end
+// conditions. Reminders paused without an end date and dead reminders are
+// left out.
+func EncodeFeedICS(now time.Time, list []Reminder) []byte {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:"+icsProdID)
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:MizuBot reminders")
+	writeICSLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	horizon := now.Add(feedHorizon)
+	for _, r := range list {
+		if r.Dead() || (r.Paused && r.PausedUntil.IsZero()) {
+			continue
+		}
+		runs, err := UpcomingRuns(r, maxFeedOccurrences)
+		if err != nil {
+			log.Printf("reminder feed expand error: reminder_id=%d error=%v", r.ID, err)
+			continue
+		}
+		for _, run := range runs {
+			if run.After(horizon) {
+				break
+			}
+			if r.Paused && run.Before(r.PausedUntil) {
+				continue
+			}
+			writeICSLine(&b, "BEGIN:VEVENT")
+			writeICSLine(&b, fmt.Sprintf("UID:reminder-%d-%d@mizubot", r.ID, run.Unix()))
+			writeICSLine(&b, "DTSTAMP:"+now.UTC().Format(icsUTCLayout))
+			writeICSLine(&b, "DTSTART:"+run.UTC().Format(icsUTCLayout))
+			writeICSLine(&b, "SUMMARY:"+escapeICSText(r.Message))
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+			writeICSLine(&b, "END:VEVENT")
+		}
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func feedObjectName(token string) string {
+	return feedObjectDir + "/" + token + ".ics"
+}
+
+func newFeedToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// FeedToken is a user's secret calendar feed token.
+type FeedToken struct {
+	UserID string
+	Token  string
+}
+
+func (s *Store) FeedToken(ctx context.Context, userID string) (string, bool, error) {
+	token, err := s.q.GetReminderFeedToken(ctx, s.db, userID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+func (s *Store) SetFeedToken(ctx context.Context, userID, token string) error {
+	return s.q.UpsertReminderFeedToken(ctx, s.db, data.UpsertReminderFeedTokenParams{
+		UserID:    userID,
+		Token:     token,
+		CreatedAt: time.Now().UTC().Unix(),
+	})
+}
+
+func (s *Store) DeleteFeedToken(ctx context.Context, userID string) (bool, error) {
+	n, err := s.q.DeleteReminderFeedToken(ctx, s.db, userID)
+	return n > 0, err
+}
+
+func (s *Store) FeedTokens(ctx context.Context) ([]FeedToken, error) {
+	rows, err := s.q.ListReminderFeedTokens(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FeedToken, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, FeedToken{UserID: row.UserID, Token: row.Token})
+	}
+	return out, nil
+}
+
+// FeedPoller periodically republishes every enabled calendar feed.
+type FeedPoller struct {
+	feeds *FeedService
+	every time.Duration
+	loop  lifecycle.Loop
+}
+
+func NewFeedPoller(feeds *FeedService, every time.Duration) *FeedPoller {
+	if every <= 0 {
+		every = 15 * time.Minute
+	}
+	return &FeedPoller{feeds: feeds, every: every}
+}
+
+func (p *FeedPoller) Start(ctx context.Context) {
+	p.loop.Start(ctx, p.every, func(ctx context.Context) {
+		start := time.Now()
+		if err := p.feeds.PublishAll(ctx); err != nil {
+			log.Printf("reminder feed poller error: %v", err)
+		}
+		metrics.PollerTickSeconds.ObserveSince(start, "reminder_feeds")
+	})
+}
+
+// Stop stops republishing and waits for a publish in progress.
+func (p *FeedPoller) Stop(ctx context.Context) error {
+	return p.loop.Stop(ctx)
+}
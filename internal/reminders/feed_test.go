@@ -0,0 +1,89 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCalendarPublisher struct {
+	objects map[string]string
+	deleted []string
+}
+
+func (p *fakeCalendarPublisher) PublishObject(_ context.Context, name, _ string, body []byte) (string, error) {
+	p.objects[name] = string(body)
+	return "https://feeds.example/" + name, nil
+}
+
+func (p *fakeCalendarPublisher) DeleteObject(_ context.Context, name string) error {
+	delete(p.objects, name)
+	p.deleted = append(p.deleted, name)
+	return nil
+}
+
+func TestFeedServiceEnableRotateDisable(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(openTestDB(t))
+	publisher := &fakeCalendarPublisher{objects: map[string]string{}}
+	feeds := NewFeedService(store, publisher)
+	service := NewService(store)
+	if _, err := service.CreateReminder(ctx, CreateReminderInput{UserID: "u1", ChannelID: "c1", Message: "stretch", Schedule: string(ScheduleDaily), At: "09:00", Timezone: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := feeds.Enable(ctx, "u1", false)
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if again, err := feeds.Enable(ctx, "u1", false); err != nil || again != url {
+		t.Fatalf("second Enable = %q err=%v, want the same URL %q", again, err, url)
+	}
+	name := strings.TrimPrefix(url, "https://feeds.example/")
+	if body := publisher.objects[name]; strings.Count(body, "BEGIN:VEVENT") < 50 || !strings.Contains(body, "SUMMARY:stretch") {
+		t.Fatalf("feed body has %d events:\n%s", strings.Count(body, "BEGIN:VEVENT"), body)
+	}
+
+	rotated, err := feeds.Enable(ctx, "u1", true)
+	if err != nil || rotated == url {
+		t.Fatalf("rotate = %q err=%v, want a new URL", rotated, err)
+	}
+	if _, ok := publisher.objects[name]; ok {
+		t.Fatalf("old feed %s still published after rotate", name)
+	}
+
+	if removed, err := feeds.Disable(ctx, "u1"); err != nil || !removed {
+		t.Fatalf("Disable removed=%v err=%v", removed, err)
+	}
+	if len(publisher.objects) != 0 {
+		t.Fatalf("feeds left after disable: %v", publisher.objects)
+	}
+	if removed, err := feeds.Disable(ctx, "u1"); err != nil || removed {
+		t.Fatalf("second Disable removed=%v err=%v, want false", removed, err)
+	}
+}
+
+func TestEncodeFeedICSExpandsOccurrences(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	list := []Reminder{
+		{ID: 1, Message: "limited", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now.Add(21 * time.Hour), RemainingRuns: sql.NullInt64{Int64: 3, Valid: true}},
+		{ID: 2, Message: "weekly", Schedule: ScheduleWeekly, CronExpr: "0 9 * * 1", Timezone: "UTC", NextRun: time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)},
+		{ID: 3, Message: "paused", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now.Add(21 * time.Hour), Paused: true},
+		{ID: 4, Message: "resumes", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now.Add(21 * time.Hour), Paused: true, PausedUntil: now.Add(50 * 24 * time.Hour)},
+	}
+	body := string(EncodeFeedICS(now, list))
+	counts := map[string]int{}
+	for _, line := range strings.Split(body, "\r\n") {
+		if summary, ok := strings.CutPrefix(line, "SUMMARY:"); ok {
+			counts[summary]++
+		}
+	}
+	if counts["limited"] != 3 || counts["weekly"] != 9 || counts["paused"] != 0 || counts["resumes"] != 10 {
+		t.Fatalf("occurrences = %v, want limited=3 weekly=9 paused=0 resumes=10", counts)
+	}
+	if !strings.Contains(body, "UID:reminder-2-1791190800@mizubot") || !strings.Contains(body, "DTSTART:20261005T090000Z") {
+		t.Fatalf("missing first weekly occurrence:\n%s", body)
+	}
+}
@@ -0,0 +1,760 @@
+package reminders
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportEvents caps how many events one calendar file may create.
+const maxImportEvents = 50
+
+const (
+	icsProdID        = "-//MizuBot//Reminders//EN"
+	icsLocalLayout   = "20060102T150405"
+	icsUTCLayout     = "20060102T150405Z"
+	icsDateLayout    = "20060102"
+	icsMaxLineOctets = 75
+)
+
+var icsWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var errNotCalendar = errors.New("That file isn't an iCalendar (.ics) file.")
+
+// CalendarIssue explains why a reminder or calendar event was not carried
+// over during export or import. Subject names the reminder or event.
+type CalendarIssue struct {
+	Subject string
+	Reason  string
+}
+
+// CalendarExport is an iCalendar file with one VEVENT per exported reminder.
+type CalendarExport struct {
+	Data    []byte
+	Events  int
+	Skipped []CalendarIssue
+}
+
+// CalendarImport reports the reminders created from a calendar file, the
+// events that could not be mapped, and notes about events that were
+// imported with a change in meaning.
+type CalendarImport struct {
+	Created []*Reminder
+	Skipped []CalendarIssue
+	Notes   []CalendarIssue
+}
+
+// ExportICS renders the user's active reminders as an iCalendar file.
+// Reminders that won't run again on their own, and those whose cron
+// expression has no RRULE equivalent, are skipped and reported.
+func (s *Service) ExportICS(ctx context.Context, userID string) (CalendarExport, error) {
+	list, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return CalendarExport{}, err
+	}
+	return EncodeICS(time.Now().UTC(), list), nil
+}
+
+// ImportICS creates a reminder for each VEVENT in an iCalendar file. Every
+// reminder is created through CreateReminder from a copy of base, so base
+// carries the owner, channel, default timezone and delivery settings; the
+// event supplies the message, schedule, and its own timezone when it has
+// one. Only a file that can't be read at all returns an error.
+func (s *Service) ImportICS(ctx context.Context, r io.Reader, base CreateReminderInput) (CalendarImport, error) {
+	events, err := parseICS(r)
+	if err != nil {
+		return CalendarImport{}, err
+	}
+	now := time.Now().UTC()
+	var result CalendarImport
+	for idx, event := range events {
+		subject := event.subject(idx)
+		if idx >= maxImportEvents {
+			result.Skipped = append(result.Skipped, CalendarIssue{subject, fmt.Sprintf("Only the first %d events of a file are imported.", maxImportEvents)})
+			continue
+		}
+		input, note, err := event.reminderInput(now, base)
+		if err != nil {
+			result.Skipped = append(result.Skipped, CalendarIssue{subject, err.Error()})
+			continue
+		}
+		reminder, err := s.CreateReminder(ctx, input)
+		if err != nil {
+			result.Skipped = append(result.Skipped, CalendarIssue{subject, err.Error()})
+			continue
+		}
+		result.Created = append(result.Created, reminder)
+		if note != "" {
+			result.Notes = append(result.Notes, CalendarIssue{subject, note})
+		}
+	}
+	return result, nil
+}
+
+// EncodeICS writes reminders as VEVENTs starting at their next run, or for
+// a reminder paused until a date, at its first run after the pause. The
+// recurrence is derived from the stored cron expression in the reminder's
+// timezone, and end dates and run limits become UNTIL and COUNT. Timezones
+// are referenced by IANA name without VTIMEZONE blocks, which calendar apps
+// resolve themselves. Like the feed, it leaves out dead reminders and those
+// paused without an end date, reporting them as skipped.
+func EncodeICS(now time.Time, list []Reminder) CalendarExport {
+	var out CalendarExport
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:"+icsProdID)
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	for _, r := range list {
+		lines, err := reminderEventLines(now, r)
+		if err != nil {
+			out.Skipped = append(out.Skipped, CalendarIssue{fmt.Sprintf("Reminder %d", r.ID), err.Error()})
+			continue
+		}
+		for _, line := range lines {
+			writeICSLine(&b, line)
+		}
+		out.Events++
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	out.Data = []byte(b.String())
+	return out
+}
+
+func reminderEventLines(now time.Time, r Reminder) ([]string, error) {
+	switch {
+	case r.Dead():
+		return nil, errors.New("It stopped retrying after failed sends and won't run until it is edited.")
+	case r.Paused && r.PausedUntil.IsZero():
+		return nil, errors.New("It is paused with no end date.")
+	}
+	loc, err := time.LoadLocation(timezoneOrUTC(r.Timezone))
+	if err != nil {
+		return nil, fmt.Errorf("Unknown timezone %q.", r.Timezone)
+	}
+	start := r.NextRun
+	if r.Paused {
+		// Runs inside the pause are skipped, and a one-time reminder due
+		// during it fires when the pause ends.
+		start = ResumeNextRun(r, r.PausedUntil)
+		if start.Before(r.PausedUntil) {
+			start = r.PausedUntil
+		}
+		if !r.EndsAt.IsZero() && start.After(r.EndsAt) {
+			return nil, errors.New("It ends before its pause does.")
+		}
+	}
+	lines := []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:reminder-%d@mizubot", r.ID),
+		"DTSTAMP:" + now.UTC().Format(icsUTCLayout),
+		icsDateTime("DTSTART", start, loc),
+	}
+	if !r.Once {
+		rule, err := cronToRRule(r.CronExpr)
+		if err != nil {
+			return nil, err
+		}
+		if !r.EndsAt.IsZero() {
+			rule += ";UNTIL=" + r.EndsAt.UTC().Format(icsUTCLayout)
+		} else if r.RemainingRuns.Valid {
+			rule += ";COUNT=" + strconv.FormatInt(r.RemainingRuns.Int64, 10)
+		}
+		lines = append(lines, "RRULE:"+rule)
+	}
+	lines = append(lines, "SUMMARY:"+escapeICSText(r.Message), "END:VEVENT")
+	return lines, nil
+}
+
+func icsDateTime(name string, t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return name + ":" + t.UTC().Format(icsUTCLayout)
+	}
+	return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format(icsLocalLayout)
+}
+
+// cronToRRule maps the five-field cron expressions the bot creates to an
+// RRULE. DTSTART supplies the first occurrence, so steps such as "5-59/15"
+// only need their interval.
+func cronToRRule(expr string) (string, error) {
+	fields := strings.Fields(expr)
+	unsupported := fmt.Errorf("Cron schedule `%s` has no calendar equivalent.", expr)
+	if len(fields) != 5 {
+		return "", unsupported
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+	if dom != "*" && dow != "*" {
+		// Cron fires when either field matches; RRULE needs both to.
+		return "", unsupported
+	}
+
+	if step, ok := parseCronStep(minute, 60); ok {
+		if hour != "*" || dom != "*" || month != "*" || dow != "*" || 60%step != 0 {
+			return "", unsupported
+		}
+		return fmt.Sprintf("FREQ=MINUTELY;INTERVAL=%d", step), nil
+	}
+	minutes, ok := parseCronValues(minute, 0, 59)
+	if !ok {
+		return "", unsupported
+	}
+	if step, ok := parseCronStep(hour, 24); ok {
+		if dom != "*" || month != "*" || dow != "*" || 24%step != 0 {
+			return "", unsupported
+		}
+		rule := "FREQ=HOURLY"
+		if step > 1 {
+			rule += fmt.Sprintf(";INTERVAL=%d", step)
+		}
+		return rule + ";BYMINUTE=" + joinCronList(minutes), nil
+	}
+	hours, ok := parseCronValues(hour, 0, 23)
+	if !ok {
+		return "", unsupported
+	}
+	byTime := ";BYHOUR=" + joinCronList(hours) + ";BYMINUTE=" + joinCronList(minutes)
+
+	switch {
+	case month != "*":
+		months, ok := parseCronValues(month, 1, 12)
+		days, daysOK := parseCronValues(dom, 1, 31)
+		if !ok || !daysOK || dow != "*" {
+			return "", unsupported
+		}
+		return "FREQ=YEARLY;BYMONTH=" + joinCronList(months) + ";BYMONTHDAY=" + joinCronList(days) + byTime, nil
+	case dow != "*":
+		days, ok := parseCronValues(dow, 0, 7)
+		if !ok {
+			return "", unsupported
+		}
+		var names []string
+		for _, day := range days {
+			name := icsWeekdays[day%7]
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(names, ",") + byTime, nil
+	case dom != "*":
+		days, ok := parseCronValues(dom, 1, 31)
+		if !ok {
+			return "", unsupported
+		}
+		return "FREQ=MONTHLY;BYMONTHDAY=" + joinCronList(days) + byTime, nil
+	default:
+		return "FREQ=DAILY" + byTime, nil
+	}
+}
+
+// parseCronStep matches "*", "*/n" and "a-max/n" with a < n, the step
+// forms whose occurrences are evenly spaced from the first one.
+func parseCronStep(field string, size int) (int, bool) {
+	if field == "*" {
+		return 1, true
+	}
+	span, stepText, ok := strings.Cut(field, "/")
+	if !ok {
+		return 0, false
+	}
+	step, err := strconv.Atoi(stepText)
+	if err != nil || step < 1 || step >= size {
+		return 0, false
+	}
+	if span == "*" {
+		return step, true
+	}
+	startText, endText, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.Atoi(startText)
+	if err != nil || start < 0 || start >= step || endText != strconv.Itoa(size-1) {
+		return 0, false
+	}
+	return step, true
+}
+
+// parseCronValues expands a comma list of numbers and ranges such as
+// "1-5,7".
+func parseCronValues(field string, min, max int) ([]int, bool) {
+	var values []int
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, false
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(hi); err != nil {
+				return nil, false
+			}
+		}
+		if start < min || end > max || start > end {
+			return nil, false
+		}
+		for v := start; v <= end; v++ {
+			values = append(values, v)
+		}
+	}
+	return values, true
+}
+
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+func unescapeICSText(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
+
+// writeICSLine folds a content line at 75 octets without splitting a UTF-8
+// sequence and terminates it with CRLF.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+type icsEvent []icsProperty
+
+func (e icsEvent) get(name string) (icsProperty, bool) {
+	for _, p := range e {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+func (e icsEvent) subject(idx int) string {
+	if p, ok := e.get("SUMMARY"); ok && strings.TrimSpace(p.Value) != "" {
+		return strings.TrimSpace(unescapeICSText(p.Value))
+	}
+	if p, ok := e.get("UID"); ok && p.Value != "" {
+		return p.Value
+	}
+	return fmt.Sprintf("Event %d", idx+1)
+}
+
+// parseICS returns the top-level properties of every VEVENT in r. Nested
+// components such as VALARM are ignored.
+func parseICS(r io.Reader) ([]icsEvent, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errNotCalendar
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, errNotCalendar
+	}
+
+	var (
+		events  []icsEvent
+		current icsEvent
+		inEvent bool
+		nested  int
+	)
+	for _, line := range lines {
+		prop, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+		value := strings.ToUpper(prop.Value)
+		switch {
+		case prop.Name == "BEGIN" && value == "VEVENT" && !inEvent:
+			inEvent, current = true, nil
+		case prop.Name == "END" && value == "VEVENT" && inEvent && nested == 0:
+			inEvent = false
+			events = append(events, current)
+		case !inEvent:
+		case prop.Name == "BEGIN":
+			nested++
+		case prop.Name == "END":
+			nested--
+		case nested == 0:
+			current = append(current, prop)
+		}
+	}
+	return events, nil
+}
+
+func parseICSLine(line string) (icsProperty, bool) {
+	quoted := false
+	colon := -1
+	for idx, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = idx
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, false
+	}
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{Name: strings.ToUpper(parts[0]), Value: line[colon+1:], Params: map[string]string{}}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, true
+}
+
+// reminderInput maps the event onto a copy of base. The note is set when
+// the reminder will behave differently from the calendar event.
+func (e icsEvent) reminderInput(now time.Time, base CreateReminderInput) (CreateReminderInput, string, error) {
+	input := base
+	summary, _ := e.get("SUMMARY")
+	input.Message = strings.TrimSpace(unescapeICSText(summary.Value))
+	if input.Message == "" {
+		return input, "", errors.New("The event has no title to use as the reminder message.")
+	}
+	for _, name := range []string{"RDATE", "EXDATE", "EXRULE"} {
+		if _, ok := e.get(name); ok {
+			return input, "", fmt.Errorf("Extra or excluded dates (%s) aren't supported.", name)
+		}
+	}
+	start, ok := e.get("DTSTART")
+	if !ok {
+		return input, "", errors.New("The event has no start time.")
+	}
+	if strings.EqualFold(start.Params["VALUE"], "DATE") || len(start.Value) == len(icsDateLayout) {
+		return input, "", errors.New("All-day events have no time of day to remind at.")
+	}
+	loc, err := icsLocation(start, base.Timezone)
+	if err != nil {
+		return input, "", err
+	}
+	startAt, err := time.ParseInLocation(icsLocalLayout, strings.TrimSuffix(start.Value, "Z"), loc)
+	if err != nil {
+		return input, "", fmt.Errorf("Unreadable start time %q.", start.Value)
+	}
+	input.Timezone = loc.String()
+
+	rule, ok := e.get("RRULE")
+	if !ok {
+		input.Schedule, input.At = string(ScheduleOnce), startAt.Format(time.RFC3339)
+		return input, "", nil
+	}
+	if err := applyRRule(&input, rule.Value, startAt, loc); err != nil {
+		return input, "", err
+	}
+	if input.RemainingRuns > 0 && startAt.Before(now) {
+		count := input.RemainingRuns
+		passed, err := pastOccurrences(input, startAt, now)
+		if err != nil {
+			return input, "", err
+		}
+		if passed >= count {
+			return input, "", fmt.Errorf("All %d occurrences are in the past.", count)
+		}
+		input.RemainingRuns = count - passed
+		if passed > 0 {
+			return input, fmt.Sprintf("%d of its %d occurrences have already passed.", passed, count), nil
+		}
+		return input, "", nil
+	}
+	if startAt.After(now) {
+		return input, fmt.Sprintf("Repeats from its next occurrence instead of starting on %s.", startAt.Format("2006-01-02")), nil
+	}
+	return input, "", nil
+}
+
+// pastOccurrences counts the runs of input's schedule from start, which is
+// the first, up to now. It stops counting at input.RemainingRuns.
+func pastOccurrences(input CreateReminderInput, start, now time.Time) (int64, error) {
+	normalized, err := normalizeSchedule(start, normalizeInput{
+		Schedule:   Schedule(input.Schedule),
+		At:         input.At,
+		CronExpr:   input.CronExpr,
+		Timezone:   input.Timezone,
+		Weekdays:   input.Weekdays,
+		DayOfMonth: input.DayOfMonth,
+		Every:      input.Every,
+	})
+	if err != nil {
+		return 0, err
+	}
+	r := Reminder{Schedule: Schedule(input.Schedule), CronExpr: normalized.CronExpr, Timezone: normalized.Timezone, AtTime: normalized.AtTime}
+	var passed int64
+	for run := start; run.Before(now) && passed < input.RemainingRuns; passed++ {
+		next, repeat, err := NextAfter(r, run)
+		if err != nil {
+			return 0, err
+		}
+		if !repeat {
+			return passed + 1, nil
+		}
+		run = next
+	}
+	return passed, nil
+}
+
+func icsLocation(p icsProperty, fallback string) (*time.Location, error) {
+	if strings.HasSuffix(p.Value, "Z") {
+		return time.UTC, nil
+	}
+	name := p.Params["TZID"]
+	if name == "" {
+		// Floating time: read it in the importing user's timezone.
+		name = timezoneOrUTC(fallback)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown timezone %q.", name)
+	}
+	return loc, nil
+}
+
+// applyRRule maps an RRULE onto the matching schedule: hourly, daily,
+// weekly and monthly rules with a single time of day use those schedules,
+// and the rest become cron expressions.
+func applyRRule(input *CreateReminderInput, value string, start time.Time, loc *time.Location) error {
+	parts := map[string]string{}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(key)] = strings.ToUpper(v)
+	}
+	for key, v := range parts {
+		switch key {
+		case "FREQ", "INTERVAL", "UNTIL", "COUNT", "BYMINUTE", "BYHOUR", "BYDAY", "BYMONTHDAY", "BYMONTH", "WKST":
+		case "BYSECOND":
+			if v != "0" {
+				return errors.New("Repeating at specific seconds isn't supported.")
+			}
+		default:
+			return fmt.Errorf("Repeat rules using %s aren't supported.", key)
+		}
+	}
+
+	interval := 1
+	if v, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("Invalid repeat interval %q.", v)
+		}
+		interval = n
+	}
+	minutes, err := rruleValues(parts, "BYMINUTE", 0, 59, start.Minute())
+	if err != nil {
+		return err
+	}
+	hours, err := rruleValues(parts, "BYHOUR", 0, 23, start.Hour())
+	if err != nil {
+		return err
+	}
+	weekdays, err := rruleWeekdays(parts["BYDAY"])
+	if err != nil {
+		return err
+	}
+	var monthDays []int
+	if parts["BYMONTHDAY"] != "" {
+		if monthDays, err = rruleValues(parts, "BYMONTHDAY", 1, 31, 0); err != nil {
+			return err
+		}
+	}
+	freq := parts["FREQ"]
+	switch freq {
+	case "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return errors.New("The repeat rule has no FREQ.")
+	default:
+		return fmt.Errorf("Repeating every %s isn't supported.", rruleUnit(freq))
+	}
+	if freq != "YEARLY" && parts["BYMONTH"] != "" {
+		return errors.New("Limiting a repeat to certain months is only supported for yearly events.")
+	}
+	if (freq == "MINUTELY" || freq == "HOURLY") && (parts["BYHOUR"] != "" || weekdays != nil || monthDays != nil) {
+		return fmt.Errorf("FREQ=%s rules can't be limited to certain hours or days.", freq)
+	}
+	if freq != "MINUTELY" && freq != "HOURLY" && interval != 1 {
+		return fmt.Errorf("Repeating every %d %s isn't supported; reminders repeat on every matching day.", interval, rruleUnit(freq))
+	}
+
+	single := len(minutes) == 1 && len(hours) == 1
+	switch freq {
+	case "MINUTELY":
+		if parts["BYMINUTE"] != "" || 60%interval != 0 {
+			return errors.New("Minute repeats must divide an hour evenly, e.g. every 5, 15, or 30 minutes.")
+		}
+		if offset := start.Minute() % interval; offset == 0 {
+			input.Schedule, input.Every = string(ScheduleInterval), fmt.Sprintf("%dm", interval)
+		} else {
+			input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%d-59/%d * * * *", offset, interval)
+		}
+	case "HOURLY":
+		if 24%interval != 0 {
+			return errors.New("Hour repeats must divide a day evenly, e.g. every 2, 6, or 12 hours.")
+		}
+		switch {
+		case interval == 1 && len(minutes) == 1:
+			input.Schedule, input.At = string(ScheduleHourly), fmt.Sprintf(":%02d", minutes[0])
+		case interval == 1:
+			input.Schedule, input.CronExpr = string(ScheduleCron), joinCronList(minutes)+" * * * *"
+		default:
+			input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%s %d-23/%d * * *", joinCronList(minutes), start.Hour()%interval, interval)
+		}
+	case "DAILY", "WEEKLY":
+		if freq == "WEEKLY" && weekdays == nil {
+			weekdays = []int{int(start.Weekday())}
+		}
+		if weekdays != nil && monthDays != nil {
+			return errors.New("Rules limited to both weekdays and days of the month aren't supported.")
+		}
+		switch {
+		case weekdays != nil && single:
+			names := make([]string, len(weekdays))
+			for idx, day := range weekdays {
+				names[idx] = strings.ToLower(time.Weekday(day).String()[:3])
+			}
+			input.Schedule, input.At, input.Weekdays = string(ScheduleWeekly), fmt.Sprintf("%02d:%02d", hours[0], minutes[0]), strings.Join(names, ",")
+		case weekdays != nil:
+			input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%s %s * * %s", joinCronList(minutes), joinCronList(hours), joinCronList(weekdays))
+		case monthDays != nil:
+			input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%s %s %s * *", joinCronList(minutes), joinCronList(hours), joinCronList(monthDays))
+		case single:
+			input.Schedule, input.At = string(ScheduleDaily), fmt.Sprintf("%02d:%02d", hours[0], minutes[0])
+		default:
+			input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%s %s * * *", joinCronList(minutes), joinCronList(hours))
+		}
+	case "MONTHLY", "YEARLY":
+		if weekdays != nil {
+			return errors.New("Weekday rules such as \"second Monday\" aren't supported for monthly or yearly events.")
+		}
+		if monthDays == nil {
+			monthDays = []int{start.Day()}
+		}
+		if freq == "MONTHLY" && single && len(monthDays) == 1 {
+			input.Schedule, input.At, input.DayOfMonth = string(ScheduleMonthly), fmt.Sprintf("%02d:%02d", hours[0], minutes[0]), monthDays[0]
+			break
+		}
+		months := "*"
+		if freq == "YEARLY" {
+			values, err := rruleValues(parts, "BYMONTH", 1, 12, int(start.Month()))
+			if err != nil {
+				return err
+			}
+			months = joinCronList(values)
+		}
+		input.Schedule, input.CronExpr = string(ScheduleCron), fmt.Sprintf("%s %s %s %s *", joinCronList(minutes), joinCronList(hours), joinCronList(monthDays), months)
+	}
+
+	if v, ok := parts["UNTIL"]; ok {
+		until, err := rruleUntil(v, loc)
+		if err != nil {
+			return err
+		}
+		input.EndsAt = until
+	}
+	if v, ok := parts["COUNT"]; ok {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			return fmt.Errorf("Invalid repeat count %q.", v)
+		}
+		input.RemainingRuns = n
+	}
+	return nil
+}
+
+// rruleValues parses a numeric BY* list, or returns def when the part is
+// absent.
+func rruleValues(parts map[string]string, key string, min, max, def int) ([]int, error) {
+	v, ok := parts[key]
+	if !ok {
+		return []int{def}, nil
+	}
+	var values []int
+	for _, field := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < min || n > max {
+			return nil, fmt.Errorf("%s=%s isn't supported.", key, v)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func rruleWeekdays(v string) ([]int, error) {
+	if v == "" {
+		return nil, nil
+	}
+	var days []int
+	for _, field := range strings.Split(v, ",") {
+		day := slices.Index(icsWeekdays, field)
+		if day < 0 {
+			return nil, fmt.Errorf("BYDAY=%s isn't supported; use plain weekdays like MO,WE,FR.", v)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// rruleUntil converts UNTIL to the EndsAt forms accepted by ParseEndsAt. A
+// bare date stays a date so the whole day is included.
+func rruleUntil(v string, loc *time.Location) (string, error) {
+	if t, err := time.Parse(icsDateLayout, v); err == nil {
+		return t.Format("2006-01-02"), nil
+	}
+	if t, err := time.Parse(icsUTCLayout, v); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+	if t, err := time.ParseInLocation(icsLocalLayout, v, loc); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("Unreadable repeat end %q.", v)
+}
+
+func rruleUnit(freq string) string {
+	switch freq {
+	case "SECONDLY":
+		return "second"
+	case "MINUTELY":
+		return "minutes"
+	case "HOURLY":
+		return "hours"
+	case "DAILY":
+		return "days"
+	case "WEEKLY":
+		return "weeks"
+	case "MONTHLY":
+		return "months"
+	case "YEARLY":
+		return "years"
+	}
+	return strings.ToLower(freq)
+}
@@ -0,0 +1,180 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronToRRule(t *testing.T) {
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"15 * * * *", "FREQ=HOURLY;BYMINUTE=15"},
+		{"0 */6 * * *", "FREQ=HOURLY;INTERVAL=6;BYMINUTE=0"},
+		{"*/15 * * * *", "FREQ=MINUTELY;INTERVAL=15"},
+		{"30 9 * * *", "FREQ=DAILY;BYHOUR=9;BYMINUTE=30"},
+		{"30 8 * * 1-5", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=8;BYMINUTE=30"},
+		{"0 9 1,15 * *", "FREQ=MONTHLY;BYMONTHDAY=1,15;BYHOUR=9;BYMINUTE=0"},
+		{"0 9 25 12 *", "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25;BYHOUR=9;BYMINUTE=0"},
+	}
+	for _, tc := range cases {
+		got, err := cronToRRule(tc.expr)
+		if err != nil || got != tc.want {
+			t.Errorf("cronToRRule(%q) = %q, %v; want %q", tc.expr, got, err, tc.want)
+		}
+	}
+	for _, expr := range []string{"0 9 1 * 1", "*/7 * * * *", "0 9 * * MON", "0 9-17/2 * * *"} {
+		if _, err := cronToRRule(expr); err == nil {
+			t.Errorf("cronToRRule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestEncodeICS(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	list := []Reminder{
+		{ID: 1, Message: "stand-up; bring notes, please", Schedule: ScheduleWeekly, CronExpr: "30 8 * * 1-5", Timezone: "Europe/Berlin", NextRun: time.Date(2026, 10, 2, 6, 30, 0, 0, time.UTC), RemainingRuns: sql.NullInt64{Int64: 10, Valid: true}},
+		{ID: 2, Message: "dentist", Schedule: ScheduleOnce, Once: true, Timezone: "UTC", NextRun: time.Date(2026, 10, 5, 14, 0, 0, 0, time.UTC)},
+		{ID: 3, Message: "odd", Schedule: ScheduleCron, CronExpr: "0 9 1 * 1", Timezone: "UTC", NextRun: now},
+		{ID: 4, Message: "failing", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now.Add(-48 * time.Hour), DeadAt: now.Add(-time.Hour)},
+		{ID: 5, Message: "on hold", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now.Add(-48 * time.Hour), Paused: true},
+		// Paused until Oct 10 12:00 UTC: the next run is Oct 11 09:00.
+		{ID: 6, Message: "vacation", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC),
+			Paused: true, PausedUntil: time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)},
+	}
+	out := EncodeICS(now, list)
+	skipped := make([]string, 0, len(out.Skipped))
+	for _, issue := range out.Skipped {
+		skipped = append(skipped, issue.Subject)
+	}
+	if out.Events != 3 || !slices.Equal(skipped, []string{"Reminder 3", "Reminder 4", "Reminder 5"}) {
+		t.Fatalf("events=%d skipped=%+v, want 3 events and reminders 3, 4 and 5 skipped", out.Events, out.Skipped)
+	}
+	data := string(out.Data)
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Berlin:20261002T083000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=8;BYMINUTE=30;COUNT=10\r\n",
+		`SUMMARY:stand-up\; bring notes\, please`,
+		"DTSTART:20261005T140000Z\r\n",
+		"DTSTART:20261011T090000Z\r\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("export is missing %q:\n%s", want, data)
+		}
+	}
+	for _, line := range strings.Split(data, "\r\n") {
+		if len(line) > icsMaxLineOctets {
+			t.Errorf("line longer than %d octets: %q", icsMaxLineOctets, line)
+		}
+	}
+}
+
+func TestImportICS(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	next := time.Now().UTC().Add(48 * time.Hour).Format(icsUTCLayout)
+	// Four daily runs, the last half an hour ago, have already passed.
+	started := time.Now().UTC().Add(-72*time.Hour - 30*time.Minute).Truncate(time.Minute).Format(icsUTCLayout)
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"SUMMARY:Water the plants",
+		"DTSTART;TZID=Europe/Berlin:20260105T083000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,TH",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Pay rent",
+		"DTSTART:20990101T090000Z",
+		"RRULE:FREQ=MONTHLY;COUNT=12",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Take antibiotics",
+		"DTSTART:" + started,
+		"RRULE:FREQ=DAILY;COUNT=10",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Finished course",
+		"DTSTART:20260101T090000Z",
+		"RRULE:FREQ=DAILY;COUNT=5",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Dentist",
+		"DTSTART:" + next,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Team sync every other week",
+		"DTSTART:20260101T090000Z",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Holiday",
+		"DTSTART;VALUE=DATE:20261224",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	base := CreateReminderInput{UserID: "u1", ChannelID: "c1", Timezone: "UTC"}
+	result, err := service.ImportICS(ctx, strings.NewReader(calendar), base)
+	if err != nil {
+		t.Fatalf("ImportICS: %v", err)
+	}
+	if len(result.Created) != 4 || len(result.Skipped) != 3 {
+		t.Fatalf("created=%d skipped=%+v, want 4 created and 3 skipped", len(result.Created), result.Skipped)
+	}
+	plants, rent, antibiotics, dentist := result.Created[0], result.Created[1], result.Created[2], result.Created[3]
+	if plants.Schedule != ScheduleWeekly || plants.CronExpr != "30 8 * * 1,4" || plants.Timezone != "Europe/Berlin" {
+		t.Errorf("plants = %s %q %s, want weekly 30 8 * * 1,4 in Europe/Berlin", plants.Schedule, plants.CronExpr, plants.Timezone)
+	}
+	if rent.Schedule != ScheduleMonthly || rent.CronExpr != "0 9 1 * *" || rent.RemainingRuns.Int64 != 12 {
+		t.Errorf("rent = %s %q runs=%v, want monthly on the 1st with 12 runs", rent.Schedule, rent.CronExpr, rent.RemainingRuns)
+	}
+	if antibiotics.RemainingRuns.Int64 != 6 {
+		t.Errorf("antibiotics runs = %v, want the 6 of 10 not yet passed", antibiotics.RemainingRuns)
+	}
+	if !dentist.Once || dentist.NextRun.Format(icsUTCLayout) != next {
+		t.Errorf("dentist = once=%v next=%s, want one-time at %s", dentist.Once, dentist.NextRun, next)
+	}
+	if result.Skipped[0].Subject != "Finished course" || result.Skipped[1].Subject != "Team sync every other week" || result.Skipped[2].Subject != "Holiday" {
+		t.Errorf("skipped = %+v", result.Skipped)
+	}
+
+	if _, err := service.ImportICS(ctx, strings.NewReader("not a calendar"), base); err == nil {
+		t.Fatalf("expected error for a file that isn't a calendar")
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	input := CreateReminderInput{UserID: "u1", ChannelID: "c1", Message: "stretch", Schedule: string(ScheduleCron), CronExpr: "0 9,13,17 * * 1-5", Timezone: "Asia/Tokyo"}
+	original, err := service.CreateReminder(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := service.ExportICS(ctx, "u1")
+	if err != nil || exported.Events != 1 {
+		t.Fatalf("export events=%d err=%v", exported.Events, err)
+	}
+	imported, err := service.ImportICS(ctx, strings.NewReader(string(exported.Data)), CreateReminderInput{UserID: "u2", ChannelID: "c1"})
+	if err != nil || len(imported.Created) != 1 {
+		t.Fatalf("import = %+v err=%v", imported, err)
+	}
+	got := imported.Created[0]
+	if got.Timezone != input.Timezone || got.Message != input.Message {
+		t.Fatalf("round trip = %s %q, want %s %q", got.Timezone, got.Message, input.Timezone, input.Message)
+	}
+	want, _ := UpcomingRuns(*original, 10)
+	runs, err := UpcomingRuns(*got, 10)
+	if err != nil || !slices.Equal(runs, want) {
+		t.Fatalf("round trip runs = %v err=%v, want %v (cron %q)", runs, err, want, got.CronExpr)
+	}
+}
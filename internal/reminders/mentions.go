@@ -0,0 +1,88 @@
+package reminders
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MentionTarget is who a delivered reminder pings. The zero value pings
+// only the reminder's owner.
+type MentionTarget struct {
+	Users []string
+	Roles []string
+	Here  bool
+}
+
+// maxMentionTargets keeps a reminder's ping list well under Discord's
+// allowed_mentions limit of 100 ids per kind.
+const maxMentionTargets = 25
+
+// ParseMentionTarget parses Discord mention text such as
+// "<@123> <@&456> @here". Users, roles and @here are supported; @everyone
+// is not.
+func ParseMentionTarget(s string) (MentionTarget, error) {
+	var target MentionTarget
+	for _, token := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch {
+		case token == "@here":
+			target.Here = true
+		case token == "@everyone":
+			return MentionTarget{}, fmt.Errorf("Reminders can't mention @everyone. Use @here or a role.")
+		case strings.HasPrefix(token, "<@&") && strings.HasSuffix(token, ">"):
+			id := token[3 : len(token)-1]
+			if !isSnowflake(id) {
+				return MentionTarget{}, fmt.Errorf("Invalid role mention %q.", token)
+			}
+			if !slices.Contains(target.Roles, id) {
+				target.Roles = append(target.Roles, id)
+			}
+		case strings.HasPrefix(token, "<@") && strings.HasSuffix(token, ">"):
+			id := strings.TrimPrefix(token[2:len(token)-1], "!")
+			if !isSnowflake(id) {
+				return MentionTarget{}, fmt.Errorf("Invalid user mention %q.", token)
+			}
+			if !slices.Contains(target.Users, id) {
+				target.Users = append(target.Users, id)
+			}
+		default:
+			return MentionTarget{}, fmt.Errorf("Unknown mention %q. Use @user, @role, or @here.", token)
+		}
+	}
+	if len(target.Users)+len(target.Roles) > maxMentionTargets {
+		return MentionTarget{}, fmt.Errorf("A reminder can mention at most %d users and roles.", maxMentionTargets)
+	}
+	return target, nil
+}
+
+// IsZero reports whether the target is empty, meaning the owner is pinged.
+func (m MentionTarget) IsZero() bool {
+	return len(m.Users) == 0 && len(m.Roles) == 0 && !m.Here
+}
+
+// String renders the target as mention text, which is also how it is stored.
+func (m MentionTarget) String() string {
+	parts := make([]string, 0, len(m.Users)+len(m.Roles)+1)
+	for _, id := range m.Users {
+		parts = append(parts, "<@"+id+">")
+	}
+	for _, id := range m.Roles {
+		parts = append(parts, "<@&"+id+">")
+	}
+	if m.Here {
+		parts = append(parts, "@here")
+	}
+	return strings.Join(parts, " ")
+}
+
+func isSnowflake(id string) bool {
+	if id == "" || len(id) > 20 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package reminders
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestParseMentionTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MentionTarget
+		wantErr bool
+	}{
+		{in: "", want: MentionTarget{}},
+		{in: "<@123> <@!456>", want: MentionTarget{Users: []string{"123", "456"}}},
+		{in: "<@&789>, @here <@123> <@123>", want: MentionTarget{Users: []string{"123"}, Roles: []string{"789"}, Here: true}},
+		{in: "@everyone", wantErr: true},
+		{in: "<@abc>", wantErr: true},
+		{in: "bob", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMentionTarget(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("ParseMentionTarget(%q) = %+v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got.Users, tt.want.Users) || !slices.Equal(got.Roles, tt.want.Roles) || got.Here != tt.want.Here {
+			t.Fatalf("ParseMentionTarget(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateReminderStoresMentionTarget(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	target := MentionTarget{Users: []string{"1"}, Roles: []string{"2"}, Here: true}
+	input := CreateReminderInput{UserID: "u1", ChannelID: "c1", GuildID: "g1", Message: "standup", Schedule: string(ScheduleDaily), At: "09:00", Timezone: "UTC", Mentions: target}
+	if _, err := service.CreateReminder(ctx, input); err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+	list, err := service.ListUserReminders(ctx, "u1")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list = %+v err=%v", list, err)
+	}
+	if got := list[0].Mentions.String(); got != "<@1> <@&2> @here" {
+		t.Fatalf("mentions = %q, want <@1> <@&2> @here", got)
+	}
+
+	input.GuildID = ""
+	if _, err := service.CreateReminder(ctx, input); err == nil {
+		t.Fatalf("expected error for role mentions outside a server")
+	}
+}
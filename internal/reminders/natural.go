@@ -0,0 +1,474 @@
+package reminders
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NaturalTime is a parsed natural-language time phrase: either a single
+// instant (Once) or a five-field cron expression in the phrase's timezone.
+type NaturalTime struct {
+	Once     bool
+	RunAt    time.Time
+	CronExpr string
+}
+
+// defaultNaturalHour is used when a phrase names a day but no time, e.g.
+// "tomorrow" or "on the 1st of every month".
+const defaultNaturalHour = 9
+
+var errNaturalTime = errors.New("I couldn't understand that time. Try phrases like 'tomorrow 9am', 'next friday 18:00' (Friday of next week), 'in 2 hours 30 minutes', 'every weekday at 8:30', or 'on the 1st of every month'.")
+
+var naturalWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var naturalMonths = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// ParseNaturalTime parses an English time phrase without any LLM help.
+// One-time phrases ("tomorrow 9am", "next friday 18:00", "in 2 hours 30
+// minutes", "dec 25 at noon") resolve to an instant after now in timezone.
+// "next friday" means Friday of next week; "friday" or "this friday" means
+// the coming one.
+// Phrases with "every", "each", "daily" or "monthly" ("every weekday at
+// 8:30", "on the 1st of every month", "every 15 minutes") become a cron
+// expression. A day without a time means 09:00.
+func ParseNaturalTime(now time.Time, phrase string, timezone string) (NaturalTime, error) {
+	loc, err := time.LoadLocation(timezoneOrUTC(strings.TrimSpace(timezone)))
+	if err != nil {
+		return NaturalTime{}, errors.New("invalid timezone")
+	}
+	words := naturalWords(phrase)
+	if len(words) == 0 {
+		return NaturalTime{}, errNaturalTime
+	}
+	for _, w := range words {
+		if w == "every" || w == "each" || w == "daily" || w == "monthly" {
+			expr, err := parseNaturalRecurring(words)
+			if err != nil {
+				return NaturalTime{}, err
+			}
+			return NaturalTime{CronExpr: expr}, nil
+		}
+	}
+	runAt, err := parseNaturalOnce(now.In(loc), words)
+	if err != nil {
+		return NaturalTime{}, err
+	}
+	if !runAt.After(now) {
+		return NaturalTime{}, errors.New("That time is in the past.")
+	}
+	return NaturalTime{Once: true, RunAt: runAt.UTC()}, nil
+}
+
+// naturalWords lowercases a phrase, splits it into words, and drops filler
+// words that never change its meaning.
+func naturalWords(phrase string) []string {
+	phrase = strings.ToLower(phrase)
+	phrase = strings.NewReplacer(",", " ", ";", " ", "o'clock", "").Replace(phrase)
+	var words []string
+	for _, w := range strings.Fields(phrase) {
+		switch w {
+		case "at", "on", "the", "of", "and", "&", "from", "now":
+			continue
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+func parseNaturalOnce(now time.Time, words []string) (time.Time, error) {
+	if words[0] == "in" {
+		return parseNaturalDuration(now, words[1:])
+	}
+
+	var (
+		date         time.Time // midnight of the chosen day; zero if unset
+		weekday      = time.Weekday(-1)
+		nextWeek     bool
+		hour, minute = -1, 0
+		defaultHour  = defaultNaturalHour
+	)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	setDate := func(d time.Time) error {
+		if !date.IsZero() || weekday >= 0 {
+			return errNaturalTime
+		}
+		date = d
+		return nil
+	}
+
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case w == "today":
+			if err := setDate(today); err != nil {
+				return time.Time{}, err
+			}
+		case w == "tonight":
+			if err := setDate(today); err != nil {
+				return time.Time{}, err
+			}
+			defaultHour = 20
+		case w == "tomorrow":
+			if err := setDate(today.AddDate(0, 0, 1)); err != nil {
+				return time.Time{}, err
+			}
+		case w == "next" || w == "this":
+			if i+1 >= len(words) {
+				return time.Time{}, errNaturalTime
+			}
+			if _, ok := naturalWeekdays[words[i+1]]; !ok {
+				return time.Time{}, errNaturalTime
+			}
+			nextWeek = w == "next"
+		case isNaturalWeekday(w):
+			if !date.IsZero() || weekday >= 0 {
+				return time.Time{}, errNaturalTime
+			}
+			weekday = naturalWeekdays[w]
+		default:
+			if d, err := time.ParseInLocation("2006-01-02", w, now.Location()); err == nil {
+				if err := setDate(d); err != nil {
+					return time.Time{}, err
+				}
+				continue
+			}
+			if d, n, ok := parseNaturalMonthDay(now, words[i:]); ok {
+				if err := setDate(d); err != nil {
+					return time.Time{}, err
+				}
+				i += n - 1
+				continue
+			}
+			h, m, n, ok := parseNaturalClock(words[i:])
+			if !ok || hour >= 0 {
+				return time.Time{}, errNaturalTime
+			}
+			hour, minute = h, m
+			i += n - 1
+		}
+	}
+
+	if weekday >= 0 && nextWeek {
+		// "next friday" is Friday of next week, with weeks starting on
+		// Monday, even when this week's Friday is still ahead.
+		date = today.AddDate(0, 0, 7-mondayIndex(today.Weekday())+mondayIndex(weekday))
+	} else if weekday >= 0 {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		date = today.AddDate(0, 0, days)
+		if days == 0 && hour >= 0 && !time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location()).After(now) {
+			date = date.AddDate(0, 0, 7)
+		}
+	}
+	if date.IsZero() {
+		if hour < 0 {
+			return time.Time{}, errNaturalTime
+		}
+		// A bare time means its next occurrence.
+		date = today
+		if !time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location()).After(now) {
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+	if hour < 0 {
+		hour = defaultHour
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location()), nil
+}
+
+// mondayIndex numbers weekdays from Monday (0) to Sunday (6).
+func mondayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+// parseNaturalDuration parses the words after "in", e.g. "2 hours 30
+// minutes", "an hour", or "10m".
+func parseNaturalDuration(now time.Time, words []string) (time.Time, error) {
+	if len(words) == 0 {
+		return time.Time{}, errNaturalTime
+	}
+	t := now
+	for i := 0; i < len(words); i++ {
+		if d, ok := parseRelativeDuration(words[i]); ok {
+			t = t.Add(d)
+			continue
+		}
+		n, err := strconv.Atoi(words[i])
+		if words[i] == "a" || words[i] == "an" {
+			n, err = 1, nil
+		}
+		if err != nil || n <= 0 || i+1 >= len(words) {
+			return time.Time{}, errNaturalTime
+		}
+		i++
+		switch strings.TrimSuffix(words[i], "s") {
+		case "sec", "second":
+			t = t.Add(time.Duration(n) * time.Second)
+		case "min", "minute":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "hr", "hour":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "day":
+			t = t.AddDate(0, 0, n)
+		case "week":
+			t = t.AddDate(0, 0, 7*n)
+		case "month":
+			t = t.AddDate(0, n, 0)
+		default:
+			return time.Time{}, errNaturalTime
+		}
+	}
+	return t, nil
+}
+
+// parseNaturalMonthDay parses "dec 25", "december 25th" or "25th december"
+// at the start of words. Without a year it picks the next such date.
+func parseNaturalMonthDay(now time.Time, words []string) (time.Time, int, bool) {
+	if len(words) < 2 {
+		return time.Time{}, 0, false
+	}
+	month, ok := naturalMonths[words[0]]
+	day, dayOK := parseNaturalDayOfMonth(words[1])
+	if !ok || !dayOK {
+		month, ok = naturalMonths[words[1]]
+		day, dayOK = parseNaturalDayOfMonth(words[0])
+		if !ok || !dayOK {
+			return time.Time{}, 0, false
+		}
+	}
+	n := 2
+	year := now.Year()
+	if len(words) > 2 {
+		if y, err := strconv.Atoi(words[2]); err == nil && y >= 1000 {
+			year = y
+			n = 3
+		}
+	}
+	d := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	if d.Day() != day {
+		return time.Time{}, 0, false
+	}
+	if n == 2 && d.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, n, true
+}
+
+// parseNaturalDayOfMonth parses "1st", "22nd", "3rd", "15th" or "15".
+func parseNaturalDayOfMonth(w string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		w = strings.TrimSuffix(w, suffix)
+	}
+	day, err := strconv.Atoi(w)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
+
+// parseNaturalClock parses a time of day at the start of words: "9",
+// "9am", "9 pm", "9:30", "18:00", "noon" or "midnight". It returns how many
+// words it used.
+func parseNaturalClock(words []string) (hour, minute, n int, ok bool) {
+	if len(words) == 0 {
+		return 0, 0, 0, false
+	}
+	w := words[0]
+	switch w {
+	case "noon", "midday":
+		return 12, 0, 1, true
+	case "midnight":
+		return 0, 0, 1, true
+	}
+	n = 1
+	suffix := ""
+	for _, s := range []string{"am", "pm", "a.m.", "p.m."} {
+		if before, found := strings.CutSuffix(w, s); found && before != "" {
+			w, suffix = before, s[:1]
+			break
+		}
+	}
+	if suffix == "" && len(words) > 1 {
+		switch words[1] {
+		case "am", "a.m.":
+			suffix, n = "a", 2
+		case "pm", "p.m.":
+			suffix, n = "p", 2
+		}
+	}
+
+	hourPart, minutePart, hasMinutes := strings.Cut(w, ":")
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil || len(hourPart) > 2 {
+		return 0, 0, 0, false
+	}
+	if hasMinutes {
+		if len(minutePart) != 2 {
+			return 0, 0, 0, false
+		}
+		minute, err = strconv.Atoi(minutePart)
+		if err != nil || minute > 59 {
+			return 0, 0, 0, false
+		}
+	}
+	switch suffix {
+	case "a", "p":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		hour %= 12
+		if suffix == "p" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, 0, false
+		}
+	}
+	return hour, minute, n, true
+}
+
+// naturalIntervalUnit returns "minute" or "hour" for interval words such as
+// "mins", "hours" or "hourly", and "" otherwise.
+func naturalIntervalUnit(w string) string {
+	switch w {
+	case "minute", "minutes", "min", "mins":
+		return "minute"
+	case "hour", "hours", "hr", "hrs", "hourly":
+		return "hour"
+	}
+	return ""
+}
+
+func isNaturalWeekday(w string) bool {
+	_, ok := naturalWeekdays[w]
+	return ok
+}
+
+// parseNaturalRecurring turns a recurring phrase into a cron expression.
+func parseNaturalRecurring(words []string) (string, error) {
+	var (
+		weekdays     []int
+		daysOfMonth  []int
+		monthly      bool
+		daily        bool
+		interval     int
+		intervalUnit string
+		hour, minute = -1, 0
+	)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case w == "every" || w == "each":
+		case w == "day" || w == "daily":
+			daily = true
+		case w == "month" || w == "monthly":
+			monthly = true
+		case w == "weekday" || w == "weekdays":
+			weekdays = append(weekdays, 1, 2, 3, 4, 5)
+		case w == "weekend" || w == "weekends":
+			weekdays = append(weekdays, 0, 6)
+		case isNaturalWeekday(strings.TrimSuffix(w, "s")):
+			weekdays = append(weekdays, int(naturalWeekdays[strings.TrimSuffix(w, "s")]))
+		case naturalIntervalUnit(w) != "":
+			if intervalUnit != "" {
+				return "", errNaturalTime
+			}
+			interval, intervalUnit = max(interval, 1), naturalIntervalUnit(w)
+		default:
+			if day, ok := parseNaturalDayOfMonth(w); ok && w != strconv.Itoa(day) {
+				daysOfMonth = append(daysOfMonth, day)
+				continue
+			}
+			if n, err := strconv.Atoi(w); err == nil && i+1 < len(words) {
+				switch strings.TrimSuffix(words[i+1], "s") {
+				case "day", "week", "month":
+					return "", errors.New("Repeating every few days, weeks or months isn't supported. Use specific weekdays or days of the month.")
+				}
+				if naturalIntervalUnit(words[i+1]) != "" {
+					if n <= 0 || interval != 0 {
+						return "", errNaturalTime
+					}
+					interval = n
+					continue
+				}
+			}
+			h, m, n, ok := parseNaturalClock(words[i:])
+			if !ok || hour >= 0 {
+				return "", errNaturalTime
+			}
+			hour, minute = h, m
+			i += n - 1
+		}
+	}
+
+	if intervalUnit != "" {
+		if hour >= 0 || len(weekdays) > 0 || len(daysOfMonth) > 0 || monthly || daily {
+			return "", errors.New("Intervals like 'every 2 hours' can't be combined with a time or day.")
+		}
+		switch intervalUnit {
+		case "minute":
+			if interval >= 60 {
+				return "", errors.New("Minute intervals must be less than 60. Use hours instead.")
+			}
+			return cronForInterval(fmt.Sprintf("%dm", interval))
+		default:
+			if interval >= 24 {
+				return "", errors.New("Hour intervals must be less than 24. Use a daily schedule instead.")
+			}
+			return cronForInterval(fmt.Sprintf("%dh", interval))
+		}
+	}
+
+	if hour < 0 {
+		hour = defaultNaturalHour
+	}
+	switch {
+	case len(daysOfMonth) > 0:
+		if len(weekdays) > 0 {
+			return "", errNaturalTime
+		}
+		return fmt.Sprintf("%d %d %s * *", minute, hour, joinCronList(daysOfMonth)), nil
+	case monthly:
+		return "", errors.New("Say which day of the month, e.g. 'on the 1st of every month'.")
+	case len(weekdays) > 0:
+		return fmt.Sprintf("%d %d * * %s", minute, hour, joinCronList(weekdays)), nil
+	default:
+		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+	}
+}
+
+func joinCronList(values []int) string {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	values = slices.Compact(values)
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
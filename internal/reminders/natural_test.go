@@ -0,0 +1,146 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestParseNaturalNextWeekday pins "next <weekday>" to that weekday of the
+// following Monday-to-Sunday week, whichever day it is said on.
+func TestParseNaturalNextWeekday(t *testing.T) {
+	tests := []struct {
+		now    time.Time
+		phrase string
+		want   time.Time
+	}{
+		// Thursday: not tomorrow, but Friday of next week.
+		{time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC), "next friday 18:00", time.Date(2026, 3, 13, 18, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC), "next monday", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC), "next thursday 8am", time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC)},
+		// Monday: a whole week ahead even though this week's Friday is still to come.
+		{time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), "next friday", time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC)},
+		// Saturday and Sunday: next week starts on the coming Monday.
+		{time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC), "next sunday", time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC), "next monday 7:30", time.Date(2026, 3, 9, 7, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC), "next saturday", time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseNaturalTime(tt.now, tt.phrase, "UTC")
+		if err != nil || !got.Once || !got.RunAt.Equal(tt.want) {
+			t.Fatalf("ParseNaturalTime(%s, %q) = %+v, %v; want %v", tt.now.Weekday(), tt.phrase, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseNaturalTime(t *testing.T) {
+	// Wednesday 2026-03-04 10:00 UTC, 15:30 in Asia/Kolkata.
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	kolkata := func(y int, m time.Month, d, h, min int) time.Time {
+		loc, _ := time.LoadLocation("Asia/Kolkata")
+		return time.Date(y, m, d, h, min, 0, 0, loc).UTC()
+	}
+
+	tests := []struct {
+		phrase   string
+		timezone string
+		want     NaturalTime
+		wantErr  bool
+	}{
+		{phrase: "tomorrow 9am", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)}},
+		{phrase: "tomorrow at 9am", timezone: "Asia/Kolkata", want: NaturalTime{Once: true, RunAt: kolkata(2026, 3, 5, 9, 0)}},
+		{phrase: "Tomorrow", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)}},
+		{phrase: "today 6:30 pm", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 4, 18, 30, 0, 0, time.UTC)}},
+		{phrase: "tonight", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 4, 20, 0, 0, 0, time.UTC)}},
+		{phrase: "next friday 18:00", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 13, 18, 0, 0, 0, time.UTC)}},
+		{phrase: "this friday 18:00", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 6, 18, 0, 0, 0, time.UTC)}},
+		{phrase: "next wednesday", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)}},
+		{phrase: "wednesday 11am", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)}},
+		{phrase: "wednesday 9am", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)}},
+		{phrase: "on monday at noon", timezone: "Asia/Kolkata", want: NaturalTime{Once: true, RunAt: kolkata(2026, 3, 9, 12, 0)}},
+		{phrase: "5pm", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC)}},
+		{phrase: "at 8", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC)}},
+		{phrase: "in 2 hours 30 minutes", timezone: "UTC", want: NaturalTime{Once: true, RunAt: now.Add(150 * time.Minute)}},
+		{phrase: "in an hour", timezone: "UTC", want: NaturalTime{Once: true, RunAt: now.Add(time.Hour)}},
+		{phrase: "in 3 days", timezone: "UTC", want: NaturalTime{Once: true, RunAt: now.AddDate(0, 0, 3)}},
+		{phrase: "in 1h 15m", timezone: "UTC", want: NaturalTime{Once: true, RunAt: now.Add(75 * time.Minute)}},
+		{phrase: "dec 25 at noon", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC)}},
+		{phrase: "1st january", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2027, 1, 1, 9, 0, 0, 0, time.UTC)}},
+		{phrase: "2026-04-01 7:45am", timezone: "UTC", want: NaturalTime{Once: true, RunAt: time.Date(2026, 4, 1, 7, 45, 0, 0, time.UTC)}},
+
+		{phrase: "every weekday at 8:30", timezone: "UTC", want: NaturalTime{CronExpr: "30 8 * * 1,2,3,4,5"}},
+		{phrase: "every day at 9pm", timezone: "UTC", want: NaturalTime{CronExpr: "0 21 * * *"}},
+		{phrase: "daily 07:15", timezone: "UTC", want: NaturalTime{CronExpr: "15 7 * * *"}},
+		{phrase: "every monday, wednesday and friday at 6pm", timezone: "UTC", want: NaturalTime{CronExpr: "0 18 * * 1,3,5"}},
+		{phrase: "every weekend", timezone: "UTC", want: NaturalTime{CronExpr: "0 9 * * 0,6"}},
+		{phrase: "on the 1st of every month", timezone: "UTC", want: NaturalTime{CronExpr: "0 9 1 * *"}},
+		{phrase: "every month on the 15th at 18:00", timezone: "UTC", want: NaturalTime{CronExpr: "0 18 15 * *"}},
+		{phrase: "every 15 minutes", timezone: "UTC", want: NaturalTime{CronExpr: "*/15 * * * *"}},
+		{phrase: "every 2 hours", timezone: "UTC", want: NaturalTime{CronExpr: "0 */2 * * *"}},
+		{phrase: "every hour", timezone: "UTC", want: NaturalTime{CronExpr: "0 * * * *"}},
+
+		{phrase: "", timezone: "UTC", wantErr: true},
+		{phrase: "someday", timezone: "UTC", wantErr: true},
+		{phrase: "tomorrow 25:00", timezone: "UTC", wantErr: true},
+		{phrase: "13pm", timezone: "UTC", wantErr: true},
+		{phrase: "today 9am", timezone: "UTC", wantErr: true},
+		{phrase: "tomorrow friday", timezone: "UTC", wantErr: true},
+		{phrase: "every month", timezone: "UTC", wantErr: true},
+		{phrase: "every 2 days", timezone: "UTC", wantErr: true},
+		{phrase: "every 90 minutes", timezone: "UTC", wantErr: true},
+		{phrase: "every 45 minutes", timezone: "UTC", wantErr: true},
+		{phrase: "every 7 minutes", timezone: "UTC", wantErr: true},
+		{phrase: "every 5 hours", timezone: "UTC", wantErr: true},
+		{phrase: "every 2 hours at 9", timezone: "UTC", wantErr: true},
+		{phrase: "feb 30", timezone: "UTC", wantErr: true},
+		{phrase: "tomorrow 9am", timezone: "Mars/Olympus", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			got, err := ParseNaturalTime(now, tt.phrase, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseNaturalTime(%q) = %+v, want error", tt.phrase, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNaturalTime(%q): %v", tt.phrase, err)
+			}
+			if got.Once != tt.want.Once || !got.RunAt.Equal(tt.want.RunAt) || got.CronExpr != tt.want.CronExpr {
+				t.Fatalf("ParseNaturalTime(%q) = %+v, want %+v", tt.phrase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateReminderFromNaturalPhrase(t *testing.T) {
+	db := openTestDB(t)
+	service := NewService(NewStore(db))
+	ctx := context.Background()
+
+	recurring, err := service.CreateReminder(ctx, CreateReminderInput{UserID: "u", ChannelID: "c", Message: "standup", At: "every weekday at 8:30", Timezone: "Asia/Kolkata"})
+	if err != nil {
+		t.Fatalf("create recurring: %v", err)
+	}
+	if recurring.Schedule != ScheduleCron || recurring.CronExpr != "30 8 * * 1,2,3,4,5" || recurring.Once {
+		t.Fatalf("recurring reminder = %+v", recurring)
+	}
+	local := recurring.NextRun.In(mustLoadLocation(t, "Asia/Kolkata"))
+	if local.Hour() != 8 || local.Minute() != 30 || local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		t.Fatalf("next run %v is not a weekday 08:30 in Asia/Kolkata", local)
+	}
+
+	once, err := service.CreateReminder(ctx, CreateReminderInput{UserID: "u", ChannelID: "c", Message: "dentist", Schedule: "once", At: "tomorrow 9am", Timezone: "Asia/Kolkata"})
+	if err != nil {
+		t.Fatalf("create once: %v", err)
+	}
+	local = once.NextRun.In(mustLoadLocation(t, "Asia/Kolkata"))
+	if !once.Once || local.Hour() != 9 || local.Minute() != 0 {
+		t.Fatalf("one-time reminder = %+v (local %v)", once, local)
+	}
+
+	if _, err := service.CreateReminder(ctx, CreateReminderInput{UserID: "u", ChannelID: "c", Message: "x", Schedule: "once", At: "every day at 9"}); err == nil {
+		t.Fatal("expected a recurring phrase to be rejected for a one-time schedule")
+	}
+}
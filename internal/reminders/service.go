@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,7 +13,18 @@ import (
 )
 
 type Service struct {
-	store *Store
+	store  *Store
+	access ChannelAccess
+}
+
+// ChannelAccess checks a user's rights in a Discord channel. The bot
+// implements it over its gateway session.
+type ChannelAccess interface {
+	// CheckChannel errors unless channelID is a channel of guildID that
+	// userID may send messages in.
+	CheckChannel(guildID, channelID, userID string) error
+	// CheckMentions errors unless userID may ping target in channelID.
+	CheckMentions(guildID, channelID, userID string, target MentionTarget) error
 }
 
 type CreateReminderInput struct {
@@ -24,23 +36,100 @@ type CreateReminderInput struct {
 	At        string
 	CronExpr  string
 	Timezone  string
+	CatchUp   string
+
+	Weekdays   string // weekly: e.g. "mon,wed,fri"
+	DayOfMonth int    // monthly: 1-31
+	Every      string // interval: e.g. "15m" or "2h"
+
+	EndsAt        string // optional: last moment a recurring reminder may run
+	RemainingRuns int64  // optional: retire after this many deliveries
+
+	// Mentions is who delivery pings, already checked against the creator's
+	// permissions. Empty pings the creator.
+	Mentions MentionTarget
+	// DeliveryMode is channel, dm, or fallback; empty means channel.
+	DeliveryMode string
+	// Shared lets other members of the guild subscribe. The creator is
+	// subscribed automatically.
+	Shared bool
+}
+
+var errInvalidSchedule = errors.New("Invalid schedule. Use once, hourly, daily, weekly, monthly, interval, or cron.")
+
+func validSchedule(schedule Schedule) bool {
+	switch schedule {
+	case ScheduleOnce, ScheduleHourly, ScheduleDaily, ScheduleWeekly, ScheduleMonthly, ScheduleInterval, ScheduleCron:
+		return true
+	}
+	return false
 }
 
 func NewService(store *Store) *Service {
 	return &Service{store: store}
 }
 
+// SetChannelAccess makes UpdateReminder check that the owner may use a new
+// channel, and ping the reminder's mentions there. Without it channel
+// changes aren't checked.
+func (s *Service) SetChannelAccess(access ChannelAccess) {
+	s.access = access
+}
+
 func (s *Service) CreateReminder(ctx context.Context, input CreateReminderInput) (*Reminder, error) {
 	schedule := Schedule(strings.ToLower(strings.TrimSpace(input.Schedule)))
-	if schedule != ScheduleOnce && schedule != ScheduleHourly && schedule != ScheduleDaily && schedule != ScheduleCron {
-		return nil, errors.New("Invalid schedule. Use once, hourly, daily, or cron.")
+	if schedule == "" && strings.TrimSpace(input.At) != "" {
+		// No schedule: the time phrase decides, e.g. "every weekday at 8:30".
+		natural, err := ParseNaturalTime(time.Now().UTC(), input.At, input.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		if natural.Once {
+			schedule, input.At = ScheduleOnce, natural.RunAt.Format(time.RFC3339)
+		} else {
+			schedule, input.At, input.CronExpr = ScheduleCron, "", natural.CronExpr
+		}
+	}
+	if !validSchedule(schedule) {
+		return nil, errInvalidSchedule
+	}
+	if err := ValidateMessage(input.Message); err != nil {
+		return nil, err
+	}
+	catchUp, err := ParseCatchUpPolicy(input.CatchUp)
+	if err != nil {
+		return nil, err
+	}
+	if input.GuildID == "" && (len(input.Mentions.Roles) > 0 || input.Mentions.Here) {
+		return nil, errors.New("Role and @here mentions only work in server channels.")
+	}
+	deliveryMode, err := ParseDeliveryMode(input.DeliveryMode)
+	if err != nil {
+		return nil, err
+	}
+	if deliveryMode == DeliveryDM && !input.Mentions.IsZero() {
+		return nil, errors.New("Mentions need channel delivery; DM reminders only reach you.")
+	}
+	if input.Shared {
+		switch {
+		case input.GuildID == "":
+			return nil, errors.New("Shared reminders only work in server channels.")
+		case deliveryMode == DeliveryDM:
+			return nil, errors.New("Shared reminders are posted in a channel; DM delivery isn't available for them.")
+		case !input.Mentions.IsZero():
+			return nil, errors.New("Shared reminders mention their subscribers; leave out mention.")
+		}
 	}
 
-	normalized, err := normalizeSchedule(time.Now().UTC(), normalizeInput{
-		Schedule: schedule,
-		At:       input.At,
-		CronExpr: input.CronExpr,
-		Timezone: input.Timezone,
+	now := time.Now().UTC()
+	normalized, err := normalizeSchedule(now, normalizeInput{
+		Schedule:   schedule,
+		At:         input.At,
+		CronExpr:   input.CronExpr,
+		Timezone:   input.Timezone,
+		Weekdays:   input.Weekdays,
+		DayOfMonth: input.DayOfMonth,
+		Every:      input.Every,
 	})
 	if err != nil {
 		return nil, err
@@ -56,14 +145,51 @@ func (s *Service) CreateReminder(ctx context.Context, input CreateReminderInput)
 		CronExpr:  normalized.CronExpr,
 		Once:      normalized.Once,
 		Timezone:  normalized.Timezone,
+		CatchUp:   catchUp,
 		NextRun:   normalized.NextRun,
+		Mentions:  input.Mentions,
+
+		DeliveryMode: deliveryMode,
+		Shared:       input.Shared,
+	}
+	if err := applyEndConditions(now, reminder, input.EndsAt, input.RemainingRuns); err != nil {
+		return nil, err
 	}
 	if err := s.store.Create(ctx, reminder); err != nil {
 		return nil, err
 	}
+	if reminder.Shared {
+		if _, err := s.store.Subscribe(ctx, reminder.ID, reminder.UserID); err != nil {
+			return nil, err
+		}
+	}
 	return reminder, nil
 }
 
+// SubscribeReminder adds userID to a shared reminder in guildID. found is
+// false when there is no such reminder; added is false when the user was
+// already subscribed.
+func (s *Service) SubscribeReminder(ctx context.Context, id int64, guildID, userID string) (r Reminder, found, added bool, err error) {
+	r, found, err = s.store.GetShared(ctx, id)
+	if err != nil || !found || r.GuildID.String != guildID {
+		return Reminder{}, false, false, err
+	}
+	added, err = s.store.Subscribe(ctx, id, userID)
+	return r, true, added, err
+}
+
+// UnsubscribeReminder removes userID from a shared reminder. found is false
+// when there is no such reminder; removed is false when the user was not
+// subscribed.
+func (s *Service) UnsubscribeReminder(ctx context.Context, id int64, guildID, userID string) (r Reminder, found, removed bool, err error) {
+	r, found, err = s.store.GetShared(ctx, id)
+	if err != nil || !found || r.GuildID.String != guildID {
+		return Reminder{}, false, false, err
+	}
+	removed, err = s.store.Unsubscribe(ctx, id, userID)
+	return r, true, removed, err
+}
+
 func (s *Service) ListUserReminders(ctx context.Context, userID string) ([]Reminder, error) {
 	return s.store.ListByUser(ctx, userID)
 }
@@ -88,11 +214,279 @@ func (s *Service) DeleteReminderWithDetails(ctx context.Context, id int64, userI
 	return reminder, true, nil
 }
 
+// SnoozeReminder pushes an owned reminder's next run d into the future. A
+// one-time reminder that has already been delivered is reactivated.
+func (s *Service) SnoozeReminder(ctx context.Context, id int64, userID string, d time.Duration) (Reminder, bool, error) {
+	if d <= 0 {
+		return Reminder{}, false, errors.New("snooze duration must be positive")
+	}
+	return s.snooze(ctx, id, userID, func(Reminder) time.Time {
+		return time.Now().UTC().Add(d)
+	})
+}
+
+// SnoozeReminderUntilTomorrow reschedules an owned reminder to the same
+// wall-clock time tomorrow in the reminder's timezone.
+func (s *Service) SnoozeReminderUntilTomorrow(ctx context.Context, id int64, userID string) (Reminder, bool, error) {
+	return s.snooze(ctx, id, userID, func(r Reminder) time.Time {
+		loc, err := time.LoadLocation(timezoneOrUTC(r.Timezone))
+		if err != nil {
+			loc = time.UTC
+		}
+		return time.Now().In(loc).AddDate(0, 0, 1).UTC()
+	})
+}
+
+func (s *Service) snooze(ctx context.Context, id int64, userID string, until func(Reminder) time.Time) (Reminder, bool, error) {
+	reminder, ok, err := s.store.GetOwned(ctx, id, userID)
+	if err != nil || !ok {
+		return Reminder{}, ok, err
+	}
+	next := until(reminder).Truncate(time.Second)
+	snoozed, err := s.store.Snooze(ctx, id, userID, next)
+	if err != nil {
+		return Reminder{}, false, err
+	}
+	if !snoozed {
+		return Reminder{}, false, nil
+	}
+	reminder.NextRun = next
+	return reminder, true, nil
+}
+
+// AcknowledgeReminder marks a delivered reminder as done. One-time reminders
+// are removed; recurring reminders keep their schedule.
+func (s *Service) AcknowledgeReminder(ctx context.Context, id int64, userID string) (Reminder, bool, error) {
+	reminder, ok, err := s.store.GetOwned(ctx, id, userID)
+	if err != nil || !ok {
+		return Reminder{}, ok, err
+	}
+	if !reminder.Once {
+		return reminder, true, nil
+	}
+	if _, err := s.store.Delete(ctx, id, userID); err != nil {
+		return Reminder{}, false, err
+	}
+	return reminder, true, nil
+}
+
+// PauseReminder stops an owned reminder from being delivered without
+// changing its schedule. A non-zero until resumes it automatically at that
+// time.
+func (s *Service) PauseReminder(ctx context.Context, id int64, userID string, until time.Time) (Reminder, bool, error) {
+	if !until.IsZero() && !until.After(time.Now()) {
+		return Reminder{}, false, errors.New("Pause-until time must be in the future.")
+	}
+	reminder, ok, err := s.store.GetOwned(ctx, id, userID)
+	if err != nil || !ok {
+		return Reminder{}, ok, err
+	}
+	if !reminder.CompletedAt.IsZero() {
+		return Reminder{}, false, errors.New("This reminder was already delivered. Snooze or edit it to schedule it again.")
+	}
+	paused, err := s.store.Pause(ctx, id, userID, until.Truncate(time.Second))
+	if err != nil || !paused {
+		return Reminder{}, paused, err
+	}
+	reminder.Paused = true
+	reminder.PausedUntil = until.Truncate(time.Second).UTC()
+	return reminder, true, nil
+}
+
+// ResumeReminder unpauses an owned reminder. Recurring reminders continue
+// from their next run after now rather than catching up on the pause.
+func (s *Service) ResumeReminder(ctx context.Context, id int64, userID string) (Reminder, bool, error) {
+	reminder, ok, err := s.store.GetOwned(ctx, id, userID)
+	if err != nil || !ok {
+		return Reminder{}, ok, err
+	}
+	if !reminder.Paused {
+		return Reminder{}, false, errors.New("This reminder is not paused.")
+	}
+	next := ResumeNextRun(reminder, time.Now().UTC())
+	resumed, err := s.store.Resume(ctx, id, next)
+	if err != nil || !resumed {
+		return Reminder{}, resumed, err
+	}
+	reminder.Paused = false
+	reminder.PausedUntil = time.Time{}
+	reminder.NextRun = next
+	reminder.MissedRuns = 0
+	return reminder, true, nil
+}
+
+// UpdateReminderInput is a partial update to an owned reminder. Nil fields
+// are left unchanged. Changing any of Schedule, At, CronExpr or Timezone
+// recomputes the next run the same way CreateReminder does.
+type UpdateReminderInput struct {
+	ID        int64
+	UserID    string
+	Message   *string
+	ChannelID *string
+	Schedule  *string
+	At        *string
+	CronExpr  *string
+	Timezone  *string
+
+	Weekdays   *string
+	DayOfMonth *int
+	Every      *string
+}
+
+// UpdateReminder applies a partial update to an owned reminder, keeping its
+// ID. It returns false if the reminder does not exist or is not owned by
+// input.UserID.
+func (s *Service) UpdateReminder(ctx context.Context, input UpdateReminderInput) (Reminder, bool, error) {
+	reminder, ok, err := s.store.GetOwned(ctx, input.ID, input.UserID)
+	if err != nil || !ok {
+		return Reminder{}, ok, err
+	}
+
+	if input.Message != nil {
+		message := strings.TrimSpace(*input.Message)
+		if message == "" {
+			return Reminder{}, false, errors.New("Message cannot be empty.")
+		}
+		if err := ValidateMessage(message); err != nil {
+			return Reminder{}, false, err
+		}
+		reminder.Message = message
+	}
+	if input.ChannelID != nil {
+		channelID := strings.TrimSpace(*input.ChannelID)
+		if channelID == "" {
+			return Reminder{}, false, errors.New("Channel cannot be empty.")
+		}
+		if channelID != reminder.ChannelID && s.access != nil {
+			if err := s.access.CheckChannel(reminder.GuildID.String, channelID, input.UserID); err != nil {
+				return Reminder{}, false, err
+			}
+			if err := s.access.CheckMentions(reminder.GuildID.String, channelID, input.UserID, reminder.Mentions); err != nil {
+				return Reminder{}, false, err
+			}
+		}
+		reminder.ChannelID = channelID
+	}
+
+	if input.Schedule != nil || input.At != nil || input.CronExpr != nil || input.Timezone != nil ||
+		input.Weekdays != nil || input.DayOfMonth != nil || input.Every != nil {
+		normalize, err := updatedScheduleInput(reminder, input)
+		if err != nil {
+			return Reminder{}, false, err
+		}
+		normalized, err := normalizeSchedule(time.Now().UTC(), normalize)
+		if err != nil {
+			return Reminder{}, false, err
+		}
+		reminder.Schedule = normalize.Schedule
+		reminder.AtTime = normalized.AtTime
+		reminder.CronExpr = normalized.CronExpr
+		reminder.Once = normalized.Once
+		reminder.Timezone = normalized.Timezone
+		reminder.NextRun = normalized.NextRun
+		// The scheduler would retire it again without sending anything.
+		if err := checkNotEnded(reminder); err != nil {
+			return Reminder{}, false, err
+		}
+		// A new schedule reactivates a delivered one-time reminder.
+		reminder.CompletedAt = time.Time{}
+	}
+
+	updated, err := s.store.Update(ctx, &reminder)
+	if err != nil || !updated {
+		return Reminder{}, updated, err
+	}
+	return reminder, true, nil
+}
+
+// updatedScheduleInput merges a partial update with the reminder's current
+// schedule. When the schedule kind is unchanged and no new time is given,
+// the existing time of day, minute or cron expression is kept.
+func updatedScheduleInput(r Reminder, input UpdateReminderInput) (normalizeInput, error) {
+	out := normalizeInput{Schedule: r.Schedule, Timezone: r.Timezone}
+	if input.Schedule != nil {
+		out.Schedule = Schedule(strings.ToLower(strings.TrimSpace(*input.Schedule)))
+		if !validSchedule(out.Schedule) {
+			return normalizeInput{}, errInvalidSchedule
+		}
+	}
+	if input.Timezone != nil {
+		out.Timezone = *input.Timezone
+	}
+	if input.At != nil {
+		out.At = *input.At
+	}
+	if input.CronExpr != nil {
+		out.CronExpr = *input.CronExpr
+	}
+	if out.Schedule == r.Schedule {
+		keepScheduleDetails(r, input, &out)
+	}
+	if input.Weekdays != nil {
+		out.Weekdays = *input.Weekdays
+	}
+	if input.DayOfMonth != nil {
+		out.DayOfMonth = *input.DayOfMonth
+	}
+	if input.Every != nil {
+		out.Every = *input.Every
+	}
+	return out, nil
+}
+
+// keepScheduleDetails fills out with the parts of r's schedule the update
+// doesn't replace, for an update that keeps the schedule kind.
+func keepScheduleDetails(r Reminder, input UpdateReminderInput, out *normalizeInput) {
+	fields := strings.Fields(r.CronExpr)
+	switch {
+	case r.Schedule == ScheduleWeekly && len(fields) == 5:
+		out.Weekdays = fields[4]
+	case r.Schedule == ScheduleMonthly && len(fields) == 5:
+		out.DayOfMonth, _ = strconv.Atoi(fields[2])
+	case r.Schedule == ScheduleInterval:
+		out.Every = r.AtTime.String
+	}
+	if input.At != nil || input.CronExpr != nil {
+		return
+	}
+
+	switch r.Schedule {
+	case ScheduleOnce, ScheduleDaily, ScheduleWeekly, ScheduleMonthly:
+		out.At = r.AtTime.String
+	case ScheduleHourly:
+		field, _, _ := strings.Cut(r.CronExpr, " ")
+		if minute, err := strconv.Atoi(field); err == nil {
+			out.At = fmt.Sprintf(":%02d", minute)
+		}
+	case ScheduleCron:
+		out.CronExpr = r.CronExpr
+	}
+}
+
+// ReminderHistory returns the most recent delivery attempts for an owned
+// reminder, newest first.
+func (s *Service) ReminderHistory(ctx context.Context, id int64, userID string, limit int) ([]Delivery, bool, error) {
+	if _, ok, err := s.store.GetOwned(ctx, id, userID); err != nil || !ok {
+		return nil, ok, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	deliveries, err := s.store.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	return deliveries, true, nil
+}
+
 type normalizeInput struct {
-	Schedule Schedule
-	At       string
-	CronExpr string
-	Timezone string
+	Schedule   Schedule
+	At         string
+	CronExpr   string
+	Timezone   string
+	Weekdays   string // weekly: e.g. "mon,wed,fri"
+	DayOfMonth int    // monthly: 1-31
+	Every      string // interval: e.g. "15m" or "2h"
 }
 
 type normalizedSchedule struct {
@@ -113,14 +507,14 @@ func normalizeSchedule(now time.Time, input normalizeInput) (normalizedSchedule,
 		return normalizedSchedule{}, errors.New("invalid timezone")
 	}
 
-	nextRun, atTime, err := nextRunForSchedule(now, input.Schedule, input.At, input.CronExpr, loc)
+	nextRun, atTime, err := nextRunForSchedule(now, input, loc)
 	if err != nil {
 		return normalizedSchedule{}, err
 	}
 
 	cronExpr := ""
 	if input.Schedule != ScheduleOnce {
-		cronExpr, err = cronForSchedule(now, input.Schedule, input.At, input.CronExpr, loc)
+		cronExpr, err = cronForSchedule(now, input, loc)
 		if err != nil {
 			return normalizedSchedule{}, err
 		}
@@ -135,12 +529,11 @@ func normalizeSchedule(now time.Time, input normalizeInput) (normalizedSchedule,
 	}, nil
 }
 
-func nextRunForSchedule(now time.Time, schedule Schedule, at, cronExpr string, loc *time.Location) (time.Time, sql.NullString, error) {
+func nextRunForSchedule(now time.Time, input normalizeInput, loc *time.Location) (time.Time, sql.NullString, error) {
 	now = now.UTC()
-	at = strings.TrimSpace(at)
+	at := strings.TrimSpace(input.At)
 
-	switch schedule {
-	case ScheduleOnce:
+	if input.Schedule == ScheduleOnce {
 		if at == "" {
 			nextRun := now.Add(5 * time.Minute)
 			return nextRun, sql.NullString{String: nextRun.Format(time.RFC3339), Valid: true}, nil
@@ -150,49 +543,42 @@ func nextRunForSchedule(now time.Time, schedule Schedule, at, cronExpr string, l
 			return nextRun, sql.NullString{String: nextRun.Format(time.RFC3339), Valid: true}, nil
 		}
 		parsed, err := parseFlexibleTime(at, loc)
-		if err != nil || !parsed.After(now) {
-			return time.Time{}, sql.NullString{}, errors.New("Invalid time. For once, use a duration like '10m', '2h', '3d', or an absolute time like '2025-01-31 15:04'.")
-		}
-		return parsed, sql.NullString{String: parsed.Format(time.RFC3339), Valid: true}, nil
-	case ScheduleHourly:
-		expr, err := cronForSchedule(now, schedule, at, "", loc)
-		if err != nil {
-			return time.Time{}, sql.NullString{}, err
-		}
-		nextRun, err := nextRunForCron(now, expr, loc)
-		return nextRun, sql.NullString{}, err
-	case ScheduleDaily:
-		hhmm := at
-		if hhmm == "" {
-			hhmm = now.In(loc).Truncate(time.Minute).Add(time.Minute).Format("15:04")
-		}
-		expr, err := cronForSchedule(now, schedule, hhmm, "", loc)
-		if err != nil {
-			return time.Time{}, sql.NullString{}, err
-		}
-		nextRun, err := nextRunForCron(now, expr, loc)
 		if err != nil {
-			return time.Time{}, sql.NullString{}, err
-		}
-		return nextRun, sql.NullString{String: hhmm, Valid: true}, nil
-	case ScheduleCron:
-		expr := strings.TrimSpace(cronExpr)
-		if expr == "" {
-			expr = at
+			natural, naturalErr := ParseNaturalTime(now, at, loc.String())
+			if naturalErr != nil || !natural.Once {
+				return time.Time{}, sql.NullString{}, errors.New("Invalid time. For once, use a duration like '10m', '2h', '3d', an absolute time like '2025-01-31 15:04', or a phrase like 'tomorrow 9am'.")
+			}
+			parsed = natural.RunAt
 		}
-		nextRun, err := nextRunForCron(now, expr, loc)
-		if err != nil {
-			return time.Time{}, sql.NullString{}, err
+		if !parsed.After(now) {
+			return time.Time{}, sql.NullString{}, errors.New("Invalid time. For once, use a duration like '10m', '2h', '3d', an absolute time like '2025-01-31 15:04', or a phrase like 'tomorrow 9am'.")
 		}
-		return nextRun, sql.NullString{}, nil
+		return parsed, sql.NullString{String: parsed.Format(time.RFC3339), Valid: true}, nil
+	}
+
+	expr, err := cronForSchedule(now, input, loc)
+	if err != nil {
+		return time.Time{}, sql.NullString{}, err
+	}
+	nextRun, err := nextRunForCron(now, expr, loc)
+	if err != nil {
+		return time.Time{}, sql.NullString{}, err
+	}
+	switch input.Schedule {
+	case ScheduleDaily, ScheduleWeekly, ScheduleMonthly:
+		return nextRun, sql.NullString{String: timeOfDayOrNext(now, at, loc), Valid: true}, nil
+	case ScheduleInterval:
+		return nextRun, sql.NullString{String: strings.TrimSpace(input.Every), Valid: true}, nil
 	default:
-		return time.Time{}, sql.NullString{}, errors.New("unknown schedule")
+		return nextRun, sql.NullString{}, nil
 	}
 }
 
-func cronForSchedule(now time.Time, schedule Schedule, at, cronExpr string, loc *time.Location) (string, error) {
-	at = strings.TrimSpace(at)
-	switch schedule {
+// cronForSchedule turns any recurring schedule into the cron expression the
+// scheduler runs on, validating its inputs along the way.
+func cronForSchedule(now time.Time, input normalizeInput, loc *time.Location) (string, error) {
+	at := strings.TrimSpace(input.At)
+	switch input.Schedule {
 	case ScheduleHourly:
 		minute := now.In(loc).Truncate(time.Minute).Add(time.Minute).Minute()
 		if at != "" {
@@ -204,16 +590,34 @@ func cronForSchedule(now time.Time, schedule Schedule, at, cronExpr string, loc
 		}
 		return fmt.Sprintf("%d * * * *", minute), nil
 	case ScheduleDaily:
-		if at == "" {
-			at = now.In(loc).Truncate(time.Minute).Add(time.Minute).Format("15:04")
-		}
-		hour, minute, err := parseHHMM(at)
+		hour, minute, err := parseHHMM(timeOfDayOrNext(now, at, loc))
 		if err != nil {
 			return "", errors.New("Use HH:MM, e.g., 09:00.")
 		}
 		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+	case ScheduleWeekly:
+		hour, minute, err := parseHHMM(timeOfDayOrNext(now, at, loc))
+		if err != nil {
+			return "", errors.New("Use HH:MM, e.g., 09:00.")
+		}
+		days, err := parseWeekdayList(input.Weekdays)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d %d * * %s", minute, hour, joinCronList(days)), nil
+	case ScheduleMonthly:
+		hour, minute, err := parseHHMM(timeOfDayOrNext(now, at, loc))
+		if err != nil {
+			return "", errors.New("Use HH:MM, e.g., 09:00.")
+		}
+		if input.DayOfMonth < 1 || input.DayOfMonth > 31 {
+			return "", errors.New("Pick a day of the month from 1 to 31 for monthly reminders.")
+		}
+		return fmt.Sprintf("%d %d %d * *", minute, hour, input.DayOfMonth), nil
+	case ScheduleInterval:
+		return cronForInterval(input.Every)
 	case ScheduleCron:
-		expr := strings.TrimSpace(cronExpr)
+		expr := strings.TrimSpace(input.CronExpr)
 		if expr == "" {
 			expr = at
 		}
@@ -222,10 +626,71 @@ func cronForSchedule(now time.Time, schedule Schedule, at, cronExpr string, loc
 		}
 		return expr, nil
 	default:
-		return "", nil
+		return "", errors.New("unknown schedule")
 	}
 }
 
+// timeOfDayOrNext returns at, or the next whole minute in loc as HH:MM when
+// at is empty.
+func timeOfDayOrNext(now time.Time, at string, loc *time.Location) string {
+	if at == "" {
+		return now.In(loc).Truncate(time.Minute).Add(time.Minute).Format("15:04")
+	}
+	return at
+}
+
+// cronForInterval turns "15m" or "2h" into a cron expression. Intervals must
+// divide an hour or a day evenly so every gap is the same length.
+func cronForInterval(every string) (string, error) {
+	d, ok := parseRelativeDuration(every)
+	if !ok {
+		return "", errors.New("Use an interval like 15m or 2h.")
+	}
+	switch {
+	case d%time.Hour == 0 && d < 24*time.Hour && 24%int(d/time.Hour) == 0:
+		if d == time.Hour {
+			return "0 * * * *", nil
+		}
+		return fmt.Sprintf("0 */%d * * *", int(d/time.Hour)), nil
+	case d%time.Minute == 0 && d < time.Hour && 60%int(d/time.Minute) == 0:
+		if d == time.Minute {
+			return "* * * * *", nil
+		}
+		return fmt.Sprintf("*/%d * * * *", int(d/time.Minute)), nil
+	default:
+		return "", errors.New("Intervals must divide an hour or a day evenly, e.g. 5m, 15m, 30m, 2h, or 6h.")
+	}
+}
+
+// parseWeekdayList parses days such as "mon,wed,fri", "weekdays" or the
+// cron form "1,3,5".
+func parseWeekdayList(s string) ([]int, error) {
+	var days []int
+	for _, part := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch part {
+		case "weekday", "weekdays":
+			days = append(days, 1, 2, 3, 4, 5)
+			continue
+		case "weekend", "weekends":
+			days = append(days, 0, 6)
+			continue
+		}
+		if day, ok := naturalWeekdays[strings.TrimSuffix(part, "s")]; ok {
+			days = append(days, int(day))
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n > 7 {
+			return nil, fmt.Errorf("Unknown day %q. Use names like mon,wed,fri.", part)
+		}
+		days = append(days, n%7)
+	}
+	if len(days) == 0 {
+		return nil, errors.New("Pick days for weekly reminders, e.g. mon,wed,fri.")
+	}
+	return days, nil
+}
+
 func nextRunForCron(now time.Time, expr string, loc *time.Location) (time.Time, error) {
 	schedule, err := cron.ParseStandard(expr)
 	if err != nil {
@@ -246,6 +711,29 @@ func parseFlexibleTime(s string, loc *time.Location) (time.Time, error) {
 	return time.Time{}, errors.New("bad time format")
 }
 
+// ParseUntil parses an end time such as a pause-until date. It accepts a
+// relative duration ("3d", "12h"), a date ("2026-08-01", midnight in the
+// timezone), a local "YYYY-MM-DD HH:MM", or RFC3339. The result must be
+// after now.
+func ParseUntil(now time.Time, s string, timezone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timezoneOrUTC(strings.TrimSpace(timezone)))
+	if err != nil {
+		return time.Time{}, errors.New("invalid timezone")
+	}
+	var until time.Time
+	if d, ok := parseRelativeDuration(s); ok {
+		until = now.Add(d)
+	} else if day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(s), loc); err == nil {
+		until = day
+	} else if until, err = parseFlexibleTime(s, loc); err != nil {
+		return time.Time{}, errors.New("Invalid time. Use a duration like '3d', a date like '2026-08-01', or '2026-08-01 09:00'.")
+	}
+	if !until.After(now) {
+		return time.Time{}, errors.New("That time is in the past.")
+	}
+	return until.UTC().Truncate(time.Second), nil
+}
+
 func parseHourMinute(now time.Time, s string, loc *time.Location) (time.Time, error) {
 	s = strings.TrimSpace(s)
 	if len(s) == 3 && s[0] == ':' {
@@ -297,7 +785,7 @@ func parseHourMinuteUTC(now time.Time, s string) (time.Time, error) {
 }
 
 func legacyNextRunForSchedule(now time.Time, schedule Schedule, at string) (time.Time, sql.NullString, error) {
-	return nextRunForSchedule(now, schedule, at, "", time.UTC)
+	return nextRunForSchedule(now, normalizeInput{Schedule: schedule, At: at}, time.UTC)
 }
 
 func parseRelativeDuration(s string) (time.Duration, bool) {
@@ -14,26 +14,58 @@ import (
 type Schedule string
 
 const (
-	ScheduleOnce   Schedule = "once"
-	ScheduleHourly Schedule = "hourly"
-	ScheduleDaily  Schedule = "daily"
-	ScheduleCron   Schedule = "cron"
+	ScheduleOnce     Schedule = "once"
+	ScheduleHourly   Schedule = "hourly"
+	ScheduleDaily    Schedule = "daily"
+	ScheduleWeekly   Schedule = "weekly"
+	ScheduleMonthly  Schedule = "monthly"
+	ScheduleInterval Schedule = "interval"
+	ScheduleCron     Schedule = "cron"
 )
 
 type Reminder struct {
-	ID        int64
-	UserID    string
-	ChannelID string
-	GuildID   sql.NullString
-	Message   string
-	Schedule  Schedule
-	AtTime    sql.NullString // RFC3339 for once; HH:MM for daily
-	CronExpr  string
-	Once      bool
-	Timezone  string
-	NextRun   time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            int64
+	UserID        string
+	ChannelID     string
+	GuildID       sql.NullString
+	Message       string
+	Schedule      Schedule
+	AtTime        sql.NullString // RFC3339 for once; HH:MM for daily, weekly and monthly; the interval for interval
+	CronExpr      string
+	Once          bool
+	Timezone      string
+	NextRun       time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	FailureCount  int64     // consecutive failed sends of the current run
+	RetryAt       time.Time // zero unless backing off after a failed send
+	LastError     string
+	DeadAt        time.Time // set once retries are exhausted
+	CatchUp       CatchUpPolicy
+	MissedRuns    int64     // runs skipped after downtime before NextRun
+	CompletedAt   time.Time // set once a one-time reminder has been delivered
+	Paused        bool
+	PausedUntil   time.Time     // zero when paused indefinitely
+	EndsAt        time.Time     // zero when the reminder has no end date
+	RemainingRuns sql.NullInt64 // deliveries left before the reminder retires; NULL for no limit
+	Mentions      MentionTarget // who delivery pings; zero pings the owner
+	DeliveryMode  DeliveryMode
+	RunCount      int64 // deliveries sent so far
+	Shared        bool  // other guild members can subscribe
+
+	// Subscribers is loaded by the scheduler for shared reminders right
+	// before delivery. It is not stored on the reminder row.
+	Subscribers []string
+
+	// LastRun is set by the scheduler on the final delivery of a reminder
+	// with an end condition. It is not stored.
+	LastRun bool
+}
+
+// Dead reports whether the reminder stopped retrying after repeated send
+// failures.
+func (r Reminder) Dead() bool {
+	return !r.DeadAt.IsZero()
 }
 
 type Store struct {
@@ -60,18 +92,24 @@ func (s *Store) Create(ctx context.Context, r *Reminder) error {
 		atPtr = &v
 	}
 	created, err := s.q.CreateReminder(ctx, s.db, data.CreateReminderParams{
-		UserID:    r.UserID,
-		ChannelID: r.ChannelID,
-		GuildID:   guildIDPtr,
-		Message:   r.Message,
-		Schedule:  string(r.Schedule),
-		AtTime:    atPtr,
-		CronExpr:  r.CronExpr,
-		Once:      boolToInt64(r.Once),
-		Timezone:  timezoneOrUTC(r.Timezone),
-		NextRun:   r.NextRun.UTC().Unix(),
-		CreatedAt: r.CreatedAt.Unix(),
-		UpdatedAt: r.UpdatedAt.Unix(),
+		UserID:        r.UserID,
+		ChannelID:     r.ChannelID,
+		GuildID:       guildIDPtr,
+		Message:       r.Message,
+		Schedule:      string(r.Schedule),
+		AtTime:        atPtr,
+		CronExpr:      r.CronExpr,
+		Once:          boolToInt64(r.Once),
+		Timezone:      timezoneOrUTC(r.Timezone),
+		CatchUp:       string(catchUpOrDefault(r.CatchUp)),
+		NextRun:       r.NextRun.UTC().Unix(),
+		CreatedAt:     r.CreatedAt.Unix(),
+		UpdatedAt:     r.UpdatedAt.Unix(),
+		EndsAt:        timePtr(r.EndsAt),
+		RemainingRuns: int64Ptr(r.RemainingRuns),
+		Mentions:      r.Mentions.String(),
+		DeliveryMode:  string(deliveryModeOrDefault(r.DeliveryMode)),
+		Shared:        boolToInt64(r.Shared),
 	})
 	if err != nil {
 		return err
@@ -97,6 +135,11 @@ func (s *Store) Delete(ctx context.Context, id int64, userID string) (bool, erro
 	if err != nil {
 		return false, err
 	}
+	if n > 0 {
+		if _, err := s.q.DeleteOrphanReminderSubscribers(ctx, s.db); err != nil {
+			return true, err
+		}
+	}
 	return n > 0, nil
 }
 
@@ -112,7 +155,8 @@ func (s *Store) GetOwned(ctx context.Context, id int64, userID string) (Reminder
 }
 
 func (s *Store) Due(ctx context.Context, now time.Time, limit int) ([]Reminder, error) {
-	recs, err := s.q.ListDue(ctx, s.db, now.UTC().Unix(), int64(limit))
+	unix := now.UTC().Unix()
+	recs, err := s.q.ListDue(ctx, s.db, data.ListDueParams{NextRun: unix, RetryAt: &unix, Limit: int64(limit)})
 	if err != nil {
 		return nil, err
 	}
@@ -127,10 +171,169 @@ func (s *Store) SetNextRun(ctx context.Context, id int64, t time.Time) error {
 	return s.q.SetNextRun(ctx, s.db, data.SetNextRunParams{NextRun: t.UTC().Unix(), UpdatedAt: time.Now().UTC().Unix(), ID: id})
 }
 
+// Update overwrites an owned reminder's message, channel and schedule. It
+// also clears any failure, dead-letter and missed-run state so the edited
+// reminder starts fresh.
+func (s *Store) Update(ctx context.Context, r *Reminder) (bool, error) {
+	var atPtr *string
+	if r.AtTime.Valid {
+		v := r.AtTime.String
+		atPtr = &v
+	}
+	now := time.Now().UTC()
+	n, err := s.q.UpdateOwned(ctx, s.db, data.UpdateOwnedParams{
+		ChannelID:   r.ChannelID,
+		Message:     r.Message,
+		Schedule:    string(r.Schedule),
+		AtTime:      atPtr,
+		CronExpr:    r.CronExpr,
+		Once:        boolToInt64(r.Once),
+		Timezone:    timezoneOrUTC(r.Timezone),
+		NextRun:     r.NextRun.UTC().Unix(),
+		CompletedAt: timePtr(r.CompletedAt),
+		UpdatedAt:   now.Unix(),
+		ID:          r.ID,
+		UserID:      r.UserID,
+	})
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	r.UpdatedAt = now
+	r.FailureCount = 0
+	r.RetryAt = time.Time{}
+	r.LastError = ""
+	r.DeadAt = time.Time{}
+	r.MissedRuns = 0
+	return true, nil
+}
+
+// Pause stops an owned reminder from being delivered until it is resumed.
+// A non-zero until lets the scheduler resume it automatically.
+func (s *Store) Pause(ctx context.Context, id int64, userID string, until time.Time) (bool, error) {
+	n, err := s.q.PauseOwned(ctx, s.db, data.PauseOwnedParams{
+		PausedUntil: timePtr(until),
+		UpdatedAt:   time.Now().UTC().Unix(),
+		ID:          id,
+		UserID:      userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Resume unpauses a reminder and moves it to next. It returns false if the
+// reminder was not paused.
+func (s *Store) Resume(ctx context.Context, id int64, next time.Time) (bool, error) {
+	n, err := s.q.ResumeReminder(ctx, s.db, data.ResumeReminderParams{
+		NextRun:   next.UTC().Unix(),
+		UpdatedAt: time.Now().UTC().Unix(),
+		ID:        id,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ResumeExpired resumes paused reminders whose paused-until time has passed
+// and returns them with their new next run.
+func (s *Store) ResumeExpired(ctx context.Context, now time.Time, limit int) ([]Reminder, error) {
+	recs, err := s.q.ListPausedUntil(ctx, s.db, timePtr(now), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Reminder, 0, len(recs))
+	for _, it := range recs {
+		r := convertListPausedUntilRow(it)
+		next := ResumeNextRun(r, now)
+		resumed, err := s.Resume(ctx, r.ID, next)
+		if err != nil {
+			return out, err
+		}
+		if !resumed {
+			continue
+		}
+		r.Paused = false
+		r.PausedUntil = time.Time{}
+		r.NextRun = next
+		r.MissedRuns = 0
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// ResumeNextRun is when a paused reminder should next run after resuming at
+// now. Recurring reminders skip the runs that fell inside the pause instead
+// of catching up on them; one-time reminders keep their time and fire right
+// away if it has passed.
+func ResumeNextRun(r Reminder, now time.Time) time.Time {
+	if r.NextRun.After(now) {
+		return r.NextRun
+	}
+	next, repeat, err := NextAfter(r, now)
+	if err != nil || !repeat {
+		return r.NextRun
+	}
+	return next
+}
+
+// CatchUp moves an overdue reminder from r.NextRun to next and records how
+// many runs were skipped. It returns false if the reminder changed since it
+// was loaded.
+func (s *Store) CatchUp(ctx context.Context, r Reminder, next time.Time, missed int64) (bool, error) {
+	n, err := s.q.CatchUpReminder(ctx, s.db, data.CatchUpReminderParams{
+		NextRun:    next.UTC().Unix(),
+		MissedRuns: missed,
+		UpdatedAt:  time.Now().UTC().Unix(),
+		ID:         r.ID,
+		NextRun_2:  r.NextRun.UTC().Unix(),
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 func (s *Store) DeleteID(ctx context.Context, id int64) error {
 	return s.q.DeleteByID(ctx, s.db, id)
 }
 
+// Complete marks a delivered one-time reminder as finished. The row is kept
+// so snooze buttons on the delivered message can still reschedule it.
+func (s *Store) Complete(ctx context.Context, id int64, at time.Time) error {
+	completedAt := at.UTC().Unix()
+	return s.q.CompleteReminder(ctx, s.db, data.CompleteReminderParams{CompletedAt: &completedAt, UpdatedAt: time.Now().UTC().Unix(), ID: id})
+}
+
+// Snooze moves an owned reminder's next run and reactivates it if it had
+// already completed.
+func (s *Store) Snooze(ctx context.Context, id int64, userID string, until time.Time) (bool, error) {
+	n, err := s.q.SnoozeOwned(ctx, s.db, data.SnoozeOwnedParams{
+		NextRun:   until.UTC().Unix(),
+		UpdatedAt: time.Now().UTC().Unix(),
+		ID:        id,
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *Store) PurgeCompleted(ctx context.Context, before time.Time) (int64, error) {
+	cutoff := before.UTC().Unix()
+	n, err := s.q.DeleteCompletedBefore(ctx, s.db, &cutoff)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	_, err = s.q.DeleteOrphanReminderSubscribers(ctx, s.db)
+	return n, err
+}
+
 func NextAfter(r Reminder, from time.Time) (time.Time, bool, error) {
 	if r.Once || r.Schedule == ScheduleOnce {
 		return time.Time{}, false, nil
@@ -173,75 +376,226 @@ func NextAfter(r Reminder, from time.Time) (time.Time, bool, error) {
 	}
 }
 
+// UpcomingRuns returns up to n run times starting at r.NextRun, stopping
+// early at the reminder's end conditions. One-time reminders return just
+// their single run.
+func UpcomingRuns(r Reminder, n int) ([]time.Time, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	runs := []time.Time{r.NextRun}
+	for len(runs) < n {
+		next, repeat, err := NextAfter(r, r.NextRun)
+		if err != nil {
+			return nil, err
+		}
+		if !repeat || !ContinuesAfter(r, next) {
+			break
+		}
+		runs = append(runs, next)
+		r.NextRun = next
+		if r.RemainingRuns.Valid {
+			r.RemainingRuns.Int64--
+		}
+	}
+	return runs, nil
+}
+
 func convertCreateReminderRow(m data.CreateReminderRow) Reminder {
 	return Reminder{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		ChannelID: m.ChannelID,
-		GuildID:   nullStringFromPtr(m.GuildID),
-		Message:   m.Message,
-		Schedule:  Schedule(m.Schedule),
-		AtTime:    nullStringFromPtr(m.AtTime),
-		CronExpr:  m.CronExpr,
-		Once:      m.Once != 0,
-		Timezone:  timezoneOrUTC(m.Timezone),
-		NextRun:   time.Unix(m.NextRun, 0).UTC(),
-		CreatedAt: time.Unix(m.CreatedAt, 0).UTC(),
-		UpdatedAt: time.Unix(m.UpdatedAt, 0).UTC(),
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
 	}
 }
 
 func convertListByUserRow(m data.ListByUserRow) Reminder {
 	return Reminder{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		ChannelID: m.ChannelID,
-		GuildID:   nullStringFromPtr(m.GuildID),
-		Message:   m.Message,
-		Schedule:  Schedule(m.Schedule),
-		AtTime:    nullStringFromPtr(m.AtTime),
-		CronExpr:  m.CronExpr,
-		Once:      m.Once != 0,
-		Timezone:  timezoneOrUTC(m.Timezone),
-		NextRun:   time.Unix(m.NextRun, 0).UTC(),
-		CreatedAt: time.Unix(m.CreatedAt, 0).UTC(),
-		UpdatedAt: time.Unix(m.UpdatedAt, 0).UTC(),
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
 	}
 }
 
 func convertListDueRow(m data.ListDueRow) Reminder {
 	return Reminder{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		ChannelID: m.ChannelID,
-		GuildID:   nullStringFromPtr(m.GuildID),
-		Message:   m.Message,
-		Schedule:  Schedule(m.Schedule),
-		AtTime:    nullStringFromPtr(m.AtTime),
-		CronExpr:  m.CronExpr,
-		Once:      m.Once != 0,
-		Timezone:  timezoneOrUTC(m.Timezone),
-		NextRun:   time.Unix(m.NextRun, 0).UTC(),
-		CreatedAt: time.Unix(m.CreatedAt, 0).UTC(),
-		UpdatedAt: time.Unix(m.UpdatedAt, 0).UTC(),
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
 	}
 }
 
 func convertGetOwnedRow(m data.GetOwnedRow) Reminder {
 	return Reminder{
-		ID:        m.ID,
-		UserID:    m.UserID,
-		ChannelID: m.ChannelID,
-		GuildID:   nullStringFromPtr(m.GuildID),
-		Message:   m.Message,
-		Schedule:  Schedule(m.Schedule),
-		AtTime:    nullStringFromPtr(m.AtTime),
-		CronExpr:  m.CronExpr,
-		Once:      m.Once != 0,
-		Timezone:  timezoneOrUTC(m.Timezone),
-		NextRun:   time.Unix(m.NextRun, 0).UTC(),
-		CreatedAt: time.Unix(m.CreatedAt, 0).UTC(),
-		UpdatedAt: time.Unix(m.UpdatedAt, 0).UTC(),
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
+	}
+}
+
+func convertListPausedUntilRow(m data.ListPausedUntilRow) Reminder {
+	return Reminder{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
+	}
+}
+
+func convertGetSharedReminderRow(m data.GetSharedReminderRow) Reminder {
+	return Reminder{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		ChannelID:     m.ChannelID,
+		GuildID:       nullStringFromPtr(m.GuildID),
+		Message:       m.Message,
+		Schedule:      Schedule(m.Schedule),
+		AtTime:        nullStringFromPtr(m.AtTime),
+		CronExpr:      m.CronExpr,
+		Once:          m.Once != 0,
+		Timezone:      timezoneOrUTC(m.Timezone),
+		NextRun:       time.Unix(m.NextRun, 0).UTC(),
+		CreatedAt:     time.Unix(m.CreatedAt, 0).UTC(),
+		UpdatedAt:     time.Unix(m.UpdatedAt, 0).UTC(),
+		FailureCount:  m.FailureCount,
+		RetryAt:       timeFromPtr(m.RetryAt),
+		LastError:     m.LastError,
+		DeadAt:        timeFromPtr(m.DeadAt),
+		CatchUp:       CatchUpPolicy(m.CatchUp),
+		MissedRuns:    m.MissedRuns,
+		CompletedAt:   timeFromPtr(m.CompletedAt),
+		Paused:        m.Paused != 0,
+		PausedUntil:   timeFromPtr(m.PausedUntil),
+		EndsAt:        timeFromPtr(m.EndsAt),
+		RemainingRuns: nullInt64FromPtr(m.RemainingRuns),
+		Mentions:      storedMentionTarget(m.Mentions),
+		DeliveryMode:  deliveryModeOrDefault(DeliveryMode(m.DeliveryMode)),
+		RunCount:      m.RunCount,
+		Shared:        m.Shared != 0,
 	}
 }
 
@@ -252,6 +606,42 @@ func nullStringFromPtr(v *string) sql.NullString {
 	return sql.NullString{String: *v, Valid: true}
 }
 
+// storedMentionTarget parses a mentions column. Stored values were
+// validated on create, so a parse failure just falls back to the owner.
+func storedMentionTarget(v string) MentionTarget {
+	target, _ := ParseMentionTarget(v)
+	return target
+}
+
+func nullInt64FromPtr(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func int64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func timeFromPtr(v *int64) time.Time {
+	if v == nil {
+		return time.Time{}
+	}
+	return time.Unix(*v, 0).UTC()
+}
+
+func timePtr(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	v := t.UTC().Unix()
+	return &v
+}
+
 func boolToInt64(v bool) int64 {
 	if v {
 		return 1
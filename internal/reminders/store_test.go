@@ -3,6 +3,8 @@ package reminders
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +17,9 @@ func openTestDB(t *testing.T) *sql.DB {
 	if err != nil {
 		t.Fatal(err)
 	}
+	// Every connection to :memory: is a separate database; keep one so
+	// transactions see the same tables.
+	db.SetMaxOpenConns(1)
 	_, err = db.Exec(`CREATE TABLE reminders (
         id INTEGER PRIMARY KEY AUTOINCREMENT,
         user_id TEXT NOT NULL,
@@ -28,8 +33,48 @@ func openTestDB(t *testing.T) *sql.DB {
         timezone TEXT NOT NULL DEFAULT 'UTC',
         next_run INTEGER NOT NULL,
         created_at INTEGER NOT NULL,
-        updated_at INTEGER NOT NULL
-    );`)
+        updated_at INTEGER NOT NULL,
+        completed_at INTEGER,
+        failure_count INTEGER NOT NULL DEFAULT 0,
+        retry_at INTEGER,
+        last_error TEXT NOT NULL DEFAULT '',
+        dead_at INTEGER,
+        catch_up TEXT NOT NULL DEFAULT 'once',
+        missed_runs INTEGER NOT NULL DEFAULT 0,
+        paused INTEGER NOT NULL DEFAULT 0,
+        paused_until INTEGER,
+        ends_at INTEGER,
+        remaining_runs INTEGER,
+        mentions TEXT NOT NULL DEFAULT '',
+        delivery_mode TEXT NOT NULL DEFAULT 'channel',
+        run_count INTEGER NOT NULL DEFAULT 0,
+        shared INTEGER NOT NULL DEFAULT 0
+    );
+    CREATE TABLE reminder_subscribers (
+        reminder_id INTEGER NOT NULL,
+        user_id TEXT NOT NULL,
+        created_at INTEGER NOT NULL,
+        PRIMARY KEY (reminder_id, user_id)
+    );
+    CREATE TABLE reminder_feed_tokens (
+        user_id TEXT PRIMARY KEY,
+        token TEXT NOT NULL UNIQUE,
+        created_at INTEGER NOT NULL
+    );
+    CREATE TABLE reminder_deliveries (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        reminder_id INTEGER NOT NULL,
+        scheduled_for INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        error TEXT NOT NULL DEFAULT '',
+        created_at INTEGER NOT NULL,
+        updated_at INTEGER NOT NULL,
+        missed_runs INTEGER NOT NULL DEFAULT 0,
+        delivered_via TEXT NOT NULL DEFAULT '',
+        fallback_reason TEXT NOT NULL DEFAULT ''
+    );
+    CREATE UNIQUE INDEX idx_reminder_deliveries_reminder_scheduled ON reminder_deliveries(reminder_id, scheduled_for);`)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -95,6 +140,122 @@ func TestDeleteReminderWithDetails(t *testing.T) {
 	}
 }
 
+func TestSnoozeReactivatesCompletedReminder(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	r := &Reminder{UserID: "u", ChannelID: "c", Message: "stretch", Schedule: ScheduleOnce, Once: true, Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := store.Complete(ctx, r.ID, now); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 0 {
+		t.Fatalf("completed reminder should not be listed: %+v err=%v", list, err)
+	}
+
+	if _, ok, err := service.SnoozeReminder(ctx, r.ID, "other", 10*time.Minute); err != nil || ok {
+		t.Fatalf("snooze by non-owner: ok=%v err=%v", ok, err)
+	}
+	snoozed, ok, err := service.SnoozeReminder(ctx, r.ID, "u", 10*time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("snooze: ok=%v err=%v", ok, err)
+	}
+	if snoozed.NextRun.Before(now.Add(9*time.Minute)) || snoozed.NextRun.After(now.Add(11*time.Minute)) {
+		t.Fatalf("next_run = %v, want about 10m from %v", snoozed.NextRun, now)
+	}
+	due, err := store.Due(ctx, now.Add(11*time.Minute), 10)
+	if err != nil || len(due) != 1 || due[0].ID != r.ID {
+		t.Fatalf("snoozed reminder should be due again: %+v err=%v", due, err)
+	}
+
+	if _, ok, err := service.AcknowledgeReminder(ctx, r.ID, "u"); err != nil || !ok {
+		t.Fatalf("acknowledge: ok=%v err=%v", ok, err)
+	}
+	if _, ok, err := store.GetOwned(ctx, r.ID, "u"); err != nil || ok {
+		t.Fatalf("acknowledged one-time reminder should be deleted: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAcknowledgeRecurringReminderKeepsSchedule(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+
+	next := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	r := &Reminder{UserID: "u", ChannelID: "c", Message: "standup", Schedule: ScheduleDaily, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: next}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got, ok, err := service.AcknowledgeReminder(ctx, r.ID, "u")
+	if err != nil || !ok {
+		t.Fatalf("acknowledge: ok=%v err=%v", ok, err)
+	}
+	if !got.NextRun.Equal(next) {
+		t.Fatalf("next_run = %v, want %v", got.NextRun, next)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("recurring reminder should remain: %+v err=%v", list, err)
+	}
+}
+
+func TestClaimDeliveryIdempotency(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &Reminder{UserID: "u", ChannelID: "c", Message: "hello", Schedule: ScheduleHourly, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	first, ok, err := store.ClaimDelivery(ctx, *r)
+	if err != nil || !ok {
+		t.Fatalf("first claim: ok=%v err=%v", ok, err)
+	}
+	// A fresh claim is in flight elsewhere; only an abandoned one is taken over.
+	if inFlight, ok, err := store.ClaimDelivery(ctx, *r); err != nil || ok || inFlight.Status != DeliveryClaimed {
+		t.Fatalf("claim of in-flight run = %+v ok=%v err=%v", inFlight, ok, err)
+	}
+	stale := now.Add(-DeliveryClaimTimeout - time.Second).Unix()
+	if _, err := db.Exec(`UPDATE reminder_deliveries SET updated_at = ? WHERE id = ?`, stale, first.ID); err != nil {
+		t.Fatalf("age claim: %v", err)
+	}
+	if first, ok, err = store.ClaimDelivery(ctx, *r); err != nil || !ok || first.Attempts != 2 {
+		t.Fatalf("claim of abandoned run = %+v ok=%v err=%v", first, ok, err)
+	}
+	if err := store.FailDelivery(ctx, first, DeliveryFailure{Error: "boom", FailureCount: 1}); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	retry, ok, err := store.ClaimDelivery(ctx, *r)
+	if err != nil || !ok || retry.ID != first.ID || retry.Attempts != 3 {
+		t.Fatalf("retry claim = %+v ok=%v err=%v, want same row with 3 attempts", retry, ok, err)
+	}
+	if err := store.FinishDelivery(ctx, retry, Receipt{Via: DeliveryChannel}, now.Add(time.Hour), true); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+
+	// The old run is no longer what the reminder points at.
+	if _, ok, err := store.ClaimDelivery(ctx, *r); err != nil || ok {
+		t.Fatalf("claim of stale run: ok=%v err=%v", ok, err)
+	}
+	rescheduled, _, err := store.GetOwned(ctx, r.ID, "u")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !rescheduled.NextRun.Equal(now.Add(time.Hour)) {
+		t.Fatalf("next_run = %v, want %v", rescheduled.NextRun, now.Add(time.Hour))
+	}
+}
+
 func TestNextAfter(t *testing.T) {
 	now := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
 	hourly := Reminder{Schedule: ScheduleHourly}
@@ -170,3 +331,445 @@ func TestNormalizeScheduleUsesTimezone(t *testing.T) {
 		t.Fatalf("next_run = %v, want %v", got.NextRun, want)
 	}
 }
+
+func TestUpdateReminderPartial(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+
+	created, err := service.CreateReminder(ctx, CreateReminderInput{UserID: "u", ChannelID: "c", Message: "standup", Schedule: "daily", At: "09:00", Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	message := "team standup"
+	updated, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u", Message: &message})
+	if err != nil || !ok {
+		t.Fatalf("update message: ok=%v err=%v", ok, err)
+	}
+	if updated.ID != created.ID || updated.Message != message || !updated.NextRun.Equal(created.NextRun) || updated.CronExpr != created.CronExpr {
+		t.Fatalf("message-only update changed the schedule: %+v", updated)
+	}
+
+	// Changing only the timezone keeps the daily time of day.
+	timezone := "Asia/Kolkata"
+	updated, ok, err = service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u", Timezone: &timezone})
+	if err != nil || !ok {
+		t.Fatalf("update timezone: ok=%v err=%v", ok, err)
+	}
+	if updated.Timezone != timezone || updated.CronExpr != "0 9 * * *" {
+		t.Fatalf("timezone update = %+v", updated)
+	}
+	if local := updated.NextRun.In(mustLoadLocation(t, timezone)); local.Hour() != 9 || local.Minute() != 0 {
+		t.Fatalf("next run %v is not 09:00 in %s", updated.NextRun, timezone)
+	}
+
+	schedule, at := "hourly", ":15"
+	updated, ok, err = service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u", Schedule: &schedule, At: &at})
+	if err != nil || !ok {
+		t.Fatalf("update schedule: ok=%v err=%v", ok, err)
+	}
+	if updated.Schedule != ScheduleHourly || updated.CronExpr != "15 * * * *" || updated.NextRun.In(mustLoadLocation(t, timezone)).Minute() != 15 {
+		t.Fatalf("schedule update = %+v", updated)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 || list[0].Message != message || list[0].CronExpr != "15 * * * *" {
+		t.Fatalf("stored reminder = %+v err=%v", list, err)
+	}
+
+	if _, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "other", Message: &message}); err != nil || ok {
+		t.Fatalf("update by non-owner: ok=%v err=%v", ok, err)
+	}
+	daily, bad := "daily", "25:00"
+	if _, _, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u", Schedule: &daily, At: &bad}); err == nil {
+		t.Fatal("expected invalid time to be rejected")
+	}
+}
+
+func TestUpdateReminderReactivatesCompletedReminder(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	r := &Reminder{UserID: "u", ChannelID: "c", Message: "stretch", Schedule: ScheduleOnce, Once: true, Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := store.Complete(ctx, r.ID, now); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	message := "stretch again"
+	if _, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: r.ID, UserID: "u", Message: &message}); err != nil || !ok {
+		t.Fatalf("update message: ok=%v err=%v", ok, err)
+	}
+	if list, err := store.ListByUser(ctx, "u"); err != nil || len(list) != 0 {
+		t.Fatalf("message edit should not reactivate a completed reminder: %+v err=%v", list, err)
+	}
+
+	at := "2h"
+	if _, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: r.ID, UserID: "u", At: &at}); err != nil || !ok {
+		t.Fatalf("reschedule: ok=%v err=%v", ok, err)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 || list[0].Message != message || !list[0].NextRun.After(now.Add(time.Hour)) {
+		t.Fatalf("rescheduled reminder = %+v err=%v", list, err)
+	}
+}
+
+func TestUpdateReminderRejectsEndedSchedule(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Retired after its last counted run.
+	usedUp := &Reminder{UserID: "u", ChannelID: "c", Message: "standup", Schedule: ScheduleCron, CronExpr: "0 9 * * *", Timezone: "UTC", NextRun: now,
+		RemainingRuns: sql.NullInt64{Int64: 0, Valid: true}}
+	if err := store.Create(ctx, usedUp); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := store.Complete(ctx, usedUp.ID, now); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	cron := "0 10 * * *"
+	if _, _, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: usedUp.ID, UserID: "u", CronExpr: &cron}); err == nil || !strings.Contains(err.Error(), "has ended") {
+		t.Fatalf("reschedule of used-up reminder: err=%v, want it to say the reminder ended", err)
+	}
+
+	// Still active, but the new schedule's next run falls after ends_at.
+	ending := &Reminder{UserID: "u", ChannelID: "c", Message: "water plants", Schedule: ScheduleCron, CronExpr: "0 9 * * *", Timezone: "UTC",
+		NextRun: now.Add(time.Hour), EndsAt: now.Add(48 * time.Hour)}
+	if err := store.Create(ctx, ending); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	yearly := "0 9 1 1 *"
+	if _, _, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: ending.ID, UserID: "u", CronExpr: &yearly}); err == nil || !strings.Contains(err.Error(), "ends on") {
+		t.Fatalf("reschedule past ends_at: err=%v, want it to name the end date", err)
+	}
+
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 || list[0].ID != ending.ID || list[0].CronExpr != "0 9 * * *" {
+		t.Fatalf("reminders after rejected edits = %+v err=%v, want only the unchanged active one", list, err)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestPauseAndResumeReminder(t *testing.T) {
+	db := openTestDB(t)
+	store := NewStore(db)
+	service := NewService(store)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &Reminder{UserID: "u", ChannelID: "c", Message: "water plants", Schedule: ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now.Add(-3 * time.Hour)}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, ok, err := service.PauseReminder(ctx, r.ID, "other", time.Time{}); err != nil || ok {
+		t.Fatalf("pause by non-owner: ok=%v err=%v", ok, err)
+	}
+	if _, _, err := service.PauseReminder(ctx, r.ID, "u", now.Add(-time.Minute)); err == nil {
+		t.Fatal("expected pause-until in the past to be rejected")
+	}
+	paused, ok, err := service.PauseReminder(ctx, r.ID, "u", time.Time{})
+	if err != nil || !ok || !paused.Paused {
+		t.Fatalf("pause: %+v ok=%v err=%v", paused, ok, err)
+	}
+	due, err := store.Due(ctx, now, 10)
+	if err != nil || len(due) != 0 {
+		t.Fatalf("paused reminder should not be due: %+v err=%v", due, err)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 || !list[0].Paused {
+		t.Fatalf("paused reminder should be listed as paused: %+v err=%v", list, err)
+	}
+
+	resumed, ok, err := service.ResumeReminder(ctx, r.ID, "u")
+	if err != nil || !ok {
+		t.Fatalf("resume: ok=%v err=%v", ok, err)
+	}
+	// Runs that fell inside the pause are skipped, not caught up.
+	if resumed.Paused || !resumed.NextRun.After(now) || resumed.NextRun.Minute() != 0 {
+		t.Fatalf("resumed reminder = %+v", resumed)
+	}
+	if _, _, err := service.ResumeReminder(ctx, r.ID, "u"); err == nil {
+		t.Fatal("expected resuming an active reminder to fail")
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in       string
+		timezone string
+		want     time.Time
+		wantErr  bool
+	}{
+		{in: "3d", timezone: "UTC", want: now.Add(72 * time.Hour)},
+		{in: "2026-08-01", timezone: "Asia/Kolkata", want: time.Date(2026, 7, 31, 18, 30, 0, 0, time.UTC)},
+		{in: "2026-07-02 09:00", timezone: "UTC", want: time.Date(2026, 7, 2, 9, 0, 0, 0, time.UTC)},
+		{in: "2026-06-01", timezone: "UTC", wantErr: true},
+		{in: "next week", timezone: "UTC", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUntil(now, tt.in, tt.timezone)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("ParseUntil(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Fatalf("ParseUntil(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestCronForSchedule(t *testing.T) {
+	now := time.Date(2026, 1, 1, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   normalizeInput
+		want    string
+		wantErr bool
+	}{
+		{name: "daily", input: normalizeInput{Schedule: ScheduleDaily, At: "09:00"}, want: "0 9 * * *"},
+		{name: "weekly names", input: normalizeInput{Schedule: ScheduleWeekly, At: "18:15", Weekdays: "mon, wed,fri"}, want: "15 18 * * 1,3,5"},
+		{name: "weekly weekends", input: normalizeInput{Schedule: ScheduleWeekly, At: "10:00", Weekdays: "weekends"}, want: "0 10 * * 0,6"},
+		{name: "weekly numbers", input: normalizeInput{Schedule: ScheduleWeekly, At: "10:00", Weekdays: "7,2"}, want: "0 10 * * 0,2"},
+		{name: "weekly no days", input: normalizeInput{Schedule: ScheduleWeekly, At: "10:00"}, wantErr: true},
+		{name: "weekly bad day", input: normalizeInput{Schedule: ScheduleWeekly, At: "10:00", Weekdays: "funday"}, wantErr: true},
+		{name: "monthly", input: normalizeInput{Schedule: ScheduleMonthly, At: "07:45", DayOfMonth: 15}, want: "45 7 15 * *"},
+		{name: "monthly bad day", input: normalizeInput{Schedule: ScheduleMonthly, At: "07:45", DayOfMonth: 32}, wantErr: true},
+		{name: "every minute", input: normalizeInput{Schedule: ScheduleInterval, Every: "1m"}, want: "* * * * *"},
+		{name: "every 15 minutes", input: normalizeInput{Schedule: ScheduleInterval, Every: "15m"}, want: "*/15 * * * *"},
+		{name: "every hour", input: normalizeInput{Schedule: ScheduleInterval, Every: "1h"}, want: "0 * * * *"},
+		{name: "every 6 hours", input: normalizeInput{Schedule: ScheduleInterval, Every: "6h"}, want: "0 */6 * * *"},
+		{name: "uneven minutes", input: normalizeInput{Schedule: ScheduleInterval, Every: "7m"}, wantErr: true},
+		{name: "uneven hours", input: normalizeInput{Schedule: ScheduleInterval, Every: "5h"}, wantErr: true},
+		{name: "cron", input: normalizeInput{Schedule: ScheduleCron, CronExpr: "0 9 * * 1-5"}, want: "0 9 * * 1-5"},
+		{name: "bad cron", input: normalizeInput{Schedule: ScheduleCron, CronExpr: "every day"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cronForSchedule(now, tt.input, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("cronForSchedule = %q, want error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("cronForSchedule = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpcomingRunsWeeklyInTimezone(t *testing.T) {
+	// 2026-01-01 is a Thursday.
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, err := normalizeSchedule(now, normalizeInput{
+		Schedule: ScheduleWeekly,
+		At:       "09:00",
+		Weekdays: "mon,thu",
+		Timezone: "Asia/Kolkata",
+	})
+	if err != nil {
+		t.Fatalf("normalizeSchedule: %v", err)
+	}
+	r := Reminder{Schedule: ScheduleWeekly, AtTime: got.AtTime, CronExpr: got.CronExpr, Timezone: got.Timezone, NextRun: got.NextRun}
+	runs, err := UpcomingRuns(r, 5)
+	if err != nil {
+		t.Fatalf("UpcomingRuns: %v", err)
+	}
+	loc := mustLoadLocation(t, "Asia/Kolkata")
+	want := []time.Time{
+		time.Date(2026, 1, 1, 9, 0, 0, 0, loc),
+		time.Date(2026, 1, 5, 9, 0, 0, 0, loc),
+		time.Date(2026, 1, 8, 9, 0, 0, 0, loc),
+		time.Date(2026, 1, 12, 9, 0, 0, 0, loc),
+		time.Date(2026, 1, 15, 9, 0, 0, 0, loc),
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("runs = %v, want %v", runs, want)
+	}
+	for idx := range want {
+		if !runs[idx].Equal(want[idx]) {
+			t.Fatalf("run %d = %v, want %v", idx, runs[idx], want[idx])
+		}
+	}
+
+	once := Reminder{Schedule: ScheduleOnce, Once: true, NextRun: now}
+	if runs, err := UpcomingRuns(once, 5); err != nil || len(runs) != 1 {
+		t.Fatalf("once runs = %v, %v; want one run", runs, err)
+	}
+}
+
+func TestUpdateReminderKeepsWeeklyDays(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	created, err := service.CreateReminder(ctx, CreateReminderInput{
+		UserID:    "u1",
+		ChannelID: "c1",
+		Message:   "standup",
+		Schedule:  string(ScheduleWeekly),
+		At:        "09:00",
+		Weekdays:  "tue,thu",
+		Timezone:  "UTC",
+	})
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+
+	at := "10:30"
+	updated, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", At: &at})
+	if err != nil || !ok {
+		t.Fatalf("UpdateReminder: %v, %v", ok, err)
+	}
+	if updated.CronExpr != "30 10 * * 2,4" {
+		t.Fatalf("cron = %q, want 30 10 * * 2,4", updated.CronExpr)
+	}
+}
+
+func TestCreateReminderEndConditions(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	base := CreateReminderInput{UserID: "u1", ChannelID: "c1", Message: "water", Schedule: string(ScheduleCron), CronExpr: "0 * * * *", Timezone: "UTC"}
+
+	input := base
+	input.EndsAt = "2099-01-31"
+	input.RemainingRuns = 3
+	created, err := service.CreateReminder(ctx, input)
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+	wantEnd := time.Date(2099, 1, 31, 23, 59, 59, 0, time.UTC)
+	if !created.EndsAt.Equal(wantEnd) || !created.RemainingRuns.Valid || created.RemainingRuns.Int64 != 3 {
+		t.Fatalf("end conditions = %v, %+v; want %v and 3 runs", created.EndsAt, created.RemainingRuns, wantEnd)
+	}
+	list, err := service.ListUserReminders(ctx, "u1")
+	if err != nil || len(list) != 1 || !list[0].EndsAt.Equal(wantEnd) || list[0].RemainingRuns.Int64 != 3 {
+		t.Fatalf("stored reminder = %+v err=%v", list, err)
+	}
+
+	once := base
+	once.Schedule, once.CronExpr, once.At, once.RemainingRuns = string(ScheduleOnce), "", "10m", 2
+	if _, err := service.CreateReminder(ctx, once); err == nil {
+		t.Fatalf("expected error for run limit on a one-time reminder")
+	}
+
+	early := base
+	early.CronExpr, early.EndsAt = "0 9 1 1 *", "1h"
+	if _, err := service.CreateReminder(ctx, early); err == nil {
+		t.Fatalf("expected error for end date before the first run")
+	}
+}
+
+func TestUpdateReminderChangesScheduleDetails(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	created, err := service.CreateReminder(ctx, CreateReminderInput{
+		UserID:    "u1",
+		ChannelID: "c1",
+		Message:   "standup",
+		Schedule:  string(ScheduleWeekly),
+		At:        "09:00",
+		Weekdays:  "tue,thu",
+		Timezone:  "UTC",
+	})
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+
+	days := "mon,fri"
+	updated, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", Weekdays: &days})
+	if err != nil || !ok || updated.CronExpr != "0 9 * * 1,5" {
+		t.Fatalf("weekly days update = %q, %v, %v", updated.CronExpr, ok, err)
+	}
+
+	monthly, day, at := string(ScheduleMonthly), 15, "08:00"
+	updated, ok, err = service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", Schedule: &monthly, DayOfMonth: &day, At: &at})
+	if err != nil || !ok || updated.CronExpr != "0 8 15 * *" {
+		t.Fatalf("monthly update = %q, %v, %v", updated.CronExpr, ok, err)
+	}
+
+	interval, every := string(ScheduleInterval), "30m"
+	updated, ok, err = service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", Schedule: &interval, Every: &every})
+	if err != nil || !ok || updated.CronExpr != "*/30 * * * *" {
+		t.Fatalf("interval update = %q, %v, %v", updated.CronExpr, ok, err)
+	}
+	every = "2h"
+	updated, ok, err = service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", Every: &every})
+	if err != nil || !ok || updated.CronExpr != "0 */2 * * *" {
+		t.Fatalf("interval every update = %q, %v, %v", updated.CronExpr, ok, err)
+	}
+}
+
+type fakeChannelAccess struct {
+	denyChannel, denyMentions string
+	mentionChecks             []MentionTarget
+}
+
+func (f *fakeChannelAccess) CheckChannel(_, channelID, _ string) error {
+	if channelID == f.denyChannel {
+		return errors.New("no access")
+	}
+	return nil
+}
+
+func (f *fakeChannelAccess) CheckMentions(_, channelID, _ string, target MentionTarget) error {
+	f.mentionChecks = append(f.mentionChecks, target)
+	if channelID == f.denyMentions && !target.IsZero() {
+		return errors.New("no mentions")
+	}
+	return nil
+}
+
+func TestUpdateReminderChecksNewChannel(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	access := &fakeChannelAccess{denyChannel: "private", denyMentions: "quiet"}
+	service.SetChannelAccess(access)
+	created, err := service.CreateReminder(ctx, CreateReminderInput{
+		UserID:    "u1",
+		ChannelID: "c1",
+		GuildID:   "g1",
+		Message:   "raid night",
+		Schedule:  string(ScheduleDaily),
+		At:        "20:00",
+		Timezone:  "UTC",
+		Mentions:  MentionTarget{Roles: []string{"111"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+
+	for _, channel := range []string{"private", "quiet"} {
+		if _, _, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", ChannelID: &channel}); err == nil {
+			t.Fatalf("expected moving to %s to fail", channel)
+		}
+	}
+	if len(access.mentionChecks) != 1 || access.mentionChecks[0].Roles[0] != "111" {
+		t.Fatalf("mention checks = %+v, want the reminder's role checked once", access.mentionChecks)
+	}
+	open := "c2"
+	updated, ok, err := service.UpdateReminder(ctx, UpdateReminderInput{ID: created.ID, UserID: "u1", ChannelID: &open})
+	if err != nil || !ok || updated.ChannelID != "c2" {
+		t.Fatalf("UpdateReminder = %q, %v, %v", updated.ChannelID, ok, err)
+	}
+}
@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mizubot-go/internal/data"
+)
+
+// GetShared returns an active shared reminder by ID.
+func (s *Store) GetShared(ctx context.Context, id int64) (Reminder, bool, error) {
+	row, err := s.q.GetSharedReminder(ctx, s.db, id)
+	if err == sql.ErrNoRows {
+		return Reminder{}, false, nil
+	}
+	if err != nil {
+		return Reminder{}, false, err
+	}
+	return convertGetSharedReminderRow(row), true, nil
+}
+
+// Subscribe adds userID to a reminder's subscribers. It returns false when
+// the user was already subscribed.
+func (s *Store) Subscribe(ctx context.Context, reminderID int64, userID string) (bool, error) {
+	n, err := s.q.AddReminderSubscriber(ctx, s.db, data.AddReminderSubscriberParams{
+		ReminderID: reminderID,
+		UserID:     userID,
+		CreatedAt:  time.Now().UTC().Unix(),
+	})
+	return n > 0, err
+}
+
+// Unsubscribe removes userID from a reminder's subscribers. It returns false
+// when the user was not subscribed.
+func (s *Store) Unsubscribe(ctx context.Context, reminderID int64, userID string) (bool, error) {
+	n, err := s.q.RemoveReminderSubscriber(ctx, s.db, reminderID, userID)
+	return n > 0, err
+}
+
+// Subscribers lists the user IDs subscribed to a reminder, oldest first.
+func (s *Store) Subscribers(ctx context.Context, reminderID int64) ([]string, error) {
+	return s.q.ListReminderSubscribers(ctx, s.db, reminderID)
+}
@@ -0,0 +1,71 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSharedReminderSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(openTestDB(t))
+	service := NewService(store)
+	input := CreateReminderInput{UserID: "owner", ChannelID: "c1", GuildID: "g1", Message: "raid night", Schedule: string(ScheduleDaily), At: "20:00", Timezone: "UTC", Shared: true}
+	r, err := service.CreateReminder(ctx, input)
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+
+	if _, found, _, err := service.SubscribeReminder(ctx, r.ID, "g2", "u1"); err != nil || found {
+		t.Fatalf("subscribe from another guild found=%v err=%v, want not found", found, err)
+	}
+	if _, found, added, err := service.SubscribeReminder(ctx, r.ID, "g1", "u1"); err != nil || !found || !added {
+		t.Fatalf("subscribe found=%v added=%v err=%v", found, added, err)
+	}
+	if _, _, added, err := service.SubscribeReminder(ctx, r.ID, "g1", "u1"); err != nil || added {
+		t.Fatalf("duplicate subscribe added=%v err=%v, want false", added, err)
+	}
+	subs, err := store.Subscribers(ctx, r.ID)
+	if err != nil || len(subs) != 2 || subs[0] != "owner" || subs[1] != "u1" {
+		t.Fatalf("subscribers = %v err=%v, want [owner u1]", subs, err)
+	}
+
+	if _, _, removed, err := service.UnsubscribeReminder(ctx, r.ID, "g1", "u1"); err != nil || !removed {
+		t.Fatalf("unsubscribe removed=%v err=%v", removed, err)
+	}
+	if _, _, removed, err := service.UnsubscribeReminder(ctx, r.ID, "g1", "u1"); err != nil || removed {
+		t.Fatalf("second unsubscribe removed=%v err=%v, want false", removed, err)
+	}
+
+	if _, err := service.DeleteReminder(ctx, r.ID, "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if subs, err := store.Subscribers(ctx, r.ID); err != nil || len(subs) != 0 {
+		t.Fatalf("subscribers after delete = %v err=%v, want none", subs, err)
+	}
+}
+
+func TestCreateSharedReminderValidation(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(NewStore(openTestDB(t)))
+	base := CreateReminderInput{UserID: "owner", ChannelID: "c1", GuildID: "g1", Message: "m", Schedule: string(ScheduleDaily), At: "20:00", Timezone: "UTC", Shared: true}
+
+	noGuild := base
+	noGuild.GuildID = ""
+	if _, err := service.CreateReminder(ctx, noGuild); err == nil {
+		t.Fatalf("expected error for shared reminder outside a server")
+	}
+	dm := base
+	dm.DeliveryMode = string(DeliveryDM)
+	if _, err := service.CreateReminder(ctx, dm); err == nil {
+		t.Fatalf("expected error for shared reminder delivered by DM")
+	}
+	private := base
+	private.Shared = false
+	r, err := service.CreateReminder(ctx, private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _, err := service.SubscribeReminder(ctx, r.ID, "g1", "u1"); err != nil || found {
+		t.Fatalf("subscribe to private reminder found=%v err=%v, want not found", found, err)
+	}
+}
@@ -0,0 +1,174 @@
+package reminders
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+)
+
+// Reminder messages may use template variables that are filled in when the
+// reminder is delivered, in the reminder's timezone:
+//
+//	{{date}}                   Jan 2, 2006
+//	{{weekday}}                Monday
+//	{{count}}                  occurrence number, starting at 1
+//	{{days_until "2026-12-25"}} whole days from the run date to that date
+//	{{user}}                   the owner as a mention
+//
+// Only messages that use one of these variables are templates; anything
+// else, including text with a stray "{{", is sent as written. Nothing else
+// of text/template is allowed: no pipelines, variables, control structures
+// or builtins such as printf, so a message can't render much longer than it
+// was written.
+
+// MaxMessageLength caps a reminder message as delivered, leaving room for
+// the mentions and notes sent with it within Discord's 2000 characters.
+const MaxMessageLength = 1800
+
+// templateVars is what a message template is rendered against.
+type templateVars struct {
+	run   time.Time // in the reminder's timezone
+	count int64
+	user  string
+}
+
+func (v templateVars) funcs() template.FuncMap {
+	return template.FuncMap{
+		"date":    func() string { return v.run.Format("Jan 2, 2006") },
+		"weekday": func() string { return v.run.Weekday().String() },
+		"count":   func() int64 { return v.count },
+		"user":    func() string { return "<@" + v.user + ">" },
+		"days_until": func(date string) (int, error) {
+			target, err := time.ParseInLocation("2006-01-02", date, v.run.Location())
+			if err != nil {
+				return 0, fmt.Errorf("days_until wants a date like \"2026-12-25\", got %q", date)
+			}
+			day := time.Date(v.run.Year(), v.run.Month(), v.run.Day(), 0, 0, 0, 0, v.run.Location())
+			// Round to absorb DST shifts between the two midnights.
+			return int(target.Sub(day).Round(24*time.Hour) / (24 * time.Hour)), nil
+		},
+	}
+}
+
+// templateVarPattern finds an action that starts with a template variable.
+var templateVarPattern = regexp.MustCompile(`\{\{-?\s*(date|weekday|count|days_until|user)\b`)
+
+func isTemplate(message string) bool {
+	return templateVarPattern.MatchString(message)
+}
+
+func renderTemplate(message string, vars templateVars) (string, error) {
+	tmpl, err := template.New("reminder").Option("missingkey=error").Funcs(vars.funcs()).Parse(message)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTemplateNodes(tmpl.Tree.Root); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, struct{}{}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// templateArgs is how many arguments each template variable takes.
+var templateArgs = map[string]int{"date": 0, "weekday": 0, "count": 0, "user": 0, "days_until": 1}
+
+// checkTemplateNodes allows only text and single calls of the template
+// variables with literal string arguments.
+func checkTemplateNodes(list *parse.ListNode) error {
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+		case *parse.ActionNode:
+			if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 {
+				return fmt.Errorf("%s: only single variables are supported", n)
+			}
+			args := n.Pipe.Cmds[0].Args
+			ident, ok := args[0].(*parse.IdentifierNode)
+			if !ok {
+				return fmt.Errorf("%s isn't a reminder variable", n)
+			}
+			want, ok := templateArgs[ident.Ident]
+			if !ok {
+				return fmt.Errorf("%s isn't a reminder variable", n)
+			}
+			if len(args)-1 != want {
+				return fmt.Errorf("%s takes %d arguments", ident.Ident, want)
+			}
+			for _, arg := range args[1:] {
+				if _, ok := arg.(*parse.StringNode); !ok {
+					return fmt.Errorf("%s wants a quoted date", ident.Ident)
+				}
+			}
+		default:
+			return fmt.Errorf("%s isn't supported", n)
+		}
+	}
+	return nil
+}
+
+// sampleTemplateVars gives variables their longest typical values, so a
+// sample render shows about how long the message gets when delivered.
+var sampleTemplateVars = templateVars{
+	run:   time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC), // a Wednesday in September
+	count: 100000,
+	user:  "12345678901234567890",
+}
+
+// ValidateMessage checks a reminder message's template variables and its
+// length by rendering it once, so mistakes surface when the reminder is
+// created rather than when it fires.
+func ValidateMessage(message string) error {
+	rendered := message
+	if isTemplate(message) {
+		var err error
+		rendered, err = renderTemplate(message, sampleTemplateVars)
+		if err != nil {
+			return errors.New("Invalid reminder template: " + templateErrorText(err) + ". Use {{date}}, {{weekday}}, {{count}}, {{days_until \"2026-12-25\"}}, or {{user}}.")
+		}
+	}
+	if n := utf8.RuneCountInString(rendered); n > MaxMessageLength {
+		return fmt.Errorf("Reminder messages can be at most %d characters once filled in; this one is %d.", MaxMessageLength, n)
+	}
+	return nil
+}
+
+// RenderMessage fills in the template variables of r.Message for the run at
+// r.NextRun, which is delivery number r.RunCount+1.
+func RenderMessage(r Reminder) (string, error) {
+	if !isTemplate(r.Message) {
+		return r.Message, nil
+	}
+	loc, err := time.LoadLocation(timezoneOrUTC(r.Timezone))
+	if err != nil {
+		return "", err
+	}
+	rendered, err := renderTemplate(r.Message, templateVars{run: r.NextRun.In(loc), count: r.RunCount + 1, user: r.UserID})
+	if err != nil {
+		return "", err
+	}
+	// Checked on create with sample values; a real run can still come out
+	// longer, e.g. a large days_until.
+	if runes := []rune(rendered); len(runes) > MaxMessageLength {
+		rendered = string(runes[:MaxMessageLength-1]) + "…"
+	}
+	return rendered, nil
+}
+
+// templateErrorText strips text/template's "template: reminder:1:" prefix.
+func templateErrorText(err error) string {
+	msg := err.Error()
+	if _, rest, ok := strings.Cut(msg, "template: reminder:"); ok {
+		if idx := strings.Index(rest, ": "); idx >= 0 {
+			return rest[idx+2:]
+		}
+	}
+	return msg
+}
@@ -0,0 +1,57 @@
+package reminders
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMessage(t *testing.T) {
+	// 2026-12-20 20:00 UTC is already Monday 2026-12-21 in Tokyo.
+	r := Reminder{
+		UserID:   "42",
+		Timezone: "Asia/Tokyo",
+		NextRun:  time.Date(2026, 12, 20, 20, 0, 0, 0, time.UTC),
+		RunCount: 2,
+	}
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: "plain text {not a template}", want: "plain text {not a template}"},
+		{message: "{{weekday}} {{date}}", want: "Monday Dec 21, 2026"},
+		{message: "standup #{{count}} for {{user}}", want: "standup #3 for <@42>"},
+		{message: `{{days_until "2026-12-25"}} days to go`, want: "4 days to go"},
+		{message: `{{days_until "2026-12-01"}}`, want: "-20"},
+		{message: "{{ user }}, {{- weekday}}", want: "<@42>,Monday"},
+		// Text that merely contains "{{", as messages written before
+		// templates existed may, is sent unchanged.
+		{message: "fix the {{ placeholder", want: "fix the {{ placeholder"},
+		{message: "rename {{tomorrow}} to {{.Name}}", want: "rename {{tomorrow}} to {{.Name}}"},
+		{message: "{{$x := 1}}", want: "{{$x := 1}}"},
+	}
+	for _, tt := range tests {
+		r.Message = tt.message
+		got, err := RenderMessage(r)
+		if err != nil || got != tt.want {
+			t.Fatalf("RenderMessage(%q) = %q, %v; want %q", tt.message, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidateMessage(t *testing.T) {
+	valid := []string{"", "hello", "{{date}}", `{{days_until "2027-01-01"}} left`, "fix the {{ placeholder", "{{tomorrow}}", "{{dates}}"}
+	for _, message := range valid {
+		if err := ValidateMessage(message); err != nil {
+			t.Fatalf("ValidateMessage(%q) = %v, want nil", message, err)
+		}
+	}
+	invalid := []string{"{{date", "{{date}} {{tomorrow}}", `{{days_until "christmas"}}`, "{{days_until}}", "{{.Name}} {{count}}",
+		`{{printf "%0999d" 1}} {{date}}`, "{{range .}}{{date}}{{end}}", "{{date | print}}", "{{weekday}} {{$x := date}}", `{{days_until (date)}}`,
+		"{{user}} fix the {{ placeholder", strings.Repeat("x", MaxMessageLength+1), strings.Repeat("{{user}}", 80)}
+	for _, message := range invalid {
+		if err := ValidateMessage(message); err == nil {
+			t.Fatalf("ValidateMessage(%q) = nil, want error", message)
+		}
+	}
+}
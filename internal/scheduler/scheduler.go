@@ -5,17 +5,41 @@ import (
 	"log"
 	"time"
 
+	"mizubot-go/internal/lifecycle"
+	"mizubot-go/internal/metrics"
 	"mizubot-go/internal/reminders"
 )
 
-type Sender func(reminder reminders.Reminder) error
+// Sender delivers a reminder and reports where it went.
+type Sender func(reminder reminders.Reminder) (reminders.Receipt, error)
+
+// DeadLetterSender tells a reminder's owner that it stopped retrying. The
+// reminder passed in has DeadAt and LastError set.
+type DeadLetterSender func(reminder reminders.Reminder) error
+
+const (
+	// completedRetention is how long delivered one-time reminders are kept so
+	// their snooze buttons keep working.
+	completedRetention = 7 * 24 * time.Hour
+	// deliveryRetention is how long delivery ledger rows are kept for the
+	// per-reminder audit history.
+	deliveryRetention = 30 * 24 * time.Hour
+
+	// maxSendFailures is how many consecutive failed sends a reminder gets
+	// before it is marked dead. Retries back off exponentially from
+	// retryBaseDelay up to retryMaxDelay.
+	maxSendFailures = 6
+	retryBaseDelay  = time.Minute
+	retryMaxDelay   = time.Hour
+)
 
 type Scheduler struct {
-	store   *reminders.Store
-	sender  Sender
-	ticker  *time.Ticker
-	every   time.Duration
-	dueLoad int
+	store      *reminders.Store
+	sender     Sender
+	deadLetter DeadLetterSender
+	loop       lifecycle.Loop
+	every      time.Duration
+	dueLoad    int
 }
 
 func New(store *reminders.Store, sender Sender, every time.Duration) *Scheduler {
@@ -25,46 +49,186 @@ func New(store *reminders.Store, sender Sender, every time.Duration) *Scheduler
 	return &Scheduler{store: store, sender: sender, every: every, dueLoad: 50}
 }
 
+// SetDeadLetterSender registers who to notify when a reminder is marked dead.
+func (s *Scheduler) SetDeadLetterSender(fn DeadLetterSender) { s.deadLetter = fn }
+
 func (s *Scheduler) Start(ctx context.Context) {
-	s.ticker = time.NewTicker(s.every)
-	go func() {
-		defer s.ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-s.ticker.C:
-				s.runOnce(ctx, time.Now().UTC())
-			}
-		}
-	}()
+	s.loop.Start(ctx, s.every, func(ctx context.Context) {
+		s.runOnce(ctx, time.Now().UTC())
+	})
+}
+
+// Stop stops scheduling new ticks and waits for the current one, so a
+// reminder being sent is recorded before shutdown. It returns ctx.Err()
+// if the tick doesn't finish in time.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	return s.loop.Stop(ctx)
 }
 
 func (s *Scheduler) runOnce(ctx context.Context, now time.Time) {
+	defer metrics.PollerTickSeconds.ObserveSince(time.Now(), "scheduler")
 	// normalize to UTC
 	now = now.UTC()
+	if _, err := s.store.PurgeCompleted(ctx, now.Add(-completedRetention)); err != nil {
+		log.Printf("scheduler purge error: %v", err)
+	}
+	if _, err := s.store.PurgeDeliveries(ctx, now.Add(-deliveryRetention)); err != nil {
+		log.Printf("scheduler delivery purge error: %v", err)
+	}
+	resumed, err := s.store.ResumeExpired(ctx, now, s.dueLoad)
+	if err != nil {
+		log.Printf("scheduler resume error: %v", err)
+	}
+	for _, r := range resumed {
+		log.Printf("reminder %d resumed after pause; next run %s", r.ID, r.NextRun.Format(time.RFC3339))
+	}
 	due, err := s.store.Due(ctx, now, s.dueLoad)
 	if err != nil {
 		log.Printf("scheduler load error: %v", err)
 		return
 	}
 	for _, r := range due {
-		if err := s.sender(r); err != nil {
-			log.Printf("send error for reminder %d: %v", r.ID, err)
-			continue
+		s.deliver(ctx, r, now)
+	}
+}
+
+// deliver sends one due reminder with at-least-once semantics: the run is
+// claimed in the delivery ledger before sending, and marked sent together
+// with the reminder's reschedule afterwards. A crash between the send and
+// that update causes a resend on restart; a run already marked sent is
+// never sent again.
+func (s *Scheduler) deliver(ctx context.Context, r reminders.Reminder, now time.Time) {
+	r, ok := s.catchUp(ctx, r, now)
+	if !ok {
+		return
+	}
+	if reminders.EndReached(r) {
+		// Runs past the end date (e.g. caught up after downtime) are dropped.
+		log.Printf("reminder %d reached its end condition; retiring", r.ID)
+		if err := s.store.Complete(ctx, r.ID, now); err != nil {
+			log.Printf("retire error for reminder %d: %v", r.ID, err)
 		}
-		// reschedule or delete
-		next, repeat, err := reminders.NextAfter(r, now)
-		if err != nil {
-			log.Printf("reschedule error for reminder %d: %v", r.ID, err)
-			// best-effort delete to prevent tight loop
-			_ = s.store.DeleteID(ctx, r.ID)
-			continue
+		return
+	}
+
+	// reschedule or complete; decided before sending so the final delivery
+	// of a reminder with an end condition can say so.
+	next, repeat, err := reminders.NextAfter(r, reminders.RescheduleFrom(r, now))
+	if err != nil {
+		log.Printf("reschedule error for reminder %d: %v", r.ID, err)
+		// complete it so a broken schedule can't cause a tight loop
+		repeat = false
+	} else if repeat && !reminders.ContinuesAfter(r, next) {
+		repeat = false
+		r.LastRun = true
+	}
+
+	delivery, claimed, err := s.store.ClaimDelivery(ctx, r)
+	if err != nil {
+		log.Printf("claim error for reminder %d: %v", r.ID, err)
+		return
+	}
+	if r.Shared && claimed {
+		if r.Subscribers, err = s.store.Subscribers(ctx, r.ID); err != nil {
+			log.Printf("load subscribers error for reminder %d: %v", r.ID, err)
+			return
 		}
-		if !repeat {
-			_ = s.store.DeleteID(ctx, r.ID)
-			continue
+	}
+	receipt := reminders.Receipt{Via: delivery.Via, FallbackReason: delivery.FallbackReason}
+	if !claimed {
+		if delivery.Status != reminders.DeliverySent {
+			// The reminder was edited or removed after it was loaded, or
+			// another attempt is still sending this run.
+			return
 		}
-		_ = s.store.SetNextRun(ctx, r.ID, next)
+		log.Printf("reminder %d already delivered for %s; advancing", r.ID, r.NextRun.Format(time.RFC3339))
+	} else if receipt, err = s.sender(r); err != nil {
+		log.Printf("send error for reminder %d: %v", r.ID, err)
+		metrics.ReminderSends.Inc("failed")
+		s.recordFailure(ctx, r, delivery, err, now)
+		return
+	}
+	if claimed {
+		metrics.ReminderSends.Inc("sent")
+	}
+	if receipt.FallbackReason != "" {
+		log.Printf("reminder %d sent by DM instead: %s", r.ID, receipt.FallbackReason)
+	}
+
+	if r.LastRun {
+		log.Printf("reminder %d delivered its last run; retiring", r.ID)
+	}
+	if err := s.store.FinishDelivery(ctx, delivery, receipt, next, repeat); err != nil {
+		log.Printf("finish delivery error for reminder %d: %v", r.ID, err)
+	}
+}
+
+// catchUp applies the reminder's catch-up policy when it missed runs while
+// the bot was offline. It returns the reminder to send, or false when
+// nothing should be sent this tick.
+func (s *Scheduler) catchUp(ctx context.Context, r reminders.Reminder, now time.Time) (reminders.Reminder, bool) {
+	plan, late, err := reminders.PlanCatchUp(r, now)
+	if err != nil {
+		// Let the normal reschedule path handle broken schedules.
+		log.Printf("catch-up error for reminder %d: %v", r.ID, err)
+		return r, true
+	}
+	if !late {
+		return r, true
+	}
+	moved, err := s.store.CatchUp(ctx, r, plan.NextRun, plan.Missed)
+	if err != nil {
+		log.Printf("catch-up error for reminder %d: %v", r.ID, err)
+		return r, false
+	}
+	if !moved {
+		// The reminder was edited or removed after it was loaded.
+		return r, false
+	}
+	log.Printf("reminder %d missed %d runs (%s); continuing at %s", r.ID, plan.Missed, r.CatchUp, plan.NextRun.Format(time.RFC3339))
+	r.NextRun = plan.NextRun
+	r.MissedRuns = plan.Missed
+	return r, plan.Send
+}
+
+// recordFailure backs a reminder off after a failed send, or marks it dead
+// and notifies the owner once it has failed maxSendFailures times in a row.
+func (s *Scheduler) recordFailure(ctx context.Context, r reminders.Reminder, delivery reminders.Delivery, sendErr error, now time.Time) {
+	failure := reminders.DeliveryFailure{
+		Error:        sendErr.Error(),
+		FailureCount: r.FailureCount + 1,
+	}
+	if failure.FailureCount >= maxSendFailures {
+		failure.DeadAt = now
+	} else {
+		failure.RetryAt = now.Add(retryDelay(failure.FailureCount))
+	}
+	if err := s.store.FailDelivery(ctx, delivery, failure); err != nil {
+		log.Printf("record failed delivery error for reminder %d: %v", r.ID, err)
+		return
+	}
+	if failure.DeadAt.IsZero() {
+		return
+	}
+
+	metrics.ReminderSends.Inc("dead")
+	log.Printf("reminder %d marked dead after %d failed sends: %v", r.ID, failure.FailureCount, sendErr)
+	if s.deadLetter == nil {
+		return
+	}
+	r.FailureCount = failure.FailureCount
+	r.LastError = failure.Error
+	r.DeadAt = failure.DeadAt
+	if err := s.deadLetter(r); err != nil {
+		log.Printf("dead letter notice error for reminder %d: %v", r.ID, err)
+	}
+}
+
+// retryDelay returns the backoff before attempt failures+1.
+func retryDelay(failures int64) time.Duration {
+	delay := retryBaseDelay
+	for i := int64(1); i < failures && delay < retryMaxDelay; i++ {
+		delay *= 2
 	}
+	return min(delay, retryMaxDelay)
 }
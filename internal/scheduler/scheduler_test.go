@@ -19,6 +19,9 @@ func openTestDB(t *testing.T) *sql.DB {
 	if err != nil {
 		t.Fatal(err)
 	}
+	// Every connection to :memory: is a separate database; keep one so
+	// transactions see the same tables.
+	db.SetMaxOpenConns(1)
 	_, err = db.Exec(`CREATE TABLE reminders (
         id INTEGER PRIMARY KEY AUTOINCREMENT,
         user_id TEXT NOT NULL,
@@ -32,8 +35,43 @@ func openTestDB(t *testing.T) *sql.DB {
         timezone TEXT NOT NULL DEFAULT 'UTC',
         next_run INTEGER NOT NULL,
         created_at INTEGER NOT NULL,
-        updated_at INTEGER NOT NULL
-    );`)
+        updated_at INTEGER NOT NULL,
+        completed_at INTEGER,
+        failure_count INTEGER NOT NULL DEFAULT 0,
+        retry_at INTEGER,
+        last_error TEXT NOT NULL DEFAULT '',
+        dead_at INTEGER,
+        catch_up TEXT NOT NULL DEFAULT 'once',
+        missed_runs INTEGER NOT NULL DEFAULT 0,
+        paused INTEGER NOT NULL DEFAULT 0,
+        paused_until INTEGER,
+        ends_at INTEGER,
+        remaining_runs INTEGER,
+        mentions TEXT NOT NULL DEFAULT '',
+        delivery_mode TEXT NOT NULL DEFAULT 'channel',
+        run_count INTEGER NOT NULL DEFAULT 0,
+        shared INTEGER NOT NULL DEFAULT 0
+    );
+    CREATE TABLE reminder_subscribers (
+        reminder_id INTEGER NOT NULL,
+        user_id TEXT NOT NULL,
+        created_at INTEGER NOT NULL,
+        PRIMARY KEY (reminder_id, user_id)
+    );
+    CREATE TABLE reminder_deliveries (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        reminder_id INTEGER NOT NULL,
+        scheduled_for INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        error TEXT NOT NULL DEFAULT '',
+        created_at INTEGER NOT NULL,
+        updated_at INTEGER NOT NULL,
+        missed_runs INTEGER NOT NULL DEFAULT 0,
+        delivered_via TEXT NOT NULL DEFAULT '',
+        fallback_reason TEXT NOT NULL DEFAULT ''
+    );
+    CREATE UNIQUE INDEX idx_reminder_deliveries_reminder_scheduled ON reminder_deliveries(reminder_id, scheduled_for);`)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -50,12 +88,12 @@ func TestSchedulerSendsAndReschedules(t *testing.T) {
 	}
 
 	var sent int32
-	s := New(store, func(reminder reminders.Reminder) error {
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
 		if reminder.UserID != "u" || reminder.ChannelID != "c" || reminder.Message != "msg" {
 			t.Fatalf("unexpected reminder payload: %+v", reminder)
 		}
 		atomic.AddInt32(&sent, 1)
-		return nil
+		return reminders.Receipt{}, nil
 	}, 10*time.Millisecond)
 
 	s.runOnce(context.Background(), now.Add(time.Second))
@@ -73,17 +111,446 @@ func TestSchedulerKeepsReminderDueWhenSendFails(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	s := New(store, func(reminder reminders.Reminder) error {
-		return errors.New("send failed")
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		return reminders.Receipt{}, errors.New("send failed")
 	}, 10*time.Millisecond)
 
 	s.runOnce(context.Background(), now.Add(time.Second))
 
-	due, err := store.Due(context.Background(), now.Add(time.Second), 10)
+	due, err := store.Due(context.Background(), now.Add(time.Second+retryDelay(1)), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(due) != 1 || due[0].ID != r.ID {
-		t.Fatalf("expected reminder to remain due after send failure, got %+v", due)
+		t.Fatalf("expected reminder to be due again after backoff, got %+v", due)
+	}
+}
+
+func TestSchedulerCompletesOneTimeReminder(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	now := time.Now().UTC()
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleOnce, Once: true, Timezone: "UTC", NextRun: now}
+	if err := store.Create(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent int32
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		atomic.AddInt32(&sent, 1)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	s.runOnce(context.Background(), now.Add(time.Second))
+	s.runOnce(context.Background(), now.Add(2*time.Second))
+	if got := atomic.LoadInt32(&sent); got != 1 {
+		t.Fatalf("sent = %d, want 1", got)
+	}
+	if _, ok, err := store.GetOwned(context.Background(), r.ID, "u"); err != nil || !ok {
+		t.Fatalf("completed reminder should be kept for snoozing: ok=%v err=%v", ok, err)
+	}
+
+	s.runOnce(context.Background(), now.Add(completedRetention+time.Minute))
+	if _, ok, err := store.GetOwned(context.Background(), r.ID, "u"); err != nil || ok {
+		t.Fatalf("completed reminder should be purged after retention: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestSchedulerRecordsDeliveries(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleHourly, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	fail := true
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		if fail {
+			return reminders.Receipt{}, errors.New("missing access")
+		}
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	s.runOnce(ctx, now.Add(time.Second))
+	fail = false
+	s.runOnce(ctx, now.Add(time.Second+retryDelay(1)))
+
+	deliveries, err := store.ListDeliveries(ctx, r.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %+v, want one row for the scheduled run", deliveries)
+	}
+	d := deliveries[0]
+	if d.Status != reminders.DeliverySent || d.Attempts != 2 || !d.ScheduledFor.Equal(now) {
+		t.Fatalf("delivery = %+v, want sent after 2 attempts for %v", d, now)
+	}
+}
+
+func TestSchedulerSkipsRunAlreadyDelivered(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleHourly, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash after the send was recorded but before the reminder
+	// was rescheduled: the ledger says sent, the reminder is still due.
+	_, err := db.Exec(`INSERT INTO reminder_deliveries(reminder_id, scheduled_for, status, attempts, error, created_at, updated_at) VALUES(?, ?, 'sent', 1, '', ?, ?)`,
+		r.ID, now.Unix(), now.Unix(), now.Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent int32
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		atomic.AddInt32(&sent, 1)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+	s.runOnce(ctx, now.Add(time.Second))
+
+	if got := atomic.LoadInt32(&sent); got != 0 {
+		t.Fatalf("sent = %d, want 0 for an already delivered run", got)
+	}
+	due, err := store.Due(ctx, now.Add(time.Second), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("reminder should have been advanced past the delivered run, got %+v", due)
+	}
+}
+
+func TestSchedulerBacksOffAndDeadLettersFailingReminder(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleHourly, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts int32
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		atomic.AddInt32(&attempts, 1)
+		return reminders.Receipt{}, errors.New("Unknown Channel")
+	}, 10*time.Millisecond)
+	var dead []reminders.Reminder
+	s.SetDeadLetterSender(func(reminder reminders.Reminder) error {
+		dead = append(dead, reminder)
+		return nil
+	})
+
+	at := now.Add(time.Second)
+	s.runOnce(ctx, at)
+	// Still backing off: another tick right away must not resend.
+	s.runOnce(ctx, at.Add(time.Second))
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("attempts = %d, want 1 while backing off", got)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list: %+v err=%v", list, err)
+	}
+	if list[0].FailureCount != 1 || !list[0].RetryAt.Equal(at.Add(retryDelay(1))) || list[0].LastError != "Unknown Channel" {
+		t.Fatalf("after first failure reminder = %+v", list[0])
+	}
+
+	for range maxSendFailures - 1 {
+		at = at.Add(retryMaxDelay)
+		s.runOnce(ctx, at)
+	}
+	if got := atomic.LoadInt32(&attempts); got != maxSendFailures {
+		t.Fatalf("attempts = %d, want %d", got, maxSendFailures)
+	}
+	if len(dead) != 1 || dead[0].ID != r.ID || dead[0].LastError != "Unknown Channel" {
+		t.Fatalf("dead letters = %+v, want one for reminder %d", dead, r.ID)
+	}
+
+	s.runOnce(ctx, at.Add(retryMaxDelay))
+	if got := atomic.LoadInt32(&attempts); got != maxSendFailures {
+		t.Fatalf("dead reminder was retried: attempts = %d", got)
+	}
+	list, err = store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 || !list[0].Dead() {
+		t.Fatalf("dead reminder should still be listed: %+v err=%v", list, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Minute},
+		{failures: 2, want: 2 * time.Minute},
+		{failures: 4, want: 8 * time.Minute},
+		{failures: 20, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.failures); got != tt.want {
+			t.Fatalf("retryDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulerCatchUpAfterDowntime(t *testing.T) {
+	tests := []struct {
+		policy     reminders.CatchUpPolicy
+		wantMissed []int64 // MissedRuns of each message sent, in order
+	}{
+		{policy: reminders.CatchUpOnce, wantMissed: []int64{7}},
+		{policy: reminders.CatchUpEach, wantMissed: []int64{3, 0, 0, 0, 0}},
+		{policy: reminders.CatchUpSkip},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			db := openTestDB(t)
+			store := reminders.NewStore(db)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Hour)
+			start := now.Add(-7 * time.Hour)
+			r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: start, CatchUp: tt.policy}
+			if err := store.Create(ctx, r); err != nil {
+				t.Fatal(err)
+			}
+
+			var sent []reminders.Reminder
+			s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+				sent = append(sent, reminder)
+				return reminders.Receipt{}, nil
+			}, 10*time.Millisecond)
+			for i := 0; i < 8; i++ {
+				s.runOnce(ctx, now.Add(time.Minute))
+			}
+
+			if len(sent) != len(tt.wantMissed) {
+				t.Fatalf("sent %d messages, want %d", len(sent), len(tt.wantMissed))
+			}
+			for i, want := range tt.wantMissed {
+				if sent[i].MissedRuns != want {
+					t.Fatalf("message %d missed runs = %d, want %d", i, sent[i].MissedRuns, want)
+				}
+			}
+
+			list, err := store.ListByUser(ctx, "u")
+			if err != nil || len(list) != 1 {
+				t.Fatalf("list: %+v err=%v", list, err)
+			}
+			if want := now.Add(time.Hour); !list[0].NextRun.Equal(want) {
+				t.Fatalf("next run = %s, want %s", list[0].NextRun, want)
+			}
+			if tt.policy == reminders.CatchUpSkip && list[0].MissedRuns != 8 {
+				t.Fatalf("skip should record 8 missed runs, got %d", list[0].MissedRuns)
+			}
+		})
+	}
+}
+
+func TestSchedulerResumesPausedReminderAtPausedUntil(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Hour)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now.Add(time.Hour)}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	until := now.Add(5*time.Hour + 30*time.Minute)
+	if ok, err := store.Pause(ctx, r.ID, "u", until); err != nil || !ok {
+		t.Fatalf("pause: ok=%v err=%v", ok, err)
+	}
+
+	var sent []reminders.Reminder
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		sent = append(sent, reminder)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	s.runOnce(ctx, now.Add(3*time.Hour))
+	if len(sent) != 0 {
+		t.Fatalf("paused reminder was sent: %+v", sent)
+	}
+
+	s.runOnce(ctx, until)
+	if len(sent) != 0 {
+		t.Fatalf("resumed reminder should not catch up on paused runs: %+v", sent)
+	}
+	list, err := store.ListByUser(ctx, "u")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list: %+v err=%v", list, err)
+	}
+	if list[0].Paused || !list[0].NextRun.Equal(now.Add(6*time.Hour)) {
+		t.Fatalf("resumed reminder = %+v, want next run %s", list[0], now.Add(6*time.Hour))
+	}
+
+	s.runOnce(ctx, now.Add(6*time.Hour))
+	if len(sent) != 1 || sent[0].MissedRuns != 0 {
+		t.Fatalf("sent = %+v, want one on-time delivery", sent)
+	}
+}
+
+func TestSchedulerRetiresReminderAfterRemainingRuns(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Hour)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now.Add(time.Hour),
+		RemainingRuns: sql.NullInt64{Int64: 2, Valid: true}}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []reminders.Reminder
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		sent = append(sent, reminder)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	s.runOnce(ctx, now.Add(time.Hour))
+	s.runOnce(ctx, now.Add(2*time.Hour))
+	s.runOnce(ctx, now.Add(3*time.Hour))
+	if len(sent) != 2 {
+		t.Fatalf("sent %d reminders, want 2", len(sent))
+	}
+	if sent[0].LastRun || !sent[1].LastRun {
+		t.Fatalf("last run flags = %v, %v; want false, true", sent[0].LastRun, sent[1].LastRun)
+	}
+	if sent[0].RunCount != 0 || sent[1].RunCount != 1 {
+		t.Fatalf("run counts = %d, %d; want 0, 1", sent[0].RunCount, sent[1].RunCount)
+	}
+	if list, err := store.ListByUser(ctx, "u"); err != nil || len(list) != 0 {
+		t.Fatalf("active reminders = %+v err=%v, want none", list, err)
+	}
+}
+
+func TestSchedulerRetiresReminderAtEndsAt(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Hour)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleCron, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now.Add(time.Hour),
+		EndsAt: now.Add(2*time.Hour + 30*time.Minute), CatchUp: reminders.CatchUpEach}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []reminders.Reminder
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		sent = append(sent, reminder)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	// Downtime past the end date: only runs up to ends_at are caught up.
+	for i := 0; i < 5; i++ {
+		s.runOnce(ctx, now.Add(5*time.Hour))
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d reminders, want 2", len(sent))
+	}
+	if !sent[1].LastRun || !sent[1].NextRun.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("final delivery = %+v, want last run at %s", sent[1], now.Add(2*time.Hour))
+	}
+	if list, err := store.ListByUser(ctx, "u"); err != nil || len(list) != 0 {
+		t.Fatalf("active reminders = %+v err=%v, want none", list, err)
+	}
+}
+
+func TestSchedulerRecordsDMFallback(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleOnce, Once: true, Timezone: "UTC", NextRun: now, DeliveryMode: reminders.DeliveryFallback}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		if reminder.DeliveryMode != reminders.DeliveryFallback {
+			t.Fatalf("delivery mode = %q, want fallback", reminder.DeliveryMode)
+		}
+		return reminders.Receipt{Via: reminders.DeliveryDM, FallbackReason: "could not post in <#c>: Unknown Channel"}, nil
+	}, 10*time.Millisecond)
+	s.runOnce(ctx, now.Add(time.Second))
+
+	deliveries, err := store.ListDeliveries(ctx, r.ID, 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("deliveries = %+v err=%v", deliveries, err)
+	}
+	d := deliveries[0]
+	if d.Status != reminders.DeliverySent || d.Via != reminders.DeliveryDM || d.FallbackReason == "" {
+		t.Fatalf("delivery = %+v, want sent by DM with a fallback reason", d)
+	}
+}
+
+func TestSchedulerLoadsSharedReminderSubscribers(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	r := &reminders.Reminder{UserID: "owner", ChannelID: "c", GuildID: sql.NullString{String: "g", Valid: true}, Message: "msg", Schedule: reminders.ScheduleHourly, CronExpr: "0 * * * *", Timezone: "UTC", NextRun: now, Shared: true}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, userID := range []string{"owner", "u1"} {
+		if _, err := store.Subscribe(ctx, r.ID, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sent []reminders.Reminder
+	s := New(store, func(reminder reminders.Reminder) (reminders.Receipt, error) {
+		sent = append(sent, reminder)
+		return reminders.Receipt{}, nil
+	}, 10*time.Millisecond)
+
+	s.runOnce(ctx, now.Add(time.Second))
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	if subs := sent[0].Subscribers; len(subs) != 2 || subs[0] != "owner" || subs[1] != "u1" {
+		t.Fatalf("subscribers = %v, want [owner u1]", subs)
+	}
+}
+
+func TestSchedulerStopWaitsForInFlightSend(t *testing.T) {
+	db := openTestDB(t)
+	store := reminders.NewStore(db)
+	r := &reminders.Reminder{UserID: "u", ChannelID: "c", Message: "msg", Schedule: reminders.ScheduleOnce, Once: true, Timezone: "UTC", NextRun: time.Now().UTC().Add(-time.Minute)}
+	if err := store.Create(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	s := New(store, func(reminders.Reminder) (reminders.Receipt, error) {
+		close(sending)
+		<-release
+		return reminders.Receipt{}, nil
+	}, 5*time.Millisecond)
+	s.Start(context.Background())
+	<-sending
+
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop while sending = %v, want deadline exceeded", err)
+	}
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	got, ok, err := store.GetOwned(context.Background(), r.ID, "u")
+	if err != nil || !ok || got.CompletedAt.IsZero() {
+		t.Fatalf("reminder after Stop = %+v ok=%v err=%v, want the send recorded as completed", got, ok, err)
 	}
 }
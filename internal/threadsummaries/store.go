@@ -0,0 +1,75 @@
+package threadsummaries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"mizubot-go/internal/data"
+)
+
+// Summary condenses a reply chain from its root up to and including
+// ThroughMessageID, the newest message it covers.
+type Summary struct {
+	RootID           string
+	ChannelID        string
+	ThroughMessageID string
+	Summary          string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type Store struct {
+	db *sql.DB
+	q  *data.Queries
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, q: data.New()}
+}
+
+// GetByThrough returns the summary that ends at messageID, if any.
+func (s *Store) GetByThrough(ctx context.Context, messageID string) (Summary, bool, error) {
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return Summary{}, false, nil
+	}
+	row, err := s.q.GetThreadSummaryByThrough(ctx, s.db, messageID)
+	if err == sql.ErrNoRows {
+		return Summary{}, false, nil
+	}
+	if err != nil {
+		return Summary{}, false, err
+	}
+	return convertSummary(row), true, nil
+}
+
+// Save replaces the summary kept for summary.RootID; a thread keeps only
+// its latest one.
+func (s *Store) Save(ctx context.Context, summary Summary) error {
+	if strings.TrimSpace(summary.RootID) == "" || strings.TrimSpace(summary.ThroughMessageID) == "" {
+		return errors.New("missing thread root or through message id")
+	}
+	now := time.Now().UTC().Unix()
+	return s.q.UpsertThreadSummary(ctx, s.db, data.UpsertThreadSummaryParams{
+		RootID:           summary.RootID,
+		ChannelID:        summary.ChannelID,
+		ThroughMessageID: summary.ThroughMessageID,
+		Summary:          strings.TrimSpace(summary.Summary),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+}
+
+func convertSummary(row data.LlmThreadSummary) Summary {
+	return Summary{
+		RootID:           row.RootID,
+		ChannelID:        row.ChannelID,
+		ThroughMessageID: row.ThroughMessageID,
+		Summary:          row.Summary,
+		CreatedAt:        time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:        time.Unix(row.UpdatedAt, 0).UTC(),
+	}
+}
@@ -0,0 +1,54 @@
+package threadsummaries
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE llm_thread_summaries (
+		root_id TEXT NOT NULL PRIMARY KEY,
+		channel_id TEXT NOT NULL,
+		through_message_id TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestStoreKeepsLatestSummaryPerRoot(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	store := NewStore(db)
+	if err := store.Save(ctx, Summary{RootID: "root", ChannelID: "c", ThroughMessageID: "m5", Summary: "planned a trip"}); err != nil {
+		t.Fatalf("Save first: %v", err)
+	}
+	if err := store.Save(ctx, Summary{RootID: "root", ChannelID: "c", ThroughMessageID: "m7", Summary: "planned a trip to Kyoto"}); err != nil {
+		t.Fatalf("Save second: %v", err)
+	}
+
+	if _, ok, err := store.GetByThrough(ctx, "m5"); err != nil || ok {
+		t.Fatalf("GetByThrough(m5) = %v, %v; want replaced", ok, err)
+	}
+	got, ok, err := store.GetByThrough(ctx, "m7")
+	if err != nil || !ok {
+		t.Fatalf("GetByThrough(m7) = %v, %v", ok, err)
+	}
+	if got.RootID != "root" || got.Summary != "planned a trip to Kyoto" {
+		t.Fatalf("summary = %+v", got)
+	}
+}
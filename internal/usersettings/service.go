@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"mizubot-go/internal/reminders"
 )
 
 const DefaultTimezone = "UTC"
@@ -75,12 +77,42 @@ func (s *Service) GetTimezone(ctx context.Context, userID string) (string, bool,
 	if err != nil {
 		return "", false, err
 	}
-	if !ok {
+	if !ok || settings.Timezone == "" {
 		return DefaultTimezone, false, nil
 	}
 	return settings.Timezone, true, nil
 }
 
+// GetDeliveryMode returns the user's default reminder delivery mode, or
+// channel delivery when none is set.
+func (s *Service) GetDeliveryMode(ctx context.Context, userID string) (reminders.DeliveryMode, bool, error) {
+	if strings.TrimSpace(userID) == "" {
+		return "", false, errors.New("missing user id")
+	}
+	settings, ok, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return "", false, err
+	}
+	if !ok || settings.DeliveryMode == "" {
+		return reminders.DeliveryChannel, false, nil
+	}
+	return reminders.DeliveryMode(settings.DeliveryMode), true, nil
+}
+
+func (s *Service) SetDeliveryMode(ctx context.Context, userID, mode string) (Settings, error) {
+	if strings.TrimSpace(userID) == "" {
+		return Settings{}, errors.New("missing user id")
+	}
+	if strings.TrimSpace(mode) == "" {
+		return Settings{}, errors.New("delivery mode is required")
+	}
+	parsed, err := reminders.ParseDeliveryMode(mode)
+	if err != nil {
+		return Settings{}, err
+	}
+	return s.store.SetDeliveryMode(ctx, userID, string(parsed))
+}
+
 func (s *Service) SetTimezone(ctx context.Context, userID, timezone string) (Settings, error) {
 	if strings.TrimSpace(userID) == "" {
 		return Settings{}, errors.New("missing user id")
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"testing"
 
+	"mizubot-go/internal/reminders"
+
 	_ "modernc.org/sqlite"
 )
 
@@ -18,7 +20,8 @@ func testDB(t *testing.T) *sql.DB {
 		user_id TEXT NOT NULL PRIMARY KEY,
 		timezone TEXT NOT NULL,
 		created_at INTEGER NOT NULL,
-		updated_at INTEGER NOT NULL
+		updated_at INTEGER NOT NULL,
+		delivery_mode TEXT NOT NULL DEFAULT ''
 	)`)
 	if err != nil {
 		t.Fatal(err)
@@ -91,3 +94,37 @@ func TestSetTimezoneRejectsInvalidTimezone(t *testing.T) {
 		t.Fatalf("expected invalid timezone error")
 	}
 }
+
+func TestDeliveryModeSettings(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	service := NewService(NewStore(db))
+
+	mode, configured, err := service.GetDeliveryMode(ctx, "u")
+	if err != nil || configured || mode != reminders.DeliveryChannel {
+		t.Fatalf("GetDeliveryMode = %q, %v, %v; want channel, false", mode, configured, err)
+	}
+	if _, err := service.SetDeliveryMode(ctx, "u", "carrier pigeon"); err == nil {
+		t.Fatalf("expected error for invalid delivery mode")
+	}
+	if _, err := service.SetDeliveryMode(ctx, "u", "fallback"); err != nil {
+		t.Fatalf("SetDeliveryMode: %v", err)
+	}
+	mode, configured, err = service.GetDeliveryMode(ctx, "u")
+	if err != nil || !configured || mode != reminders.DeliveryFallback {
+		t.Fatalf("GetDeliveryMode = %q, %v, %v; want fallback, true", mode, configured, err)
+	}
+
+	// Saving only a delivery mode must not count as a configured timezone.
+	timezone, configured, err := service.GetTimezone(ctx, "u")
+	if err != nil || configured || timezone != DefaultTimezone {
+		t.Fatalf("GetTimezone = %q, %v, %v; want default, false", timezone, configured, err)
+	}
+	if _, err := service.SetTimezone(ctx, "u", "Asia/Tokyo"); err != nil {
+		t.Fatalf("SetTimezone: %v", err)
+	}
+	if mode, _, _ := service.GetDeliveryMode(ctx, "u"); mode != reminders.DeliveryFallback {
+		t.Fatalf("delivery mode after SetTimezone = %q, want fallback", mode)
+	}
+}
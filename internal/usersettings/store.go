@@ -9,10 +9,11 @@ import (
 )
 
 type Settings struct {
-	UserID    string
-	Timezone  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	UserID       string
+	Timezone     string // empty when only other settings were saved
+	DeliveryMode string // default reminder delivery mode; empty for channel
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type Store struct {
@@ -49,11 +50,26 @@ func (s *Store) SetTimezone(ctx context.Context, userID, timezone string) (Setti
 	return convertSettings(row), nil
 }
 
+func (s *Store) SetDeliveryMode(ctx context.Context, userID, mode string) (Settings, error) {
+	now := time.Now().UTC()
+	row, err := s.q.UpsertUserDeliveryMode(ctx, s.db, data.UpsertUserDeliveryModeParams{
+		UserID:       userID,
+		DeliveryMode: mode,
+		CreatedAt:    now.Unix(),
+		UpdatedAt:    now.Unix(),
+	})
+	if err != nil {
+		return Settings{}, err
+	}
+	return convertSettings(row), nil
+}
+
 func convertSettings(row data.UserSetting) Settings {
 	return Settings{
-		UserID:    row.UserID,
-		Timezone:  row.Timezone,
-		CreatedAt: time.Unix(row.CreatedAt, 0).UTC(),
-		UpdatedAt: time.Unix(row.UpdatedAt, 0).UTC(),
+		UserID:       row.UserID,
+		Timezone:     row.Timezone,
+		DeliveryMode: row.DeliveryMode,
+		CreatedAt:    time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:    time.Unix(row.UpdatedAt, 0).UTC(),
 	}
 }